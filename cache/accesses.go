@@ -0,0 +1,72 @@
+package cache
+
+import "sync"
+
+// Batched access recording (Config.BatchAccesses).
+//
+// A hit normally bumps the node's frequency with a CAS and stamps its access
+// time, so every reader of a hot key writes to the same cache lines. With
+// batching, a hit only appends the node to a buffer taken from a sync.Pool,
+// which keeps one buffer per P while it is not in use, so appends don't
+// contend. A full buffer is applied at once (BP-Wrapper style): repeated hits to
+// the same node collapse into a single frequency bump of the combined count and
+// one access stamp. Buffers the pool drops at a GC lose their pending hits,
+// which only makes the frequencies slightly lower (under the race detector the
+// pool drops buffers at random, so few hits are recorded at all).
+
+// accessBatchSize is how many hits a buffer collects before they are applied
+const accessBatchSize = 64
+
+// accessBuffer holds hits that haven't been recorded yet
+type accessBuffer[K Key, V any] struct {
+	shards [accessBatchSize]*shard[K, V]
+	nodes  [accessBatchSize]*recordNode[K, V]
+	n      int
+}
+
+// newAccessBuffers returns the pool hits are buffered in
+func newAccessBuffers[K Key, V any]() *sync.Pool {
+	return &sync.Pool{New: func() any { return new(accessBuffer[K, V]) }}
+}
+
+// bufferAccess records a hit on node later, applying the buffer once it is full
+func (c *CloxCache[K, V]) bufferAccess(shard *shard[K, V], node *recordNode[K, V]) {
+	buf := c.accessBuffers.Get().(*accessBuffer[K, V])
+	buf.shards[buf.n] = shard
+	buf.nodes[buf.n] = node
+	buf.n++
+	if buf.n == accessBatchSize {
+		c.applyAccesses(buf)
+	}
+	c.accessBuffers.Put(buf)
+}
+
+// applyAccesses records the buffered hits, once per distinct node, and empties
+// the buffer. Nodes that left the cache meanwhile are skipped: removal zeroes
+// their frequency and ghosting negates it.
+func (c *CloxCache[K, V]) applyAccesses(buf *accessBuffer[K, V]) {
+	for i := range buf.n {
+		node := buf.nodes[i]
+		if node == nil {
+			continue // counted with an earlier hit
+		}
+		hits := int32(1)
+		for j := i + 1; j < buf.n; j++ {
+			if buf.nodes[j] == node {
+				buf.nodes[j] = nil
+				hits++
+			}
+		}
+		if f := node.freq.Load(); f > 0 {
+			shard := buf.shards[i]
+			c.recordHits(shard, node, f, hits)
+			// recordHits counted one access toward the frequency window
+			for range min(hits, maxFrequency) - 1 {
+				c.touchRecent(shard, node)
+			}
+		}
+	}
+	clear(buf.shards[:buf.n])
+	clear(buf.nodes[:buf.n])
+	buf.n = 0
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheApplyAccesses(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64, BatchAccesses: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	node := func(key string) *recordNode[string, int] {
+		hash, hi := cache.hashes(key)
+		shard, _ := cache.locate(hash)
+		return cache.findNode(shard, hash, hi, key, nil)
+	}
+	for _, key := range []string{"hot", "warm", "gone"} {
+		cache.Put(key, 0)
+	}
+	hot, warm, gone := node("hot"), node("warm"), node("gone")
+	cache.Delete("gone")
+
+	shard := &cache.shards[0]
+	buf := new(accessBuffer[string, int])
+	for _, n := range []*recordNode[string, int]{hot, warm, hot, gone, hot} {
+		buf.shards[buf.n], buf.nodes[buf.n] = shard, n
+		buf.n++
+	}
+	before := hot.lastAccess.Load()
+	cache.applyAccesses(buf)
+
+	if f := hot.freq.Load(); f != initialFreq+3 {
+		t.Errorf("hot freq = %d, want %d (three hits in one bump)", f, initialFreq+3)
+	}
+	if f := warm.freq.Load(); f != initialFreq+1 {
+		t.Errorf("warm freq = %d, want %d", f, initialFreq+1)
+	}
+	if f := gone.freq.Load(); f != 0 {
+		t.Errorf("Removed node's freq = %d, want 0", f)
+	}
+	if hot.lastAccess.Load() == before {
+		t.Error("Access time wasn't refreshed")
+	}
+	if buf.n != 0 || buf.nodes[0] != nil {
+		t.Error("Buffer wasn't emptied")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheBatchAccesses(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 256, Capacity: 512, BatchAccesses: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 * accessBatchSize {
+				key := fmt.Sprintf("key-%d", (g+i)%10)
+				if v, ok := cache.Get(key); !ok || v != (g+i)%10 {
+					t.Errorf("Get(%s) = %d, %v", key, v, ok)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheBatchAccessesApplied(t *testing.T) {
+	// The race detector makes sync.Pool drop buffers at random, and their hits with them
+	var pool sync.Pool
+	for range 100 {
+		buf := new(accessBuffer[string, int])
+		pool.Put(buf)
+		if pool.Get() != buf {
+			t.Skip("sync.Pool doesn't keep buffers in this build")
+		}
+	}
+
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64, BatchAccesses: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+	cache.Put("hot", 1)
+	hash, hi := cache.hashes("hot")
+	shard, _ := cache.locate(hash)
+	node := cache.findNode(shard, hash, hi, "hot", nil)
+
+	// Hits reach the frequency once their batch is applied
+	for range accessBatchSize - 1 {
+		cache.Get("hot")
+	}
+	if f := node.freq.Load(); f != initialFreq {
+		t.Errorf("freq = %d before the batch filled, want %d", f, initialFreq)
+	}
+	cache.Get("hot")
+	if f := node.freq.Load(); f != maxFrequency {
+		t.Errorf("freq = %d after a full batch, want %d", f, maxFrequency)
+	}
+}
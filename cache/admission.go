@@ -0,0 +1,93 @@
+package cache
+
+import "sync/atomic"
+
+// Victim-comparison admission (Config.Admission).
+//
+// A count-min sketch estimates how often every key is requested, including keys
+// that are not cached. When an insert needs an eviction, the prospective victim's
+// estimate is compared with the incoming key's, and the insert is rejected if the
+// victim is requested more often: one-hit wonders then can't push out entries that
+// keep being used. This is the admission half of TinyLFU; re-inserts of ghosts are
+// always admitted since they don't need an eviction.
+
+const (
+	sketchDepth      = 4  // rows (hash functions) in the sketch
+	sketchMaxCount   = 15 // 4-bit counters
+	sketchSampleMult = 10 // counters are halved after this many additions per entry of capacity
+)
+
+// frequencySketch is a count-min sketch of 4-bit counters, 16 per word.
+// Counters are halved periodically so estimates reflect recent popularity.
+type frequencySketch struct {
+	table      []atomic.Uint64
+	mask       uint64
+	additions  atomic.Int64
+	sampleSize int64
+}
+
+// newFrequencySketch sizes a sketch for a cache holding capacity entries
+func newFrequencySketch(capacity int) *frequencySketch {
+	words := nextPowerOf2(max(capacity/4, 16))
+	return &frequencySketch{
+		table:      make([]atomic.Uint64, words),
+		mask:       uint64(words - 1),
+		sampleSize: int64(max(capacity, 1)) * sketchSampleMult,
+	}
+}
+
+// slot returns the word index and nibble shift of a key's counter in one row
+func (s *frequencySketch) slot(hash uint64, row int) (int, uint) {
+	h := (hash + uint64(row)*0x9e3779b97f4a7c15) * 0xbf58476d1ce4e5b9
+	h ^= h >> 31
+	return int(h & s.mask), uint(h>>60) * 4
+}
+
+// increment records one access to the key with the given hash (lossy under contention)
+func (s *frequencySketch) increment(hash uint64) {
+	for row := range sketchDepth {
+		i, shift := s.slot(hash, row)
+		w := s.table[i].Load()
+		if (w>>shift)&sketchMaxCount < sketchMaxCount {
+			s.table[i].CompareAndSwap(w, w+1<<shift)
+		}
+	}
+	if s.additions.Add(1) == s.sampleSize {
+		s.reset()
+	}
+}
+
+// estimate returns the approximate access count of the key with the given hash
+func (s *frequencySketch) estimate(hash uint64) int32 {
+	est := uint64(sketchMaxCount)
+	for row := range sketchDepth {
+		i, shift := s.slot(hash, row)
+		est = min(est, (s.table[i].Load()>>shift)&sketchMaxCount)
+	}
+	return int32(est)
+}
+
+// reset halves every counter so old popularity fades
+func (s *frequencySketch) reset() {
+	for i := range s.table {
+		for {
+			w := s.table[i].Load()
+			if s.table[i].CompareAndSwap(w, (w>>1)&0x7777777777777777) {
+				break
+			}
+		}
+	}
+	s.additions.Store(0)
+}
+
+// admits reports whether an insert with the given sketch estimate may evict victim.
+// candidate < 0 means admission is disabled.
+func (c *CloxCache[K, V]) admits(candidate int32, victim *recordNode[K, V]) bool {
+	return candidate < 0 || c.sketch.estimate(victim.keyHash) <= candidate
+}
+
+// AdmissionRejections returns how many inserts were rejected because their
+// prospective victim was requested more often (always 0 without Config.Admission)
+func (c *CloxCache[K, V]) AdmissionRejections() uint64 {
+	return c.Rejections(RejectAdmission)
+}
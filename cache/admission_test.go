@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFrequencySketch(t *testing.T) {
+	s := newFrequencySketch(1024)
+
+	for range 5 {
+		s.increment(hashKey("hot"))
+	}
+	s.increment(hashKey("warm"))
+
+	if est := s.estimate(hashKey("hot")); est != 5 {
+		t.Errorf("estimate(hot) = %d, want 5", est)
+	}
+	if est := s.estimate(hashKey("warm")); est != 1 {
+		t.Errorf("estimate(warm) = %d, want 1", est)
+	}
+	if est := s.estimate(hashKey("cold")); est != 0 {
+		t.Errorf("estimate(cold) = %d, want 0", est)
+	}
+
+	for range 100 {
+		s.increment(hashKey("hot"))
+	}
+	if est := s.estimate(hashKey("hot")); est != sketchMaxCount {
+		t.Errorf("estimate(hot) = %d, want saturation at %d", est, sketchMaxCount)
+	}
+
+	s.reset()
+	if est := s.estimate(hashKey("hot")); est != sketchMaxCount/2 {
+		t.Errorf("estimate(hot) after reset = %d, want %d", est, sketchMaxCount/2)
+	}
+}
+
+func TestCloxCacheAdmission(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, Admission: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 4 {
+		key := fmt.Sprintf("popular-%d", i)
+		cache.Put(key, i)
+		for range 5 {
+			cache.Get(key)
+		}
+	}
+
+	if cache.Put("one-hit", 0) {
+		t.Error("Expected a one-hit key to be rejected in favour of popular entries")
+	}
+	if _, ok := cache.Get("one-hit"); ok {
+		t.Error("Rejected key is in the cache")
+	}
+	if n := cache.AdmissionRejections(); n != 1 {
+		t.Errorf("AdmissionRejections = %d, want 1", n)
+	}
+
+	// A key that keeps being requested earns its place
+	for range 10 {
+		cache.Get("rising")
+	}
+	if !cache.Put("rising", 1) {
+		t.Error("Expected a frequently requested key to be admitted")
+	}
+	if _, ok := cache.Get("rising"); !ok {
+		t.Error("Admitted key is missing")
+	}
+}
+
+func TestCloxCacheAdmissionDisabled(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 4 {
+		key := fmt.Sprintf("popular-%d", i)
+		cache.Put(key, i)
+		for range 5 {
+			cache.Get(key)
+		}
+	}
+	if !cache.Put("one-hit", 0) {
+		t.Error("Put failed without admission")
+	}
+	if n := cache.AdmissionRejections(); n != 0 {
+		t.Errorf("AdmissionRejections = %d, want 0", n)
+	}
+}
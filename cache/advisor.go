@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Advisor thresholds
+const (
+	// advisorMaxAvgChain - average chain length (per occupied slot) above which slots are undersized
+	advisorMaxAvgChain = 2.0
+	// advisorGhostPromotionRate - fraction of ghosts re-inserted above which capacity is too small
+	advisorGhostPromotionRate = 0.30
+	// advisorProtectedEvictionRate - fraction of forced protected evictions above which the scan is too narrow
+	advisorProtectedEvictionRate = 0.25
+	// advisorShrinkHitRate / advisorShrinkFill - a cache this effective and this empty can shrink
+	advisorShrinkHitRate = 0.98
+	advisorShrinkFill    = 0.50
+)
+
+// WorkloadObservation summarizes how a cache has been behaving at runtime
+type WorkloadObservation struct {
+	Config                Config  // configuration the cache was built with
+	HitRate               float64 // hits / (hits + misses) in the current measurement window
+	Entries               int64   // live entries
+	Ghosts                int64   // ghost entries
+	AvgChainLength        float64 // average nodes per occupied slot (live and ghost)
+	MaxChainLength        int     // longest slot chain
+	GhostPromotionRate    float64 // fraction of ghosted entries later re-inserted
+	ProtectedEvictionRate float64 // fraction of evictions that had to evict a protected entry
+	AverageK              float64 // average protection threshold
+}
+
+// Recommendation is a suggested configuration with the reasoning behind each change
+type Recommendation struct {
+	Config  Config
+	Reasons []string
+}
+
+// Changed reports whether the recommendation differs from the observed config
+func (r Recommendation) Changed() bool {
+	return len(r.Reasons) > 0
+}
+
+// ObserveWorkload gathers the runtime statistics used by Advise.
+// Walks every slot, so it costs O(slots); call it periodically, not per request.
+func (c *CloxCache[K, V]) ObserveWorkload() WorkloadObservation {
+	obs := WorkloadObservation{
+		Config:   c.config,
+		AverageK: c.AverageK(),
+	}
+
+	var windowHits, windowOps, ghosted, promotions, unprotected, protected uint64
+	for i := range c.shards {
+		shard := &c.shards[i]
+		hits, ops := shard.window()
+		windowHits += hits
+		windowOps += ops
+		ghosted += shard.ghosted.Load()
+		promotions += shard.ghostPromotions.Load()
+		unprotected += shard.evictedUnprotected.Load()
+		protected += shard.evictedProtected.Load()
+		obs.Entries += shard.entryCount.Load()
+		obs.Ghosts += shard.ghostCount.Load()
+	}
+	if windowOps > 0 {
+		obs.HitRate = float64(windowHits) / float64(windowOps)
+	}
+	if ghosted > 0 {
+		obs.GhostPromotionRate = float64(promotions) / float64(ghosted)
+	}
+	if total := unprotected + protected; total > 0 {
+		obs.ProtectedEvictionRate = float64(protected) / float64(total)
+	}
+
+	chains := c.ChainStats()
+	obs.AvgChainLength = chains.AvgChain
+	obs.MaxChainLength = chains.MaxChain
+
+	return obs
+}
+
+// Advise recommends a configuration for the cache based on its observed workload
+func (c *CloxCache[K, V]) Advise() Recommendation {
+	return Advise(c.ObserveWorkload())
+}
+
+// Advise recommends a configuration for an observed workload.
+// Each adjustment is explained in Reasons; an empty Reasons means the config looks right.
+func Advise(obs WorkloadObservation) Recommendation {
+	cfg := obs.Config
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = cfg.NumShards * cfg.SlotsPerShard
+	}
+	rec := Recommendation{Config: cfg}
+	capacity := cfg.Capacity
+
+	// Capacity: ghosts coming back means we evicted entries that were still wanted
+	switch {
+	case obs.GhostPromotionRate > advisorGhostPromotionRate:
+		capacity = capacity * 3 / 2
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"%.0f%% of evicted entries were re-inserted while still remembered as ghosts; increase Capacity to %d",
+			obs.GhostPromotionRate*100, capacity))
+	case obs.HitRate > advisorShrinkHitRate && obs.Entries < int64(float64(capacity)*advisorShrinkFill):
+		capacity = max(int(obs.Entries)*2, cfg.NumShards)
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"hit rate is %.1f%% with only %d of %d entries in use; Capacity can shrink to %d",
+			obs.HitRate*100, obs.Entries, cfg.Capacity, capacity))
+	}
+	rec.Config.Capacity = capacity
+
+	// Shards: keep enough shards for write parallelism and short eviction scans
+	sized := ConfigFromCapacity(capacity)
+	if minShards := runtime.NumCPU() * 4; cfg.NumShards < minShards && sized.NumShards > cfg.NumShards {
+		rec.Config.NumShards = sized.NumShards
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"%d shards for %d CPUs causes write contention; use NumShards=%d",
+			cfg.NumShards, runtime.NumCPU(), sized.NumShards))
+	}
+
+	// Slots: ~3 slots per entry keeps chains short and leaves room for ghosts
+	slots := rec.Config.SlotsPerShard
+	if rec.Config.NumShards != cfg.NumShards || capacity != cfg.Capacity {
+		slots = max(slots, nextPowerOf2(capacity*3/rec.Config.NumShards))
+	}
+	if obs.AvgChainLength > advisorMaxAvgChain {
+		slots *= 2
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"average chain length is %.1f (max %d); double SlotsPerShard", obs.AvgChainLength, obs.MaxChainLength))
+	}
+	if slots*rec.Config.NumShards <= capacity {
+		slots = nextPowerOf2(capacity*2/rec.Config.NumShards + 1)
+		rec.Reasons = append(rec.Reasons,
+			"SlotsPerShard leaves no room for ghosts; frequency history is lost on every eviction")
+	}
+	rec.Config.SlotsPerShard = max(slots, 1)
+
+	// Sweep: frequent forced protected evictions mean the scan window found no unprotected victim
+	if obs.ProtectedEvictionRate > advisorProtectedEvictionRate && cfg.SweepPercent < 100 && !cfg.AdaptiveSweep {
+		rec.Config.SweepPercent = min(max(cfg.SweepPercent, 1)*2, 100)
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf(
+			"%.0f%% of evictions removed protected entries; widen SweepPercent to %d",
+			obs.ProtectedEvictionRate*100, rec.Config.SweepPercent))
+	}
+
+	return rec
+}
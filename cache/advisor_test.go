@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAdvise(t *testing.T) {
+	base := Config{NumShards: 1024, SlotsPerShard: 64, Capacity: 20000, SweepPercent: 15}
+
+	tests := []struct {
+		name   string
+		obs    WorkloadObservation
+		check  func(t *testing.T, cfg Config)
+		reason string
+	}{
+		{
+			name: "healthy",
+			obs:  WorkloadObservation{Config: base, HitRate: 0.8, Entries: 20000, AvgChainLength: 1.2},
+			check: func(t *testing.T, cfg Config) {
+				if cfg != base {
+					t.Errorf("Expected unchanged config, got %+v", cfg)
+				}
+			},
+		},
+		{
+			name:   "ghosts returning",
+			obs:    WorkloadObservation{Config: base, HitRate: 0.5, Entries: 20000, GhostPromotionRate: 0.6, AvgChainLength: 1.2},
+			reason: "increase Capacity",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Capacity != 30000 {
+					t.Errorf("Expected Capacity 30000, got %d", cfg.Capacity)
+				}
+				if cfg.NumShards*cfg.SlotsPerShard < cfg.Capacity*2 {
+					t.Errorf("Slots not grown with capacity: %+v", cfg)
+				}
+			},
+		},
+		{
+			name:   "oversized",
+			obs:    WorkloadObservation{Config: base, HitRate: 0.995, Entries: 2000, AvgChainLength: 1.1},
+			reason: "can shrink",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.Capacity != 4000 {
+					t.Errorf("Expected Capacity 4000, got %d", cfg.Capacity)
+				}
+			},
+		},
+		{
+			name:   "long chains",
+			obs:    WorkloadObservation{Config: base, HitRate: 0.8, Entries: 20000, AvgChainLength: 3.5, MaxChainLength: 9},
+			reason: "double SlotsPerShard",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.SlotsPerShard != 128 {
+					t.Errorf("Expected SlotsPerShard 128, got %d", cfg.SlotsPerShard)
+				}
+			},
+		},
+		{
+			name:   "narrow sweep",
+			obs:    WorkloadObservation{Config: base, HitRate: 0.8, Entries: 20000, AvgChainLength: 1.2, ProtectedEvictionRate: 0.5},
+			reason: "widen SweepPercent",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.SweepPercent != 30 {
+					t.Errorf("Expected SweepPercent 30, got %d", cfg.SweepPercent)
+				}
+			},
+		},
+		{
+			name: "adaptive sweep",
+			obs: WorkloadObservation{Config: Config{NumShards: 1024, SlotsPerShard: 64, Capacity: 20000, SweepPercent: 15, AdaptiveSweep: true},
+				HitRate: 0.8, Entries: 20000, AvgChainLength: 1.2, ProtectedEvictionRate: 0.5},
+			check: func(t *testing.T, cfg Config) {
+				if cfg.SweepPercent != 15 {
+					t.Errorf("Expected SweepPercent left to AdaptiveSweep, got %d", cfg.SweepPercent)
+				}
+			},
+		},
+		{
+			name:   "no ghost room",
+			obs:    WorkloadObservation{Config: Config{NumShards: 1024, SlotsPerShard: 16, Capacity: 16384, SweepPercent: 15}, HitRate: 0.8, AvgChainLength: 1.2},
+			reason: "no room for ghosts",
+			check: func(t *testing.T, cfg Config) {
+				if cfg.NumShards*cfg.SlotsPerShard <= cfg.Capacity {
+					t.Errorf("Expected slots beyond capacity, got %+v", cfg)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := Advise(tt.obs)
+			tt.check(t, rec.Config)
+
+			if tt.reason == "" {
+				if rec.Changed() {
+					t.Errorf("Unexpected reasons: %v", rec.Reasons)
+				}
+				return
+			}
+			if !strings.Contains(strings.Join(rec.Reasons, "\n"), tt.reason) {
+				t.Errorf("Expected a reason mentioning %q, got %v", tt.reason, rec.Reasons)
+			}
+		})
+	}
+}
+
+func TestCloxCacheObserveWorkload(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	// Re-insert every ghost: all evicted entries were still wanted
+	for _, g := range cache.Ghosts() {
+		cache.Put(g.Key, 0)
+	}
+
+	obs := cache.ObserveWorkload()
+	if obs.Entries != cache.shards[0].entryCount.Load() {
+		t.Errorf("Expected %d live entries, got %d", cache.shards[0].entryCount.Load(), obs.Entries)
+	}
+	if obs.GhostPromotionRate <= advisorGhostPromotionRate {
+		t.Errorf("Expected high ghost promotion rate, got %.2f", obs.GhostPromotionRate)
+	}
+	if obs.Config.Capacity != 4 || obs.Config.SweepPercent != 100 {
+		t.Errorf("Observation carries wrong config: %+v", obs.Config)
+	}
+
+	rec := cache.Advise()
+	if rec.Config.Capacity <= 4 {
+		t.Errorf("Expected advice to grow capacity, got %+v (%v)", rec.Config, rec.Reasons)
+	}
+}
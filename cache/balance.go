@@ -0,0 +1,36 @@
+package cache
+
+// Recency/frequency balance (Config.AdaptiveBalance).
+//
+// Like ARC's p parameter, each shard learns a target for how many of its entries
+// should be once-seen (recency portion, freq == initialFreq) rather than seen again
+// (frequency portion). A re-inserted ghost that had only been seen once means the
+// recency portion was too small, so the target grows; a re-inserted ghost that had
+// been seen repeatedly means the frequency portion was too small, so it shrinks.
+// Eviction then takes unprotected victims from whichever portion exceeds its
+// share. This tunes which unprotected entries go first, while k keeps tuning which
+// entries are protected at all.
+
+// balanceSteps is how many ghost hits it takes to move the target across the whole shard
+const balanceSteps = 64
+
+// ghostHit adapts the shard's recency target after a ghost with the remembered
+// frequency was re-inserted. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) ghostHit(shard *shard[K, V], remembered int32) {
+	if !c.config.AdaptiveBalance {
+		return
+	}
+	step := max(shard.capacity/balanceSteps, 1)
+	target := shard.recencyTarget.Load()
+	if remembered <= initialFreq {
+		target = min(target+step, shard.capacity)
+	} else {
+		target = max(target-step, 0)
+	}
+	shard.recencyTarget.Store(target)
+}
+
+// preferRecency reports whether eviction should take once-seen entries first
+func (c *CloxCache[K, V]) preferRecency(shard *shard[K, V]) bool {
+	return shard.entryCount.Load()-shard.protected.Load() > shard.recencyTarget.Load()
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fillBalanced inserts a and d once and b and c twice (in that order of last use)
+func fillBalanced(cache *CloxCache[string, int]) {
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	cache.Get("b")
+	cache.Get("c")
+}
+
+func TestCloxCacheAdaptiveBalanceEviction(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, AdaptiveBalance: true}
+
+	// Once-seen entries are within their share: the frequency portion gives up a victim
+	cache := NewCloxCache[string, int](cfg)
+	fillBalanced(cache)
+	cache.Put("e", 5)
+	if _, ok := cache.peek("b"); ok {
+		t.Error("Expected the LRU repeatedly seen entry to be evicted")
+	}
+	if _, ok := cache.peek("a"); !ok {
+		t.Error("Once-seen entry was evicted while within its share")
+	}
+	cache.Close()
+
+	// Once-seen entries are over their share: they go first
+	cache = NewCloxCache[string, int](cfg)
+	cache.shards[0].recencyTarget.Store(0)
+	fillBalanced(cache)
+	cache.Put("e", 5)
+	if _, ok := cache.peek("a"); ok {
+		t.Error("Expected the LRU once-seen entry to be evicted")
+	}
+	if _, ok := cache.peek("b"); !ok {
+		t.Error("Repeatedly seen entry was evicted while once-seen entries are over their share")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+	cache.Close()
+}
+
+func TestCloxCacheAdaptiveBalanceGhostHits(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 256, Capacity: 128, SweepPercent: 100, AdaptiveBalance: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	target := func() int64 { return cache.GetAdaptiveStats()[0].RecencyTarget }
+	if got := target(); got != 64 {
+		t.Fatalf("Initial RecencyTarget = %d, want 64", got)
+	}
+
+	// Fill the shard, then push the first entries out as once-seen ghosts
+	for i := range 130 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if !cache.IsGhost("key-0") {
+		t.Fatal("Expected key-0 to be a ghost")
+	}
+	cache.Put("key-0", 0)
+	if got := target(); got != 66 {
+		t.Errorf("RecencyTarget after a once-seen ghost hit = %d, want 66", got)
+	}
+
+	// A ghost that had been seen repeatedly shrinks the recency target
+	cache.ghostHit(&cache.shards[0], 3)
+	if got := target(); got != 64 {
+		t.Errorf("RecencyTarget after a repeated ghost hit = %d, want 64", got)
+	}
+}
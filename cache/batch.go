@@ -0,0 +1,73 @@
+package cache
+
+import "slices"
+
+// HashKeys hashes keys in one pass, appending the results to dst (which may be
+// nil) for use with GetHashed and PutHashed. Like Prehash, the hashes are
+// 64-bit; caches using WithHash128 rehash them.
+func HashKeys[K Key](keys []K, dst []HashedKey[K]) []HashedKey[K] {
+	dst = slices.Grow(dst, len(keys))
+	for _, key := range keys {
+		dst = append(dst, HashedKey[K]{key: key, hash: hashKey(key)})
+	}
+	return dst
+}
+
+// hashKeys is HashKeys the way the cache hashes keys
+func (c *CloxCache[K, V]) hashKeys(keys []K) []HashedKey[K] {
+	hashed := make([]HashedKey[K], len(keys))
+	for i, key := range keys {
+		hashed[i] = c.Prehash(key)
+	}
+	return hashed
+}
+
+// GetMany looks up several keys at once, returning their values and whether each
+// was found, by index. Keys are hashed in one pass and looked up shard by shard,
+// so lookups in the same shard run back to back. Each lookup behaves like Get.
+func (c *CloxCache[K, V]) GetMany(keys []K) (values []V, found []bool) {
+	hashed := c.hashKeys(keys)
+	values = make([]V, len(keys))
+	found = make([]bool, len(keys))
+	for _, i := range c.shardOrder(hashed) {
+		values[i], found[i] = c.getWithHash(hashed[i].key, hashed[i].hash, hashed[i].hi)
+	}
+	return values, found
+}
+
+// PutMany stores values[i] under keys[i] for every i, shard by shard like
+// GetMany, and returns how many were stored. Each store behaves like Put.
+// Panics if the slices differ in length.
+func (c *CloxCache[K, V]) PutMany(keys []K, values []V) int {
+	if len(keys) != len(values) {
+		panic("cache: PutMany with mismatched keys and values")
+	}
+	hashed := c.hashKeys(keys)
+	stored := 0
+	for _, i := range c.shardOrder(hashed) {
+		if ok, _ := c.putReasonWithHash(hashed[i].key, hashed[i].hash, hashed[i].hi, values[i], nil); ok {
+			stored++
+		}
+	}
+	return stored
+}
+
+// shardOrder returns the indexes of keys grouped by shard, keeping their relative
+// order within each shard (a counting sort on the shard ID)
+func (c *CloxCache[K, V]) shardOrder(keys []HashedKey[K]) []int {
+	mask := uint64(c.numShards - 1)
+	starts := make([]int, c.numShards+1)
+	for _, h := range keys {
+		starts[h.hash&mask+1]++
+	}
+	for s := 1; s <= c.numShards; s++ {
+		starts[s] += starts[s-1]
+	}
+	order := make([]int, len(keys))
+	for i, h := range keys {
+		s := h.hash & mask
+		order[starts[s]] = i
+		starts[s]++
+	}
+	return order
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheGetManyPutMany(t *testing.T) {
+	cfg := Config{NumShards: 8, SlotsPerShard: 64, Capacity: 512}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	keys := make([]string, 200)
+	values := make([]int, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		values[i] = i
+	}
+	if stored := cache.PutMany(keys[:100], values[:100]); stored != 100 {
+		t.Fatalf("PutMany stored %d, want 100", stored)
+	}
+
+	got, found := cache.GetMany(keys)
+	for i := range keys {
+		if want := i < 100; found[i] != want || (want && got[i] != i) {
+			t.Errorf("GetMany[%d] = %d, %v; want %d, %v", i, got[i], found[i], i, want)
+		}
+	}
+}
+
+func TestCloxCacheShardOrder(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 16, Capacity: 64}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	hashed := HashKeys([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, nil)
+	order := cache.shardOrder(hashed)
+	if len(order) != len(hashed) {
+		t.Fatalf("shardOrder returned %d indexes, want %d", len(order), len(hashed))
+	}
+	seen := make(map[int]bool)
+	last, lastIndex := uint64(0), -1
+	for _, i := range order {
+		shard := hashed[i].hash & 3
+		if shard < last || (shard == last && i < lastIndex) {
+			t.Errorf("Index %d (shard %d) out of order after %d (shard %d)", i, shard, lastIndex, last)
+		}
+		seen[i] = true
+		last, lastIndex = shard, i
+	}
+	if len(seen) != len(hashed) {
+		t.Errorf("shardOrder visited %d distinct keys, want %d", len(seen), len(hashed))
+	}
+}
+
+func TestCloxCachePutManyMismatched(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 16})
+	defer cache.Close()
+	defer func() {
+		if recover() == nil {
+			t.Error("PutMany with mismatched slices didn't panic")
+		}
+	}()
+	cache.PutMany([]string{"a", "b"}, []int{1})
+}
@@ -0,0 +1,60 @@
+package cache
+
+// Capacity borrowing (Config.BorrowPercent > 0).
+//
+// Hash skew leaves some shards evicting constantly while others sit half-empty.
+// When an insert finds its shard full, the shard first takes back any capacity it
+// lent out, then repays a unit it borrowed if a lender has asked for one back, and
+// otherwise borrows a unit from one of a few other shards holding fewer entries
+// than their capacity, up to borrowCapacity units. Lending lowers the lender's
+// effective capacity by the same amount, so the cache as a whole stays within its
+// capacity except for units a lender has taken back and the borrower has yet to
+// repay (it does so on its next insert).
+
+// borrowProbes is how many other shards a full shard asks for a unit of capacity
+const borrowProbes = 4
+
+// shardCapacity returns the number of entries shard holds before inserts evict:
+// its (possibly governed) limit adjusted by the capacity it borrowed and lent
+func (s *shard[K, V]) shardCapacity() int64 {
+	return max(s.limit.Load()+s.borrowed.Load()-s.lent.Load(), 1)
+}
+
+// rebalance moves a unit of capacity towards a full shard instead of evicting.
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) rebalance(shardID int, shard *shard[K, V]) {
+	if shard.borrowCapacity == 0 {
+		return
+	}
+
+	// Capacity lent out comes back first; the borrower repays it on its next insert
+	if shard.lent.Load() > 0 {
+		shard.lent.Add(-1)
+		c.recalls.Add(1)
+		return
+	}
+	if shard.borrowed.Load() > 0 {
+		for recalls := c.recalls.Load(); recalls > 0; recalls = c.recalls.Load() {
+			if c.recalls.CompareAndSwap(recalls, recalls-1) {
+				shard.borrowed.Add(-1)
+				return
+			}
+		}
+	}
+	if shard.borrowed.Load() >= shard.borrowCapacity {
+		return
+	}
+
+	start := int(shard.timestamp.Load())
+	for i := range min(borrowProbes, len(c.shards)-1) {
+		id := (shardID + 1 + (start+i)%(len(c.shards)-1)) % len(c.shards)
+		lender := &c.shards[id]
+		lent := lender.lent.Load()
+		if lender.entryCount.Load()+lent < lender.limit.Load() && lender.lent.CompareAndSwap(lent, lent+1) {
+			shard.borrowed.Add(1)
+			shard.borrows.Add(1)
+			lender.lends.Add(1)
+			return
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+)
+
+// keysForShard returns n keys that hash to the given shard of a 4-shard cache
+func keysForShard(shard uint64, prefix string, n int) []string {
+	var keys []string
+	for i := 0; len(keys) < n; i++ {
+		key := fmt.Sprintf("%s-%d", prefix, i)
+		if hashKey(key)&3 == shard {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestCloxCacheBorrowCapacity(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 64, SweepPercent: 100, BorrowPercent: 50}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// A skewed shard borrows unused capacity instead of evicting
+	for i, key := range keysForShard(0, "skewed", 24) {
+		cache.Put(key, i)
+	}
+	stats := cache.GetAdaptiveStats()
+	if n := cache.shards[0].entryCount.Load(); n != 24 {
+		t.Errorf("Skewed shard holds %d entries, want 24", n)
+	}
+	if stats[0].Borrowed != 8 || stats[0].Borrows != 8 {
+		t.Errorf("Borrowed = %d (lifetime %d), want 8", stats[0].Borrowed, stats[0].Borrows)
+	}
+	var lent int64
+	for _, s := range stats[1:] {
+		lent += s.Lent
+	}
+	if lent != 8 {
+		t.Errorf("Other shards lent %d, want 8", lent)
+	}
+	if got := cache.EffectiveCapacity(); got != 64 {
+		t.Errorf("EffectiveCapacity = %d, want 64", got)
+	}
+
+	// Borrowing is bounded: further inserts evict
+	cache.Put(keysForShard(0, "more", 1)[0], 0)
+	if n := cache.shards[0].entryCount.Load(); n != 24 {
+		t.Errorf("Skewed shard grew to %d entries past its borrowing bound", n)
+	}
+
+	// Lenders take their capacity back as they fill, and the borrower repays it
+	for shard := uint64(1); shard < 4; shard++ {
+		for i, key := range keysForShard(shard, "fill", 16) {
+			cache.Put(key, i)
+		}
+	}
+	for i, key := range keysForShard(0, "repay", 8) {
+		cache.Put(key, i)
+	}
+	for i, s := range cache.GetAdaptiveStats() {
+		if s.Borrowed != 0 || s.Lent != 0 {
+			t.Errorf("Shard %d still has borrowed=%d lent=%d", i, s.Borrowed, s.Lent)
+		}
+		if n := cache.shards[i].entryCount.Load(); n != 16 {
+			t.Errorf("Shard %d holds %d entries, want 16", i, n)
+		}
+	}
+	if n := cache.recalls.Load(); n != 0 {
+		t.Errorf("%d recalled units were not repaid", n)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheNoBorrowing(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 64, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i, key := range keysForShard(0, "skewed", 24) {
+		cache.Put(key, i)
+	}
+	if n := cache.shards[0].entryCount.Load(); n != 16 {
+		t.Errorf("Shard holds %d entries without borrowing, want 16", n)
+	}
+	if s := cache.GetAdaptiveStats()[0]; s.Borrowed != 0 || s.Borrows != 0 {
+		t.Errorf("Borrowed = %d (lifetime %d) with borrowing disabled", s.Borrowed, s.Borrows)
+	}
+}
+
+func TestConfigBorrowPercentSources(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 1000, "borrowPercent": 25}`))
+	if err != nil || cfg.BorrowPercent != 25 {
+		t.Errorf("ConfigFromJSON borrowPercent = %d, %v", cfg.BorrowPercent, err)
+	}
+	if _, err := ConfigFromJSON([]byte(`{"capacity": 1000, "borrowPercent": 101}`)); err == nil {
+		t.Error("Expected ConfigFromJSON to reject borrowPercent above 100")
+	}
+
+	t.Setenv("CLOX_CAPACITY", "1000")
+	t.Setenv("CLOX_BORROW_PERCENT", "10")
+	if cfg, err := ConfigFromEnv(""); err != nil || cfg.BorrowPercent != 10 {
+		t.Errorf("ConfigFromEnv borrowPercent = %d, %v", cfg.BorrowPercent, err)
+	}
+
+	cfg = ConfigFromCapacity(1000)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-cache-borrow", "20"}); err != nil || cfg.BorrowPercent != 20 {
+		t.Errorf("-cache-borrow = %d, %v", cfg.BorrowPercent, err)
+	}
+
+	if err := (Config{NumShards: 1, SlotsPerShard: 1, BorrowPercent: -1}).Validate(); err == nil {
+		t.Error("Expected Validate to reject a negative BorrowPercent")
+	}
+}
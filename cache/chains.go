@@ -0,0 +1,58 @@
+package cache
+
+import "slices"
+
+// ChainStats describes how evenly keys spread over the slots. Long chains mean
+// SlotsPerShard is undersized for the number of entries and ghosts; a rapidly
+// growing HashCollisions count, or a few very long chains with short ones
+// elsewhere, points at keys crafted to collide (a hash flood).
+type ChainStats struct {
+	Slots    int     // slots across all shards
+	Occupied int     // slots holding at least one node (live or ghost)
+	AvgChain float64 // nodes per occupied slot
+	// Chain length percentiles over occupied slots
+	P50, P90, P99, MaxChain int
+	// Lifetime counters: inserts into a slot already holding other keys, and
+	// lookups that found a node with the same 64-bit hash but a different key
+	// (never counted with WithHash128 or WithHashOnlyKeys, which don't compare keys)
+	SlotCollisions uint64
+	HashCollisions uint64
+	Growths        uint64 // slot table doublings (see Config.GrowChainLength)
+}
+
+// ChainStats walks every slot to measure chain lengths, so it costs O(slots);
+// sample it periodically to watch the trend rather than per request.
+func (c *CloxCache[K, V]) ChainStats() ChainStats {
+	stats := ChainStats{HashCollisions: c.hashCollisions.Load()}
+	var lengths []int
+	nodes := 0
+	for i := range c.shards {
+		shard := &c.shards[i]
+		slots := shard.slots()
+		stats.Slots += len(slots)
+		stats.SlotCollisions += shard.slotCollisions.Load()
+		stats.Growths += shard.growths.Load()
+		for s := range slots {
+			length := 0
+			for node := slots[s].Load(); node != nil; node = node.next.Load() {
+				length++
+			}
+			if length > 0 {
+				lengths = append(lengths, length)
+				nodes += length
+			}
+		}
+	}
+
+	stats.Occupied = len(lengths)
+	if stats.Occupied == 0 {
+		return stats
+	}
+	slices.Sort(lengths)
+	stats.AvgChain = float64(nodes) / float64(stats.Occupied)
+	stats.P50 = lengths[(len(lengths)-1)*50/100]
+	stats.P90 = lengths[(len(lengths)-1)*90/100]
+	stats.P99 = lengths[(len(lengths)-1)*99/100]
+	stats.MaxChain = lengths[len(lengths)-1]
+	return stats
+}
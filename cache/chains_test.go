@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheChainStats(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 4, Capacity: 64})
+	defer cache.Close()
+
+	if stats := cache.ChainStats(); stats.Occupied != 0 || stats.SlotCollisions != 0 {
+		t.Errorf("Empty cache reported %+v", stats)
+	}
+
+	for i := range 20 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	stats := cache.ChainStats()
+	if stats.Slots != 4 || stats.Occupied == 0 || stats.Occupied > 4 {
+		t.Errorf("Expected up to 4 occupied of 4 slots, got %d of %d", stats.Occupied, stats.Slots)
+	}
+	// 20 keys in at most 4 slots: every insert after the first in a slot collides
+	if want := uint64(20 - stats.Occupied); stats.SlotCollisions != want {
+		t.Errorf("SlotCollisions = %d, want %d", stats.SlotCollisions, want)
+	}
+	if stats.AvgChain != 20/float64(stats.Occupied) {
+		t.Errorf("AvgChain = %v, want %v", stats.AvgChain, 20/float64(stats.Occupied))
+	}
+	if !(stats.P50 <= stats.P90 && stats.P90 <= stats.P99 && stats.P99 <= stats.MaxChain) || stats.P50 < 1 {
+		t.Errorf("Percentiles out of order: %+v", stats)
+	}
+
+	// A node with the same hash but another key counts as a hash collision
+	hash, hi := cache.hashes("key-1")
+	_, slot := cache.locate(hash)
+	var node *recordNode[string, int]
+	for node = slot.Load(); node.fullKey() != "key-1"; node = node.next.Load() {
+	}
+	if cache.matches(node, hash, hi, "impostor") {
+		t.Fatal("Different keys matched")
+	}
+	if n := cache.ChainStats().HashCollisions; n != 1 {
+		t.Errorf("HashCollisions = %d, want 1", n)
+	}
+}
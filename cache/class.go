@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+const (
+	// maxPriorityClasses bounds the number of priority classes
+	maxPriorityClasses = 8
+
+	// classUnchanged tells put to keep an existing entry's priority class
+	classUnchanged = -1
+)
+
+// WithPriorityClasses enables priority classes, numbered 0 (lowest, used by Put)
+// to len(floors)-1. floors[i] is the fraction (0-1) of each shard's capacity
+// reserved for class i: an insert of a lower class never evicts a class-i entry
+// while class i holds no more than its floor, so bulk traffic can't flush the
+// high-priority working set. If every candidate is reserved the insert is not
+// admitted (Put returns false).
+//
+// Panics if there are more than 8 classes, a floor is outside [0, 1], or the
+// floors add up to more than 1.
+func WithPriorityClasses[K Key, V any](floors ...float64) Option[K, V] {
+	if len(floors) == 0 || len(floors) > maxPriorityClasses {
+		panic(fmt.Sprintf("priority classes must number between 1 and %d", maxPriorityClasses))
+	}
+	var total float64
+	for _, f := range floors {
+		if f < 0 || f > 1 {
+			panic("priority class floors must be between 0 and 1")
+		}
+		total += f
+	}
+	if total > 1 {
+		panic("priority class floors must not add up to more than 1")
+	}
+
+	return func(c *CloxCache[K, V]) {
+		perShard := c.shards[0].capacity
+		c.classFloors = make([]int64, len(floors))
+		for i, f := range floors {
+			c.classFloors[i] = int64(f * float64(perShard))
+		}
+		for i := range c.shards {
+			c.shards[i].classCounts = make([]atomic.Int64, len(floors))
+		}
+	}
+}
+
+// PutWithClass stores value like Put and assigns the entry to a priority class
+// (see WithPriorityClasses). Evictions needed to make room for it never displace
+// higher classes that are at or below their floor. Panics if class is out of range.
+func (c *CloxCache[K, V]) PutWithClass(key K, value V, class int) bool {
+	if class < 0 || class >= max(len(c.classFloors), 1) {
+		panic(fmt.Sprintf("priority class %d out of range", class))
+	}
+	if c.classFloors == nil {
+		return c.Put(key, value)
+	}
+
+	ok := c.putClass(key, value, initialFreq, class, nil) == RejectNone
+	if ok && c.hooks != nil && c.hooks.OnPut != nil {
+		c.hooks.OnPut(key, value)
+	}
+	return ok
+}
+
+// ClassCounts returns the number of live entries in each priority class
+// (nil if classes are not enabled)
+func (c *CloxCache[K, V]) ClassCounts() []int64 {
+	if c.classFloors == nil {
+		return nil
+	}
+	counts := make([]int64, len(c.classFloors))
+	for i := range c.shards {
+		for class := range counts {
+			counts[class] += c.shards[i].classCounts[class].Load()
+		}
+	}
+	return counts
+}
+
+// classLive adjusts the live count of node's class. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) classLive(shard *shard[K, V], node *recordNode[K, V], delta int64) {
+	if c.classFloors != nil {
+		shard.classCounts[node.class].Add(delta)
+	}
+}
+
+// classReserved reports whether an entry of class may not be evicted to admit an
+// entry of incoming class. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) classReserved(shard *shard[K, V], class, incoming uint8) bool {
+	return class > incoming && shard.classCounts[class].Load() <= c.classFloors[class]
+}
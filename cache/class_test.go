@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCachePriorityClasses(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 20, SweepPercent: 100}
+	// Class 1 is guaranteed a quarter of the cache
+	cache := NewCloxCache(cfg, WithPriorityClasses[string, int](0, 0.25))
+	defer cache.Close()
+
+	for i := range 10 {
+		if !cache.PutWithClass(fmt.Sprintf("vip-%d", i), i, 1) {
+			t.Fatalf("PutWithClass vip-%d failed", i)
+		}
+	}
+
+	// A crawl of low-priority one-hit entries
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("crawl-%d", i), i)
+	}
+
+	counts := cache.ClassCounts()
+	if counts[1] < 5 {
+		t.Errorf("Expected at least 5 class-1 entries to survive (floor), got %d", counts[1])
+	}
+	survivors := 0
+	for i := range 10 {
+		if _, ok := cache.Get(fmt.Sprintf("vip-%d", i)); ok {
+			survivors++
+		}
+	}
+	if int64(survivors) != counts[1] {
+		t.Errorf("ClassCounts reports %d, found %d class-1 entries", counts[1], survivors)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCachePriorityClassesWithoutFloor(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 20, SweepPercent: 100}
+	cache := NewCloxCache(cfg, WithPriorityClasses[string, int](0, 0))
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.PutWithClass(fmt.Sprintf("vip-%d", i), i, 1)
+	}
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("crawl-%d", i), i)
+	}
+	if counts := cache.ClassCounts(); counts[1] != 0 {
+		t.Errorf("Without a floor class 1 should be evicted like any other, %d left", counts[1])
+	}
+}
+
+func TestCloxCachePriorityClassChange(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 20}
+	cache := NewCloxCache(cfg, WithPriorityClasses[string, int](0, 0.5, 0.25))
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.PutWithClass("a", 2, 2)
+	if counts := cache.ClassCounts(); counts[0] != 0 || counts[2] != 1 {
+		t.Errorf("Expected a in class 2, counts=%v", counts)
+	}
+	// Plain Put keeps the class
+	cache.Put("a", 3)
+	if counts := cache.ClassCounts(); counts[2] != 1 {
+		t.Errorf("Plain Put changed the class, counts=%v", counts)
+	}
+	cache.Delete("a")
+	if counts := cache.ClassCounts(); counts[2] != 0 {
+		t.Errorf("Delete did not update class counts: %v", counts)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an out-of-range class")
+		}
+	}()
+	cache.PutWithClass("b", 1, 3)
+}
+
+func TestWithPriorityClassesValidation(t *testing.T) {
+	for _, floors := range [][]float64{nil, {0.6, 0.6}, {-0.1}, make([]float64, 9)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for floors %v", floors)
+				}
+			}()
+			WithPriorityClasses[string, int](floors...)
+		}()
+	}
+}
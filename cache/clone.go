@@ -0,0 +1,154 @@
+package cache
+
+import "sync/atomic"
+
+// Clone returns an independent cache with the same config and options holding a
+// copy of every live entry, including its frequency and recency. When
+// includeAdaptive is true the learned state is copied as well: ghosts, the
+// per-shard protection threshold k, and the learned graduation-rate thresholds.
+// Otherwise the clone starts with default thresholds and no ghosts.
+//
+// Each shard is copied under its lock, so the clone is consistent per shard;
+// Puts to other shards may land in the source while the copy is in progress.
+// Hooks are not fired for copied entries.
+//
+// Values are copied shallowly unless WithCloneValue is set. A cache built
+// WithFinalizer or WithAutoClose needs it, since both caches would otherwise
+// release the same resources; Clone panics without it.
+func (c *CloxCache[K, V]) Clone(includeAdaptive bool) *CloxCache[K, V] {
+	if c.finalizes() && c.cloneValue == nil {
+		panic("cache: Clone of a cache WithFinalizer or WithAutoClose requires WithCloneValue")
+	}
+	clone := NewCloxCache(c.config, c.opts...)
+
+	for i := range c.shards {
+		src := &c.shards[i]
+		dst := &clone.shards[i]
+
+		src.mu.Lock()
+		dst.mu.Lock()
+		// A grown source table is copied as is, so every node keeps its slot
+		slots := src.slots()
+		table := make([]atomic.Pointer[recordNode[K, V]], len(slots))
+		dst.table.Store(&table)
+		dst.growths.Store(src.growths.Load())
+		dst.ttlSwept = src.ttlSwept
+		for s := range slots {
+			var tail *recordNode[K, V]
+			for node := slots[s].Load(); node != nil; node = node.next.Load() {
+				cp := cloneNode(node, includeAdaptive, c.cloneValue)
+				if cp == nil {
+					continue
+				}
+				if f := cp.freq.Load(); f > 0 {
+					dst.entryCount.Add(1)
+					dst.liveBytes.Add(cp.size.Load())
+					clone.classLive(dst, cp, 1)
+					clone.freqChanged(dst, 0, f)
+					if cp.lir {
+						dst.lirCount.Add(1)
+					}
+					ttlJoined(dst, cp, cp.expires.Load())
+				} else {
+					dst.ghostCount.Add(1)
+				}
+
+				if tail == nil {
+					table[s].Store(cp)
+				} else {
+					tail.next.Store(cp)
+				}
+				tail = cp
+				clone.linked(cp)
+			}
+		}
+		dst.timestamp.Store(src.timestamp.Load())
+		dst.gdsfClock.Store(src.gdsfClock.Load())
+		dst.lirsBottom.Store(src.lirsBottom.Load())
+		dst.borrowed.Store(src.borrowed.Load())
+		dst.lent.Store(src.lent.Load())
+		dst.epoch.Store(src.epoch.Load())
+		dst.epochStart.Store(src.epochStart.Load())
+		if includeAdaptive {
+			dst.hand.Store(src.hand.Load())
+			dst.k.Store(src.k.Load())
+			dst.rateLow.Store(src.rateLow.Load())
+			dst.rateHigh.Store(src.rateHigh.Load())
+			dst.prevHitRate.Store(src.prevHitRate.Load())
+			dst.lastKDirection.Store(src.lastKDirection.Load())
+			dst.recencyTarget.Store(src.recencyTarget.Load())
+			dst.limit.Store(src.limit.Load())
+		}
+		dst.mu.Unlock()
+		src.mu.Unlock()
+	}
+
+	clone.recalls.Store(c.recalls.Load())
+	clone.generation.Store(c.generation.Load())
+	clone.floor.Store(c.floor.Load())
+	clone.pendingExpiry.Store(c.pendingExpiry.Load())
+	clone.ttlBase = c.ttlBase
+	clone.ttlClock.Store(c.ttlClock.Load())
+	if c.ttlClockOn.Load() {
+		clone.startTTLClock()
+	}
+
+	return clone
+}
+
+// WithCloneValue sets how Clone copies values (by assignment without it). fn
+// must return a value that owns its resources, as the source and the clone each
+// finalize their own values; it runs under the source's shard lock.
+func WithCloneValue[K Key, V any](fn func(key K, value V) V) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.cloneValue = fn
+	}
+}
+
+// cloneNode copies a node without its chain link, copying its value with
+// cloneValue if set. Returns nil for nodes that shouldn't be copied: ghosts
+// (unless includeGhosts) and live nodes whose value was released concurrently.
+func cloneNode[K Key, V any](node *recordNode[K, V], includeGhosts bool, cloneValue func(K, V) V) *recordNode[K, V] {
+	f := node.freq.Load()
+	cp := &recordNode[K, V]{
+		keyHash:   node.keyHash,
+		keyHashHi: node.keyHashHi,
+		prefix:    node.prefix,
+		key:       copyKey(node.key),
+		inlineKey: node.inlineKey,
+		inlineLen: node.inlineLen,
+	}
+	cp.freq.Store(f)
+	cp.priority.Store(node.priority.Load())
+	cp.class = node.class
+	cp.lir = node.lir
+	cp.soft.Store(node.soft.Load())
+	cp.cost.Store(node.cost.Load())
+	cp.gdsfBase.Store(node.gdsfBase.Load())
+	cp.recent.Store(node.recent.Load())
+	cp.lirsLast.Store(node.lirsLast.Load())
+	cp.irr.Store(node.irr.Load())
+	cp.lastAccess.Store(node.lastAccess.Load())
+	cp.gen.Store(node.gen.Load())
+	cp.expires.Store(node.expires.Load())
+
+	if f <= 0 {
+		if !includeGhosts {
+			return nil
+		}
+		cp.value.Store(nil)
+		return cp
+	}
+
+	vp := node.value.Load()
+	if vp == nil {
+		return nil
+	}
+	v := *vp
+	if cloneValue != nil {
+		v = cloneValue(node.fullKey(), v)
+	}
+	cp.value.Store(&v)
+	cp.size.Store(node.size.Load())
+	return cp
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheClone(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 50 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.Get("key-7")
+	cache.Get("key-7")
+
+	clone := cache.Clone(false)
+	defer clone.Close()
+
+	if clone.Config() != cache.Config() {
+		t.Errorf("Clone config %+v differs from %+v", clone.Config(), cache.Config())
+	}
+	if report := clone.VerifyIntegrity(); !report.OK() {
+		t.Fatalf("Clone failed integrity check: %v", report.Issues)
+	}
+	for i := range 50 {
+		key := fmt.Sprintf("key-%d", i)
+		if v, ok := clone.Get(key); !ok || v != i {
+			t.Errorf("Clone Get(%q) = %d, %v; want %d", key, v, ok, i)
+		}
+	}
+	// key-7: Put, two Gets before cloning, one Get on the clone
+	if cloneFreq := liveFreq(clone, "key-7"); cloneFreq != 4 {
+		t.Errorf("Expected cloned frequency 4 for key-7, got %d", cloneFreq)
+	}
+	if clone.SizeBytes() != cache.SizeBytes() {
+		t.Errorf("Clone size %d differs from %d", clone.SizeBytes(), cache.SizeBytes())
+	}
+
+	// The copies are independent
+	clone.Put("key-1", 100)
+	clone.Put("clone-only", 1)
+	if v, _ := cache.Get("key-1"); v != 1 {
+		t.Errorf("Put on clone changed source value to %d", v)
+	}
+	if _, ok := cache.Get("clone-only"); ok {
+		t.Error("Put on clone inserted into source")
+	}
+}
+
+func TestCloxCacheCloneAdaptiveState(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 16, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache(cfg, WithCodec[string, int](JSONCodec[int]{}))
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.shards[0].k.Store(5)
+
+	plain := cache.Clone(false)
+	defer plain.Close()
+	if count, _ := plain.GhostCount(); count != 0 {
+		t.Errorf("Expected no ghosts without adaptive state, got %d", count)
+	}
+	if k := plain.shards[0].k.Load(); k != defaultProtectedFreqThreshold {
+		t.Errorf("Expected default k, got %d", k)
+	}
+	if _, ok := plain.codec.(JSONCodec[int]); !ok {
+		t.Errorf("Clone did not keep the codec option, got %T", plain.codec)
+	}
+
+	full := cache.Clone(true)
+	defer full.Close()
+	srcGhosts, _ := cache.GhostCount()
+	if count, _ := full.GhostCount(); count != srcGhosts || count == 0 {
+		t.Errorf("Expected %d ghosts, got %d", srcGhosts, count)
+	}
+	if k := full.shards[0].k.Load(); k != 5 {
+		t.Errorf("Expected k=5, got %d", k)
+	}
+	if report := full.VerifyIntegrity(); !report.OK() {
+		t.Fatalf("Clone failed integrity check: %v", report.Issues)
+	}
+}
+
+// liveFreq returns the frequency of a live key (0 if absent or a ghost)
+func liveFreq[K Key, V any](c *CloxCache[K, V], key K) int32 {
+	hash := hashKey(key)
+	_, slot := c.locate(hash)
+	for node := slot.Load(); node != nil; node = node.next.Load() {
+		if node.keyHash == hash && node.keyEquals(key) {
+			return max(node.freq.Load(), 0)
+		}
+	}
+	return 0
+}
+
+func TestCloxCacheCloneFinalizer(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 100}
+	finalized := make(map[*int]int)
+	finalizer := WithFinalizer(func(_ string, v *int) { finalized[v]++ })
+
+	plain := NewCloxCache(cfg, finalizer)
+	defer plain.Close()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Clone of a finalizing cache without WithCloneValue didn't panic")
+			}
+		}()
+		plain.Clone(false)
+	}()
+
+	cache := NewCloxCache(cfg, finalizer, WithCloneValue(func(_ string, v *int) *int {
+		cp := *v
+		return &cp
+	}))
+	for i := range 20 {
+		cache.Put(fmt.Sprintf("key-%d", i), &i)
+	}
+	clone := cache.Clone(false)
+	cache.Close()
+	clone.Close()
+
+	// Each value was finalized exactly once, by the cache that owned it
+	if len(finalized) != 40 {
+		t.Errorf("%d distinct values finalized, want 40", len(finalized))
+	}
+	for v, n := range finalized {
+		if n != 1 {
+			t.Errorf("Value %d finalized %d times", *v, n)
+		}
+	}
+}
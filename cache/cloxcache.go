@@ -4,9 +4,15 @@
 package cache
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"math"
 	"math/bits"
+	"math/rand/v2"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -54,32 +60,151 @@ type CloxCache[K Key, V any] struct {
 	shardBits int
 
 	// Configuration
-	collectStats bool
-	sweepPercent int // Percentage of shard to scan during eviction (1-100)
-
-	// Metrics (only updated when collectStats is true)
-	hits      atomic.Uint64
-	misses    atomic.Uint64
-	evictions atomic.Uint64
+	config         Config // normalized configuration the cache was built with
+	collectStats   bool
+	sweepPercent   int            // Percentage of shard to scan during eviction (1-100)
+	codec          Codec[V]       // value encoding for snapshots (nil = not serializable)
+	logger         *slog.Logger   // nil = silent
+	hooks          *Hooks[K, V]   // nil = no event callbacks
+	opts           []Option[K, V] // options the cache was built with (reapplied by Clone)
+	weigher        func(key K, value V) int64
+	classFloors    []int64                            // per-shard live entries reserved per priority class (nil = classes disabled)
+	sketch         *frequencySketch                   // access estimates for admission (nil = Config.Admission off)
+	accessBuffers  *sync.Pool                         // per-P hit buffers (nil = Config.BatchAccesses off, see accesses.go)
+	overflow       OverflowStore[K, V]                // secondary tier for evicted values (nil = discard them)
+	closeSnapshot  func() (io.WriteCloser, error)     // final snapshot destination (nil = none)
+	snapshotStore  SnapshotStore                      // WithSnapshotStore: periodic and final snapshots (nil = none)
+	persistEvery   time.Duration                      // WithSnapshotStore: periodic snapshot interval (0 = on Close only)
+	persistOptions SnapshotOptions                    // WithSnapshotStore: pacing of periodic snapshots
+	persistMu      sync.Mutex                         // serializes saves to snapshotStore
+	persistCancel  atomic.Pointer[context.CancelFunc] // cancels the periodic save in progress (nil = none)
+	pressureEvery  time.Duration                      // WithMemoryPressure: how often memory use is checked (0 = never)
+	pressureRatio  float64                            // WithMemoryPressure: fraction of the memory limit that counts as pressure
+	finalizer      func(key K, value V)               // releases values leaving the cache (nil = none)
+	cloneValue     func(key K, value V) V             // WithCloneValue: copies values for Clone (nil = shallow copy)
+	clock          func() time.Time                   // WithDeterministic: injected clock (nil = time.Now)
+	rng            *rand.Rand                         // WithDeterministic: seeded random source (nil = math/rand)
+	rngMu          sync.Mutex                         // serializes draws from rng
+	interner       *keyInterner                       // shared key prefixes (nil = WithKeyInterning not used)
+	hash128        bool                               // WithHash128: match keys by 128-bit hash
+	hashOnly       bool                               // WithHashOnlyKeys: keys aren't stored, only hashed
+	autoClose      bool                               // close io.Closer values leaving the cache
+	recalls        atomic.Int64                       // BorrowPercent: lent capacity taken back but not yet repaid
+	generation     atomic.Uint64                      // stamped on new nodes; bumped by InvalidateAll and ExpireAllAfter
+	floor          atomic.Uint64                      // oldest valid generation: live nodes below it are stale
+	pendingExpiry  atomic.Pointer[scheduledExpiry]    // ExpireAllAfter: floor raise that hasn't taken effect yet
+	ttlBase        int64                              // unix nanoseconds at which expiry bucket 0 ends (see ttl.go)
+	ttlClock       atomic.Uint32                      // newest expiry bucket that has passed
+	ttlClockOn     atomic.Bool                        // the goroutine advancing ttlClock was started
+
+	// Auxiliary indexes
+	prefixIndex  *keyIndex                            // ordered keys for ScanPrefix (nil = disabled)
+	valueIndexes atomic.Pointer[[]valueIndexer[K, V]] // registered with AddIndex
+	deps         atomic.Pointer[depGraph]             // created by the first PutWithDeps
+
+	// Slow operation reporting (nil = disabled)
+	onSlowOp        func(SlowOp)
+	slowOpThreshold time.Duration
+
+	// Metrics (only updated when collectStats is true, striped across Ps)
+	hits      stripedCounter
+	misses    stripedCounter
+	evictions stripedCounter
+
+	// Overflow tier traffic (only updated with WithOverflow)
+	demotions  atomic.Uint64
+	promotions atomic.Uint64
+
+	hashCollisions atomic.Uint64 // lookups whose hash matched another key (see ChainStats)
 
 	// Lifecycle management
 	stop      chan struct{}
 	wg        sync.WaitGroup
-	closeOnce sync.Once
+	lifecycle sync.Mutex  // serializes Close and Reopen
+	closed    atomic.Bool // set by Close; operations become no-ops
+	frozen    atomic.Bool // set by Freeze; inserts are rejected until Thaw
+
+	// Copy-on-write snapshots (see cow.go)
+	snapshotMu   sync.Mutex  // one Snapshot at a time
+	snapshotting atomic.Bool // a Snapshot is running: writers take the shard lock
 }
 
 // shard contains a portion of the cache slots with minimal lock contention
 type shard[K Key, V any] struct {
-	slots      []atomic.Pointer[recordNode[K, V]]
+	table atomic.Pointer[[]atomic.Pointer[recordNode[K, V]]] // slot chains; replaced when the shard grows (see grow.go)
+
 	mu         sync.Mutex    // only for insertions and sweeper unlink
 	entryCount atomic.Int64  // live entries in this shard
+	liveBytes  atomic.Int64  // weighed size of live entries in this shard
 	capacity   int64         // max live entries for this shard
 	hand       atomic.Uint64 // per-shard CLOCK hand position
 	timestamp  atomic.Uint64 // per-shard timestamp for LRU ordering
+	gdsfClock  atomic.Uint64 // PolicyGDSF inflation value L (float64 bits)
 
 	// Ghost tracking - ghosts have freq <= 0, |freq| is remembered frequency
-	ghostCount    atomic.Int64 // ghost entries in this shard
-	ghostCapacity int64        // max ghosts = slotsPerShard - capacity
+	ghostCount      atomic.Int64  // ghost entries in this shard
+	ghostCapacity   int64         // max ghosts = slotsPerShard - capacity
+	ghosted         atomic.Uint64 // live entries converted to ghosts
+	ghostPromotions atomic.Uint64 // ghosts re-inserted before being dropped
+
+	rejects [numRejectReasons]atomic.Uint64 // Puts that didn't store their value, per reason
+
+	slotCollisions atomic.Uint64 // inserts into a slot already holding other keys (see ChainStats)
+
+	// Generations of values demoted to the overflow store since the first
+	// InvalidateAll or ExpireAllAfter, by key hash (see promote). Guarded by mu.
+	overflowGens map[uint64]uint64
+
+	// Slot table growth (only used when Config.GrowChainLength > 0)
+	resizeSeq atomic.Uint32 // odd while the slot table is being doubled
+	growths   atomic.Uint64 // slot table doublings
+
+	// Sliding-window frequency (only used when Config.FrequencyWindow > 0)
+	epoch      atomic.Uint32 // current frequency epoch
+	epochStart atomic.Int64  // unix nanoseconds at which the current epoch started
+
+	// Probationary segment (only counted when Config.ProbationPercent > 0)
+	protected         atomic.Int64 // live entries accessed more than once
+	protectedCapacity int64        // protected entries allowed before they can be evicted by inserts
+	recencyTarget     atomic.Int64 // AdaptiveBalance: learned share of once-seen entries
+
+	// Capacity governor (limit == capacity unless Config.TargetHitRate is set)
+	limit   atomic.Int64  // effective capacity: inserts evict once entryCount reaches it
+	govHits atomic.Uint64 // hits at the start of the current governor window
+
+	// Capacity borrowing (only used when Config.BorrowPercent > 0)
+	borrowed       atomic.Int64  // capacity units borrowed from other shards
+	lent           atomic.Int64  // capacity units lent to other shards
+	borrowCapacity int64         // most units the shard may borrow
+	borrows        atomic.Uint64 // lifetime units borrowed
+	lends          atomic.Uint64 // lifetime units lent
+
+	// LIRS state (only used with PolicyLIRS, updated under the shard lock)
+	lirCount   atomic.Int64  // live entries in the LIR set
+	lirsBottom atomic.Uint64 // last access of the oldest LIR entry seen by the last eviction scan
+
+	// Live entries per priority class (nil unless WithPriorityClasses is used)
+	classCounts []atomic.Int64
+
+	// TTL buckets (see ttl.go; guarded by mu)
+	ttlBuckets   map[uint32]int64   // live entries per expiry bucket that hasn't passed
+	ttlSwept     uint32             // newest bucket moved to ttlExpired
+	ttlExpired   atomic.Int64       // entries in passed buckets that weren't reclaimed yet
+	ttlLive      atomic.Int64       // entries counted in ttlBuckets
+	ttlLiveSum   atomic.Uint64      // sum of their buckets, for the average remaining TTL
+	ttlWheel     *timingWheel[K, V] // TTL nodes by expiry bucket (nil until the first TTL, see wheel.go)
+	ttlReclaimed atomic.Uint64      // expired entries that left the live set
+	ttlReaped    atomic.Uint64      // of those, removed by the clock's reaper
+
+	// Copy-on-write snapshot state (guarded by mu; cowSaved is nil unless a Snapshot is running)
+	cowSaved  map[int][]cowEntry[K, V] // pre-write entries of written slots the snapshot hasn't reached
+	cowCursor int                      // slots below this index were already read by the snapshot
+	cowFloor  uint64                   // generation floor when the snapshot started
+
+	// Reader pins (see pin.go; nil maps until the first pin)
+	pinMu    sync.Mutex
+	pins     map[*V]int32    // readers holding each value
+	deferred map[*V]struct{} // pinned values that left the cache and await finalizing
 
 	// Adaptive threshold tracking (per-shard, no global contention)
 	k                  atomic.Int32  // current protection threshold for this shard
@@ -88,10 +213,24 @@ type shard[K Key, V any] struct {
 	reachedProtected   atomic.Uint64 // items whose freq crossed the shard's current k (graduated)
 	lastAdaptCheck     atomic.Uint64 // eviction count at last adaptation check
 
-	// Self-tuning threshold learning (gradient descent on hit rate)
-	windowHits     atomic.Uint64 // hits in current measurement window
-	windowOps      atomic.Uint64 // total ops in current measurement window
-	prevHitRate    atomic.Uint64 // previous window hit rate * 10000 (for atomic storage)
+	// Victim quality (see victim.go), recent evictions weighted over older ones
+	victimScores  atomic.Uint64 // summed quality scores, scaled by 10000
+	victimLags    atomic.Uint64 // summed access ticks between victims and the oldest scanned entry
+	victimsScored atomic.Uint64 // evictions scored
+
+	// Eviction scan width (see sweep.go), in hundredths of a percent of the slots
+	scanWidth   atomic.Int32
+	sweepScores atomic.Uint64 // victim scores since the last width adjustment
+	sweepScored atomic.Uint64 // evictions since the last width adjustment
+
+	// Self-tuning threshold learning (gradient descent on cost-weighted hit rate)
+	hits           atomic.Uint64 // lifetime Get hits (always counted, also drives hit rate learning)
+	costHits       atomic.Uint64 // lifetime extra hit weight from entries with a miss cost
+	ops            atomic.Uint64 // lifetime Get calls (always counted)
+	windowHits     atomic.Uint64 // hits at the start of the current measurement window
+	windowCostHits atomic.Uint64 // costHits at the start of the current measurement window
+	windowOps      atomic.Uint64 // ops at the start of the current measurement window
+	prevHitRate    atomic.Uint64 // previous window weighted hit rate * 10000 (for atomic storage)
 	lastKDirection atomic.Int32  // +1 if k increased, -1 if decreased, 0 if no change
 	rateLow        atomic.Uint32 // adaptive low threshold * 10000
 	rateHigh       atomic.Uint32 // adaptive high threshold * 10000
@@ -101,12 +240,40 @@ type shard[K Key, V any] struct {
 // When freq > 0: live entry with that frequency
 // When freq <= 0: ghost entry, |freq| is the remembered frequency
 type recordNode[K Key, V any] struct {
-	value      atomic.Value                     // value stored (stale for ghosts)
-	next       atomic.Pointer[recordNode[K, V]] // chain traversal
-	keyHash    uint64                           // fast hash comparison
-	freq       atomic.Int32                     // access frequency (negative = ghost)
-	lastAccess atomic.Uint64                    // timestamp for LRU tiebreaking
-	key        K
+	// Read by every lookup: the first cache line
+	next      atomic.Pointer[recordNode[K, V]] // chain traversal
+	keyHash   uint64                           // fast hash comparison
+	keyHashHi uint64                           // WithHash128: high half of the 128-bit hash (0 otherwise)
+	freq      atomic.Int32                     // access frequency (negative = ghost)
+	priority  atomic.Int32                     // eviction bias set by PutWithPriority (0 = none)
+	soft      atomic.Bool                      // droppable before other entries (see PutSoft)
+	inlineLen uint8                            // length+1 of a key held in inlineKey (0 = key holds it)
+	class     uint8                            // priority class (guarded by the shard lock)
+	lir       bool                             // PolicyLIRS: in the LIR set (guarded by the shard lock)
+	gen       atomic.Uint64                    // cache generation the value was stored in (see InvalidateAll)
+	value     atomic.Pointer[V]                // nil for ghosts
+	cost      atomic.Int64                     // miss cost in nanoseconds set by PutWithCost (0 = none)
+
+	// Key storage and metadata that changes only when the value or TTL does: the
+	// second line, which lookups read to compare keys
+	expires   atomic.Uint32       // expiry bucket set by PutWithTTL (0 = none, see ttl.go)
+	inlineKey [inlineKeySize]byte // short keys, stored in the node (see storedKey)
+	prefix    *string             // interned key prefix, key holds the rest (nil = key is whole)
+	size      atomic.Int64        // weighed size captured at Put (0 for ghosts)
+	key       K                   // keys too long to inline
+
+	// Written on access, kept off the lines above so hits don't invalidate them
+	// in other cores' caches
+	lastAccess atomic.Uint64 // timestamp for LRU tiebreaking
+	gdsfBase   atomic.Uint64 // PolicyGDSF: shard clock at the last access (float64 bits)
+	recent     atomic.Uint64 // FrequencyWindow: epoch and per-epoch access counts
+	lirsLast   atomic.Uint64 // PolicyLIRS: shard timestamp of the last access
+	irr        atomic.Uint64 // PolicyLIRS: gap between the last two accesses (0 = accessed once)
+
+	// Pads string- and []byte-keyed nodes into the allocator's 192-byte size
+	// class, whose objects start on 64-byte boundaries, so the groups above line
+	// up with hardware cache lines (see layout_test.go)
+	_ [16]byte
 }
 
 // Config holds CloxCache configuration
@@ -117,92 +284,255 @@ type Config struct {
 	CollectStats  bool // Enable hit/miss/eviction counters
 	// (recommend: 15 for temporal workloads and low latency)
 	SweepPercent int // Percentage of shard to scan during eviction
-}
 
-// NewCloxCache creates a new cache with the given configuration
-func NewCloxCache[K Key, V any](cfg Config) *CloxCache[K, V] {
-	// Validate positive values
-	if cfg.NumShards <= 0 {
-		panic("NumShards must be positive")
-	}
-	if cfg.SlotsPerShard <= 0 {
-		panic("SlotsPerShard must be positive")
-	}
-
-	// Validate power-of-2 requirements
-	if cfg.NumShards&(cfg.NumShards-1) != 0 {
-		panic("NumShards must be a power of 2")
-	}
-	if cfg.SlotsPerShard&(cfg.SlotsPerShard-1) != 0 {
-		panic("SlotsPerShard must be a power of 2")
-	}
+	// Logger receives diagnostics: debug for adaptation and eviction decisions,
+	// warn for misconfiguration and failed snapshot loads (nil = silent)
+	Logger *slog.Logger
+
+	// Sizing hints used by memory-based sizing and EstimateMemoryUsage (0 = unknown)
+	AvgKeySize   int // Average key size in bytes
+	AvgValueSize int // Average value size in bytes
+
+	// Policy selects how victims are chosen (zero value = PolicyProtectedFreq)
+	Policy Policy
+
+	// FrequencyWindow makes eviction judge entries by their accesses in the last one
+	// to two windows instead of their lifetime frequency, so protection follows
+	// shifts in the working set (0 = lifetime frequency)
+	FrequencyWindow time.Duration
+
+	// ProbationPercent reserves this percentage of each shard for probationary entries
+	// (those not yet accessed a second time); inserts then only evict probationary
+	// entries while the rest of the shard holds the working set (0 = disabled)
+	ProbationPercent int
+
+	// Admission rejects inserts that would evict an entry requested more often than
+	// the incoming key, using a frequency sketch of recent requests (TinyLFU-style)
+	Admission bool
+
+	// AdaptiveBalance learns from ghost hits how much of each shard should favour
+	// recently inserted over repeatedly used entries (ARC-style), and evicts from
+	// whichever portion is over its share
+	AdaptiveBalance bool
+
+	// TargetHitRate lets each shard shrink its effective capacity while its hit rate
+	// stays above this fraction (e.g. 0.95) and grow it back, up to Capacity, when
+	// the hit rate falls below, giving memory back when a smaller cache would do
+	// (0 = fixed capacity)
+	TargetHitRate float64
+
+	// BorrowPercent lets a full shard borrow up to this percentage of its capacity
+	// from shards holding fewer entries than theirs, so hash skew doesn't leave some
+	// shards evicting while others sit half-empty (0 = fixed per-shard capacity)
+	BorrowPercent int
+
+	// MaxKeyBytes and MaxValueBytes reject Puts of larger keys or values with
+	// RejectOverWeight (ErrTooLarge from PutE), so one oversized entry can't blow
+	// the memory budget (0 = unlimited). Values are measured like SizeBytes
+	// measures entries, minus the key: their length for string and []byte values,
+	// the weigher's result minus len(key) with WithWeigher, the size of V otherwise.
+	MaxKeyBytes   int
+	MaxValueBytes int64
+
+	// GrowChainLength lets a shard double its slots, rehashing its chains under
+	// the shard lock, once its nodes (live and ghost) average more than this many
+	// per slot, so lookups stay short when SlotsPerShard was sized too small
+	// (0 = fixed slot count). Must be at least 1 when set.
+	GrowChainLength float64
+
+	// NoCopyKeys stores []byte keys too long to inline as given instead of
+	// copying them, saving an allocation per insert. The caller must never modify
+	// a key's bytes after passing it to the cache. Interned keys (see
+	// WithKeyInterning) and keys restored from snapshots are still copied.
+	NoCopyKeys bool
+
+	// BatchAccesses buffers hits per P and records them in batches, so readers
+	// of hot keys stop writing to shared node memory on every Get. Frequencies
+	// and access times lag up to a batch of hits behind.
+	BatchAccesses bool
+
+	// FrequencyLogFactor makes frequency increments probabilistic, like Redis's
+	// lfu-log-factor: a hit raises frequency f with probability
+	// 1/((f-1)*FrequencyLogFactor+1). Hot keys stop paying a CAS on most hits and
+	// the counter tells apart keys hit hundreds of times from ones hit dozens
+	// (10 takes about 900 hits to saturate; 0 = every hit counts).
+	FrequencyLogFactor int
+
+	// EvictionSamples switches eviction from scanning a contiguous window of
+	// SweepPercent slots to Redis-style sampling: the chains of random slots are
+	// walked until this many live entries were seen, and the best victim among them
+	// goes. The work per eviction then follows entries rather than slots, which
+	// suits sparse or long-chained tables (0 = window scan; Redis uses 5).
+	EvictionSamples int
+
+	// AdaptiveSweep tunes each shard's eviction scan width from its victim quality
+	// (AdaptiveStats.VictimQuality): scans widen while they keep forcing out
+	// protected entries and narrow while they find good victims, starting from
+	// SweepPercent (see AdaptiveStats.ScanSlots). Not used with EvictionSamples.
+	AdaptiveSweep bool
+
+	// TTLResolution is the width of the time buckets entry TTLs are grouped in
+	// (see PutWithTTL): deadlines round up to the end of their bucket, and the
+	// cache expires one bucket at a time. Coarser buckets mean fewer wakeups and
+	// later expiry (0 = one second; at least 100ms).
+	TTLResolution time.Duration
+}
 
-	sweepPercent := cfg.SweepPercent
-	if sweepPercent <= 0 {
-		sweepPercent = 15
-	} else if sweepPercent > 100 {
-		sweepPercent = 100
+// NewCloxCache creates a new cache with the given configuration.
+// Options configure behaviour that depends on the key or value type.
+func NewCloxCache[K Key, V any](cfg Config, opts ...Option[K, V]) *CloxCache[K, V] {
+	if err := cfg.Validate(); err != nil {
+		panic(err.Error())
 	}
+	d := cfg.derive()
+	sweepPercent := d.sweepPercent
 
 	c := &CloxCache[K, V]{
 		numShards:    cfg.NumShards,
 		shardBits:    bits.Len(uint(cfg.NumShards - 1)),
 		shards:       make([]shard[K, V], cfg.NumShards),
 		stop:         make(chan struct{}),
+		ttlBase:      time.Now().UnixNano(),
 		collectStats: cfg.CollectStats,
 		sweepPercent: sweepPercent,
+		codec:        defaultCodec[V](),
+		logger:       cfg.Logger,
+		hits:         newStripedCounter(),
+		misses:       newStripedCounter(),
+		evictions:    newStripedCounter(),
 	}
 
-	totalCapacity := cfg.Capacity
-	if totalCapacity <= 0 {
-		totalCapacity = cfg.NumShards * cfg.SlotsPerShard
-	}
-	perShardCapacity := int64(totalCapacity / cfg.NumShards)
-	if perShardCapacity < 1 {
-		perShardCapacity = 1
-	}
+	totalCapacity := d.totalCapacity
+	perShardCapacity := d.perShardCapacity
+	ghostCapacity := d.ghostCapacity
 
-	// Ghost capacity uses unused slot space, capped at 100% of live capacity
-	ghostCapacity := int64(cfg.SlotsPerShard) - perShardCapacity
-	if ghostCapacity < 0 {
-		ghostCapacity = 0
+	c.config = cfg
+	c.config.SweepPercent = sweepPercent
+	c.config.Capacity = totalCapacity
+	if cfg.Admission {
+		c.sketch = newFrequencySketch(totalCapacity)
 	}
-	if ghostCapacity > perShardCapacity {
-		ghostCapacity = perShardCapacity
+	if cfg.BatchAccesses {
+		c.accessBuffers = newAccessBuffers[K, V]()
 	}
 
 	for i := range c.shards {
-		c.shards[i].slots = make([]atomic.Pointer[recordNode[K, V]], cfg.SlotsPerShard)
+		slots := make([]atomic.Pointer[recordNode[K, V]], cfg.SlotsPerShard)
+		c.shards[i].table.Store(&slots)
 		c.shards[i].capacity = perShardCapacity
+		c.shards[i].limit.Store(perShardCapacity)
+		c.shards[i].borrowCapacity = d.borrowCapacity
+		c.shards[i].protectedCapacity = d.protectedCapacity
+		c.shards[i].recencyTarget.Store(perShardCapacity / 2)
 		c.shards[i].ghostCapacity = ghostCapacity
 		c.shards[i].k.Store(defaultProtectedFreqThreshold)
+		c.shards[i].scanWidth.Store(int32(sweepPercent * 100))
 		// Initialize self-tuning threshold learning
 		c.shards[i].rateLow.Store(defaultRateLow)
 		c.shards[i].rateHigh.Store(defaultRateHigh)
+		c.shards[i].epochStart.Store(time.Now().UnixNano())
+	}
+
+	c.opts = opts
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.snapshotStore != nil && c.persistEvery > 0 {
+		c.runPersist()
 	}
+	if c.pressureEvery > 0 {
+		c.runPressureWatch()
+	}
+
+	c.warnMisconfiguration(cfg, perShardCapacity, ghostCapacity)
 
 	return c
 }
 
 // Close stops background goroutines and waits for them to exit.
-// Safe to call multiple times.
+// Safe to call multiple times. It is CloseContext without a deadline; flush
+// failures are logged.
+//
+// Once Close has been called the cache no longer changes: Get misses, Puts and
+// Updates store nothing (PutReason reports RejectClosed, PutE and GetE return
+// ErrClosed), deletions remove nothing, and snapshot or gob imports fail with
+// ErrClosed. Read-only introspection (stats, dumps, snapshots) keeps working on
+// the final contents, which are released when the cache is garbage collected
+// (with WithFinalizer or WithAutoClose they are removed and finalized instead).
 func (c *CloxCache[K, V]) Close() {
-	c.closeOnce.Do(func() {
-		close(c.stop)
-	})
-	c.wg.Wait()
+	if err := c.CloseContext(context.Background()); err != nil {
+		c.logWarn("flush on close failed", "error", err)
+	}
 }
 
-func keysEqual[K Key](a, b K) bool {
-	if len(a) != len(b) {
-		return false
+// CloseContext closes the cache like Close, but gives up waiting once ctx is done.
+// Before background goroutines are stopped, it flushes an overflow store that
+// buffers writes and writes the final snapshot configured by WithSnapshotOnClose,
+// both bounded by ctx. Returns the flush error, or ctx.Err() if ctx ended before
+// the background goroutines exited (they still stop on their own). Only the
+// first call after New or Reopen flushes; later calls just wait.
+func (c *CloxCache[K, V]) CloseContext(ctx context.Context) error {
+	var err error
+	c.lifecycle.Lock()
+	if !c.closed.Load() {
+		c.closed.Store(true)
+		err = c.flush(ctx)
+		c.finalizeAll()
+		close(c.stop)
 	}
-	for i := 0; i < len(a); i++ {
-		if a[i] != b[i] {
-			return false
+	c.lifecycle.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
 		}
+		return err
+	}
+}
+
+// Closed reports whether Close has been called (and Reopen has not since)
+func (c *CloxCache[K, V]) Closed() bool {
+	return c.closed.Load()
+}
+
+// Reopen makes a closed cache usable again, for components that are stopped and
+// started repeatedly. It waits for the background goroutines of the previous run
+// to exit, then resets the lifecycle state so operations are accepted again and a
+// later Close flushes once more. Contents, learned
+// state and counters survive the Close/Reopen cycle; entries whose values were
+// flushed to an overflow store are still served from it. A no-op on an open cache.
+func (c *CloxCache[K, V]) Reopen() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if !c.closed.Load() {
+		return
+	}
+	c.wg.Wait()
+	c.stop = make(chan struct{})
+	c.closed.Store(false)
+	if c.ttlClockOn.Load() {
+		c.runTTLClock()
+	}
+	if c.snapshotStore != nil && c.persistEvery > 0 {
+		c.runPersist()
+	}
+	if c.pressureEvery > 0 {
+		c.runPressureWatch()
 	}
-	return true
+}
+
+func keysEqual[K Key](a, b K) bool {
+	// Converting both sides in the comparison doesn't allocate; it compiles to a
+	// length check and a memequal, which compares whole words at a time
+	return string(a) == string(b)
 }
 
 func copyKey[K Key](key K) K {
@@ -216,205 +546,605 @@ func copyKey[K Key](key K) K {
 	}
 }
 
+// locate maps a key hash to its shard and slot
+func (c *CloxCache[K, V]) locate(hash uint64) (*shard[K, V], *atomic.Pointer[recordNode[K, V]]) {
+	shard := &c.shards[hash&uint64(c.numShards-1)]
+	return shard, c.slotIn(shard, hash)
+}
+
+// slotIn returns the slot of shard's current table that hash maps to
+func (c *CloxCache[K, V]) slotIn(shard *shard[K, V], hash uint64) *atomic.Pointer[recordNode[K, V]] {
+	slots := shard.slots()
+	return &slots[(hash>>c.shardBits)&uint64(len(slots)-1)]
+}
+
+// rangeNodes calls fn for every node (live and ghost) until fn returns false.
+// Lock-free: concurrent writers may add or remove nodes while the walk is in progress.
+func (c *CloxCache[K, V]) rangeNodes(fn func(shardID int, node *recordNode[K, V]) bool) {
+	for i := range c.shards {
+		shard := &c.shards[i]
+		slots := shard.slots()
+		for s := range slots {
+			for node := slots[s].Load(); node != nil; node = node.next.Load() {
+				if !fn(i, node) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // Get retrieves a value from the cache (lock-free)
 func (c *CloxCache[K, V]) Get(key K) (V, bool) {
-	var zero V
+	hash, hi := c.hashes(key)
+	return c.getWithHash(key, hash, hi)
+}
 
-	hash := hashKey(key)
-	shardID := hash & uint64(c.numShards-1)
-	slotID := (hash >> c.shardBits) & uint64(len(c.shards[0].slots)-1)
+// getWithHash is Get for a key whose hashes were already computed
+func (c *CloxCache[K, V]) getWithHash(key K, hash, hi uint64) (V, bool) {
+	if c.onSlowOp != nil {
+		return c.getTimed(key, hash, hi)
+	}
+	return c.get(key, hash, hi)
+}
 
-	shard := &c.shards[shardID]
-	slot := &shard.slots[slotID]
+func (c *CloxCache[K, V]) get(key K, hash, hi uint64) (V, bool) {
+	var zero V
+	if c.closed.Load() {
+		return zero, false
+	}
 
-	// Track ops for hit rate learning (always, even if collectStats is false)
-	shard.windowOps.Add(1)
+	shard, _ := c.locate(hash)
 
-	node := slot.Load()
-	for node != nil {
-		if node.keyHash == hash && keysEqual(node.key, key) {
-			f := node.freq.Load()
-			// Skip ghosts (freq <= 0)
-			if f <= 0 {
-				node = node.next.Load()
-				continue
-			}
+	// Track ops for hit rate learning (always, even if collectStats is false)
+	op := shard.ops.Add(1)
+	if c.sketch != nil {
+		c.sketch.increment(hash)
+	}
+	if c.config.TargetHitRate > 0 && op%governorWindow == 0 && !c.frozen.Load() {
+		c.govern(int(hash&uint64(c.numShards-1)), shard)
+	}
 
-			// Bump frequency (saturating at 15)
-			// If already at max, skip all updates - the item is clearly hot
-			if f < maxFrequency {
-				if node.freq.CompareAndSwap(f, f+1) {
-					// Track when items cross into protected status (freq > k)
-					// This happens when freq goes from k to k+1
-					// Only count when at capacity (under eviction pressure)
-					if f == shard.k.Load() && shard.entryCount.Load() >= shard.capacity {
-						shard.reachedProtected.Add(1)
+	// A walk that misses while the shard grows is repeated (see grow.go)
+	for {
+		seq := shard.stableSeq()
+		node := c.slotIn(shard, hash).Load()
+		for node != nil {
+			if c.matches(node, hash, hi, key) {
+				f := node.freq.Load()
+				// Skip ghosts (freq <= 0) and entries invalidated since they were stored
+				if f <= 0 || c.stale(node) {
+					if f > 0 && c.expired(node) {
+						c.expireOnRead(shard, node)
 					}
-					// Only update timestamp when we successfully bumped freq
-					// This amortises the cost, and hot items skip updates entirely
-					node.lastAccess.Store(shard.timestamp.Add(1))
+					node = node.next.Load()
+					continue
 				}
-			}
 
-			// Track hits for hit rate learning
-			shard.windowHits.Add(1)
+				// A nil value means the node was ghosted after we read freq
+				vp := node.value.Load()
+				if vp == nil {
+					node = node.next.Load()
+					continue
+				}
 
-			if c.collectStats {
-				c.hits.Add(1)
+				if c.accessBuffers != nil {
+					c.bufferAccess(shard, node)
+				} else {
+					c.recordHits(shard, node, f, 1)
+				}
+
+				// Track hits for hit rate learning, weighted by the miss cost they saved
+				shard.hits.Add(1)
+				if cost := node.cost.Load(); cost > 0 {
+					shard.costHits.Add(costWeight(cost))
+				}
+
+				if c.collectStats {
+					c.hits.Add(1)
+				}
+				if c.hooks != nil && c.hooks.OnHit != nil && c.hooks.sampled(op) {
+					c.hooks.OnHit(key, *vp)
+				}
+				return *vp, true
 			}
-			return node.value.Load().(V), true
+			node = node.next.Load()
+		}
+		if shard.resizeSeq.Load() == seq {
+			break
 		}
-		node = node.next.Load()
 	}
 
 	if c.collectStats {
 		c.misses.Add(1)
 	}
+	if c.hooks != nil && c.hooks.OnMiss != nil && c.hooks.sampled(op) {
+		c.hooks.OnMiss(key)
+	}
+	if c.overflow != nil {
+		return c.promote(key, hash)
+	}
+	return zero, false
+}
+
+// peek returns the value for a live key without recording an access (lock-free)
+func (c *CloxCache[K, V]) peek(key K) (V, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+
+	if node := c.findNode(shard, hash, hi, key, c.liveNode); node != nil {
+		if vp := node.value.Load(); vp != nil {
+			return *vp, true
+		}
+	}
+	var zero V
 	return zero, false
 }
 
-// Put inserts or updates a value in the cache
+// Put inserts or updates a value in the cache.
+// Returns false if the value wasn't stored (see PutReason).
 func (c *CloxCache[K, V]) Put(key K, value V) bool {
-	hash := hashKey(key)
-	shardID := hash & uint64(c.numShards-1)
-	slotID := (hash >> c.shardBits) & uint64(len(c.shards[0].slots)-1)
+	ok, _ := c.PutReason(key, value)
+	return ok
+}
 
-	shard := &c.shards[shardID]
-	slot := &shard.slots[slotID]
+// PutReason is Put that also reports why a value wasn't stored
+// (RejectNone when it was). Rejections are counted per reason, see Rejections.
+func (c *CloxCache[K, V]) PutReason(key K, value V) (bool, RejectReason) {
+	hash, hi := c.hashes(key)
+	return c.putReasonWithHash(key, hash, hi, value, nil)
+}
 
-	// First, try to update the existing key (lock-free)
+// putReasonWithHash is PutReason for a key whose hashes were already computed.
+// trace, if non-nil, bounds the put by its deadline (see PutCtx).
+func (c *CloxCache[K, V]) putReasonWithHash(key K, hash, hi uint64, value V, trace *opTrace) (bool, RejectReason) {
+	var reason RejectReason
+	if c.onSlowOp != nil {
+		reason = c.putTimed(key, hash, hi, value, trace)
+	} else {
+		reason = c.putWithHash(key, hash, hi, value, initialFreq, classUnchanged, trace)
+	}
+	if reason == RejectNone && c.hooks != nil && c.hooks.OnPut != nil {
+		c.hooks.OnPut(key, value)
+	}
+	return reason == RejectNone, reason
+}
+
+// put inserts or updates a value. freq is the starting frequency used when a new
+// node has to be allocated; existing entries keep (and bump) their own frequency.
+// trace, if non-nil, records the eviction work performed.
+func (c *CloxCache[K, V]) put(key K, value V, freq int32, trace *opTrace) bool {
+	return c.putClass(key, value, freq, classUnchanged, trace) == RejectNone
+}
+
+// putClass is put that also assigns the entry's priority class
+// (classUnchanged keeps the class of an existing entry; new entries get class 0).
+func (c *CloxCache[K, V]) putClass(key K, value V, freq int32, class int, trace *opTrace) RejectReason {
+	hash, hi := c.hashes(key)
+	return c.putWithHash(key, hash, hi, value, freq, class, trace)
+}
+
+// putWithHash is putClass for a key whose hashes were already computed
+func (c *CloxCache[K, V]) putWithHash(key K, hash, hi uint64, value V, freq int32, class int, trace *opTrace) RejectReason {
+	shardID := hash & uint64(c.numShards-1)
+	shard, slot := c.locate(hash)
+	if c.closed.Load() {
+		return shard.rejected(RejectClosed)
+	}
+	if c.oversized(key, value) {
+		return shard.rejected(RejectOverWeight)
+	}
+	if c.sketch != nil {
+		c.sketch.increment(hash)
+	}
+
+	// First, try to update the existing key (lock-free); class changes and running
+	// snapshots need the lock. Missing it during a growth just takes the lock.
 	node := slot.Load()
+	if class != classUnchanged || c.snapshotting.Load() {
+		node = nil
+	}
 	for node != nil {
-		if node.keyHash == hash {
-			if keysEqual(node.key, key) {
-				f := node.freq.Load()
-				// Skip ghosts - we'll handle them under lock
-				if f <= 0 {
-					node = node.next.Load()
-					continue
-				}
-				// Update existing - bump frequency and update access time
-				node.value.Store(value)
-				node.lastAccess.Store(shard.timestamp.Add(1))
-				for {
-					f = node.freq.Load()
-					if f >= maxFrequency || f < 1 {
-						// already at max-freq or became a ghost while we were putting
-						break
-					}
-					if node.freq.CompareAndSwap(f, f+1) {
-						break
-					}
-				}
-				return true
+		if c.matches(node, hash, hi, key) {
+			f := node.freq.Load()
+			// Skip ghosts and stale entries - we'll handle them under lock
+			if f <= 0 || c.stale(node) {
+				node = node.next.Load()
+				continue
+			}
+			// Update existing - bump frequency and update access time
+			vp := &value
+			from := node.value.Swap(vp)
+			if node.freq.Load() > 0 {
+				c.updated(shard, node, key, from, vp)
+				return RejectNone
 			}
+			// A removal or eviction claimed the node between the check and the
+			// swap (both zero freq before taking the value): the old value left
+			// with it, and unless the remover took the new one too, the new one
+			// would be stranded in a node that is no longer live
+			c.valueChanged(key, from, nil)
+			c.finalize(shard, key, from)
+			if !node.value.CompareAndSwap(vp, nil) {
+				return RejectNone // removed after this Put, as far as anyone can tell
+			}
+			break
 		}
 		node = node.next.Load()
 	}
 
-	// Allocate new node with a copied key to prevent caller mutations
-	newNode := &recordNode[K, V]{
-		keyHash: hash,
-		key:     copyKey(key),
-	}
-	newNode.value.Store(value)
-	newNode.freq.Store(initialFreq)
-	newNode.lastAccess.Store(shard.timestamp.Add(1))
+	newNode := c.newRecord(shard, hash, hi, key, value, freq)
 
 	// Try CAS onto head
-	shard.mu.Lock()
+	if reason := trace.lock(&shard.mu); reason != RejectNone {
+		return shard.rejected(reason)
+	}
 	defer shard.mu.Unlock()
+	return c.putLocked(int(shardID), shard, newNode, class, trace)
+}
+
+// recordHits bumps the frequency of a live node by hits (saturating at 15) and
+// refreshes its access time. f is the frequency the caller read.
+func (c *CloxCache[K, V]) recordHits(shard *shard[K, V], node *recordNode[K, V], f, hits int32) {
+	// If already at max, skip all updates - the item is clearly hot
+	if f < maxFrequency {
+		hits = c.frequencyIncrements(f, hits)
+	}
+	if f < maxFrequency && hits > 0 {
+		to := min(f+hits, maxFrequency)
+		if node.freq.CompareAndSwap(f, to) {
+			c.freqChanged(shard, f, to)
+			// Track when items cross into protected status (freq > k)
+			// Only count when at capacity (under eviction pressure)
+			if k := shard.k.Load(); f <= k && to > k && shard.entryCount.Load() >= shard.shardCapacity() {
+				shard.reachedProtected.Add(1)
+			}
+			// Only update timestamp when we successfully bumped freq
+			// This amortises the cost, and hot items skip updates entirely
+			node.lastAccess.Store(shard.timestamp.Add(1))
+		}
+	}
+	c.touched(shard, node)
+}
+
+// updated records a lock-free value replacement on a live node: it maintains
+// indexes and size accounting, refreshes the access time and bumps the frequency
+func (c *CloxCache[K, V]) updated(shard *shard[K, V], node *recordNode[K, V], key K, from, to *V) {
+	c.valueChanged(key, from, to)
+	c.finalize(shard, key, from)
+	size := c.weigh(key, *to)
+	shard.liveBytes.Add(size - node.size.Swap(size))
+	// An eviction may have ghosted or removed the node since the caller checked
+	// it, clearing its size before this swap: take the size back out. Evictions
+	// clear freq before size, so one of the two always does.
+	if node.freq.Load() <= 0 {
+		shard.liveBytes.Add(-node.size.Swap(0))
+	}
+	node.gen.Store(c.generation.Load())
+	node.lastAccess.Store(shard.timestamp.Add(1))
+	c.touched(shard, node)
+	for {
+		f := node.freq.Load()
+		if f >= maxFrequency || f < 1 {
+			// already at max-freq or became a ghost while we were putting
+			break
+		}
+		if node.freq.CompareAndSwap(f, f+1) {
+			c.freqChanged(shard, f, f+1)
+			break
+		}
+	}
+}
+
+// newRecord allocates an unlinked node with a copied key to prevent caller mutations
+func (c *CloxCache[K, V]) newRecord(shard *shard[K, V], hash, hi uint64, key K, value V, freq int32) *recordNode[K, V] {
+	node := &recordNode[K, V]{keyHash: hash, keyHashHi: hi}
+	c.storeKey(node, key)
+	node.value.Store(&value)
+	node.size.Store(c.weigh(key, value))
+	node.freq.Store(freq)
+	node.gen.Store(c.generation.Load())
+	node.lastAccess.Store(shard.timestamp.Add(1))
+	c.touched(shard, node)
+	return node
+}
+
+// putLocked stores newNode's value: it promotes a ghost or updates a concurrently
+// inserted node for the same key, or else inserts newNode after making room.
+// class is the priority class to assign (classUnchanged keeps the existing one).
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) putLocked(shardID int, shard *shard[K, V], newNode *recordNode[K, V],
+	class int, trace *opTrace) RejectReason {
+	hash, hi, key := newNode.keyHash, newNode.keyHashHi, newNode.fullKey()
+	value := newNode.value.Load()
+	size := newNode.size.Load()
+	c.preserve(shard, hash)
 
 	// Re-check for an existing key under lock (including ghosts)
-	node = slot.Load()
+	slot := c.slotIn(shard, hash)
+	node := slot.Load()
+	var prev *recordNode[K, V]
 	for node != nil {
-		if node.keyHash == hash {
-			if keysEqual(node.key, key) {
-				f := node.freq.Load()
-				if f <= 0 {
-					// Found a ghost - promote it! Use remembered freq + 1
-					promotedFreq := -f + 1
-					if promotedFreq > maxFrequency {
-						promotedFreq = maxFrequency
-					}
-					if promotedFreq < initialFreq {
-						promotedFreq = initialFreq
-					}
-					node.value.Store(value)
-					node.freq.Store(promotedFreq)
-					node.lastAccess.Store(shard.timestamp.Add(1))
-					shard.ghostCount.Add(-1)
-					shard.entryCount.Add(1)
-					return true
+		if c.matches(node, hash, hi, key) {
+			f := node.freq.Load()
+			if c.frozen.Load() && (f <= 0 || c.stale(node)) {
+				return shard.rejected(RejectFrozen)
+			}
+			if f > 0 && c.stale(node) {
+				// Invalidated or expired entry: reclaim it and insert the new value as a fresh entry
+				next := node.next.Load()
+				c.removeLocked(shard, slot, prev, node, c.staleReason(node))
+				node = next
+				continue
+			}
+			if f <= 0 {
+				// Found a ghost - promote it! Use remembered freq + 1
+				promotedFreq := -f + 1
+				if promotedFreq > maxFrequency {
+					promotedFreq = maxFrequency
+				}
+				if promotedFreq < initialFreq {
+					promotedFreq = initialFreq
 				}
-				// Someone else inserted it - update value and access time
-				node.value.Store(value)
+				if class != classUnchanged {
+					node.class = uint8(class)
+				}
+				lastUse := node.lirsLast.Load()
+				node.gen.Store(newNode.gen.Load())
+				from := node.value.Swap(value) // nil unless a racing lock-free update left one
+				c.valueChanged(key, from, value)
+				c.finalize(shard, key, from)
+				shard.liveBytes.Add(size - node.size.Swap(size))
+				node.freq.Store(promotedFreq)
+				c.freqChanged(shard, f, promotedFreq)
 				node.lastAccess.Store(shard.timestamp.Add(1))
-				return true
+				c.touched(shard, node)
+				shard.ghostCount.Add(-1)
+				shard.ghostPromotions.Add(1)
+				c.ghostHit(shard, -f)
+				shard.entryCount.Add(1)
+				c.classLive(shard, node, 1)
+				c.lirsJoined(shard, node, lastUse)
+				return RejectNone
+			}
+			// Someone else inserted it - update value and access time
+			if class != classUnchanged && node.class != uint8(class) {
+				c.classLive(shard, node, -1)
+				node.class = uint8(class)
+				c.classLive(shard, node, 1)
 			}
+			from := node.value.Swap(value)
+			c.valueChanged(key, from, value)
+			c.finalize(shard, key, from)
+			shard.liveBytes.Add(size - node.size.Swap(size))
+			node.gen.Store(newNode.gen.Load())
+			node.lastAccess.Store(shard.timestamp.Add(1))
+			c.touched(shard, node)
+			return RejectNone
 		}
+		prev = node
 		node = node.next.Load()
 	}
 
-	// Evict from this shard if over capacity
-	for shard.entryCount.Load() >= shard.capacity {
-		evicted := c.evictFromShard(int(shardID), len(shard.slots))
-		if evicted == 0 {
-			// Couldn't evict anything, break to avoid infinite loop
-			return false
+	if c.frozen.Load() {
+		return shard.rejected(RejectFrozen)
+	}
+	if class != classUnchanged {
+		newNode.class = uint8(class)
+	}
+
+	// Evict from this shard if over capacity, unless another shard can lend some
+	if shard.entryCount.Load() >= shard.shardCapacity() {
+		c.rebalance(shardID, shard)
+	}
+	candidate := int32(-1)
+	if c.sketch != nil && shard.entryCount.Load() >= shard.shardCapacity() {
+		candidate = c.sketch.estimate(hash)
+	}
+	for shard.entryCount.Load() >= shard.shardCapacity() {
+		if trace.expired() {
+			return shard.rejected(RejectDeadline)
+		}
+		if trace.skipsEviction() {
+			return shard.rejected(RejectNoVictim)
+		}
+		reason := c.evictFromShard(shardID, len(shard.slots()), newNode.class, candidate)
+		if trace != nil {
+			trace.evictionScans++
+			trace.slotsScanned += c.scanLength(shard, len(shard.slots()))
+		}
+		if reason != RejectNone {
+			// Couldn't evict anything (or the insert wasn't admitted), break to avoid infinite loop
+			return shard.rejected(reason)
 		}
 	}
 
 	// Insert at head
 	head := slot.Load()
+	if head != nil {
+		shard.slotCollisions.Add(1)
+	}
 	newNode.next.Store(head)
 	slot.Store(newNode)
 	shard.entryCount.Add(1)
+	shard.liveBytes.Add(size)
+	c.classLive(shard, newNode, 1)
+	c.lirsJoined(shard, newNode, 0)
+	c.freqChanged(shard, 0, newNode.freq.Load())
+	c.linked(newNode)
+	c.valueChanged(key, nil, value)
+	c.growLocked(shard)
+
+	return RejectNone
+}
 
-	return true
+// ghostLocked converts a live node into a ghost, releasing its value but keeping
+// its frequency, and returns the released value (nil if a racing update already
+// released it). OnEvict sees reason. Caller must hold the shard lock and ensure
+// there is ghost room.
+func (c *CloxCache[K, V]) ghostLocked(shard *shard[K, V], victim *recordNode[K, V], reason EvictReason) *V {
+	// Convert to ghost: atomically negate freq to claim victim and preserve frequency.
+	// CAS ensures we capture the correct freq even if concurrent Gets bump it.
+	for {
+		f := victim.freq.Load()
+		if victim.freq.CompareAndSwap(f, -f) {
+			c.freqChanged(shard, f, -f)
+			shard.entryCount.Add(-1)
+			shard.ghostCount.Add(1)
+			shard.ghosted.Add(1)
+			c.classLive(shard, victim, -1)
+			c.lirsLeft(shard, victim)
+			c.ttlLeft(shard, victim)
+			break
+		}
+		// CAS failed - freq was bumped by concurrent access, retry with fresh value
+	}
+	// Release the value so ghosts only pin their key and frequency.
+	// Concurrent Gets that already passed the freq check see nil and treat it as a miss.
+	evicted := victim.value.Swap(nil)
+	key := victim.fullKey()
+	c.valueChanged(key, evicted, nil)
+	if c.hooks != nil && c.hooks.OnEvict != nil && evicted != nil {
+		c.hooks.OnEvict(key, *evicted, reason)
+	}
+	shard.liveBytes.Add(-victim.size.Swap(0))
+	return evicted
+}
+
+// touched records an access to node for policy bookkeeping beyond lastAccess and
+// freq, which callers maintain themselves
+func (c *CloxCache[K, V]) touched(shard *shard[K, V], node *recordNode[K, V]) {
+	c.touchGDSF(shard, node)
+	c.touchLIRS(shard, node)
+	c.touchRecent(shard, node)
+}
+
+// linked maintains auxiliary indexes after node was added to a chain.
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) linked(node *recordNode[K, V]) {
+	if c.prefixIndex != nil {
+		c.prefixIndex.insert(string(node.fullKey()))
+	}
+}
+
+// valueChanged updates value indexes after the value stored for key was swapped
+// from one pointer to another (either may be nil), and drops the dependencies of
+// entries that left the cache
+func (c *CloxCache[K, V]) valueChanged(key K, from, to *V) {
+	if indexes := c.valueIndexes.Load(); indexes != nil {
+		for _, ix := range *indexes {
+			ix.update(key, from, to)
+		}
+	}
+	if to == nil && from != nil {
+		if g := c.deps.Load(); g != nil {
+			g.forget(string(key))
+		}
+	}
+}
+
+// unlinked maintains auxiliary indexes after node was removed from its chain.
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) unlinked(node *recordNode[K, V]) {
+	if c.prefixIndex != nil {
+		c.prefixIndex.remove(string(node.fullKey()))
+	}
+}
+
+// scanLength returns how many slots an eviction scan of shard visits
+func (c *CloxCache[K, V]) scanLength(shard *shard[K, V], slotsPerShard int) int {
+	if c.config.AdaptiveSweep {
+		return max(int(int64(slotsPerShard)*int64(shard.scanWidth.Load())/sweepFullWidth), 1)
+	}
+	return max(slotsPerShard*c.sweepPercent/100, 1)
 }
 
 // evictFromShard uses protected-freq eviction with LRU tiebreaking.
 // Called during Put when shard is over capacity. Caller must hold shard lock.
-// Returns the number of entries evicted (0 or 1).
+// Returns RejectNone if an entry was evicted, or why none could be.
 //
 // Algorithm:
-// - Scans a portion of the shard (sweepPercent)
-// - Finds LRU item among low-frequency items (freq <= k)
-// - Falls back to any LRU item if no low-freq items are found
-// - Low-freq items become ghosts (freq negated) instead of being removed
-// - Adapts k based on graduation rate
-func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
+//   - Scans a portion of the shard (sweepPercent), or the chains of random slots
+//     until EvictionSamples live entries were seen (see sample.go)
+//   - Takes invalidated entries first, then the oldest soft entry (see PutSoft)
+//   - Finds LRU item among the cheapest low-frequency items (freq + priority <= k)
+//   - Falls back to the lowest-priority, cheapest LRU item if no low-freq items are found
+//   - Never picks entries of a priority class above incomingClass that is at or
+//     below its capacity floor
+//   - Only picks probationary entries while the protected segment is within its
+//     share (see ProbationPercent)
+//   - With AdaptiveBalance, prefers once-seen or repeatedly seen unprotected items
+//     depending on which portion is over its learned share
+//   - Evicts nothing if the victim is requested more often than the incoming key,
+//     whose sketch estimate is candidate (-1 = admission disabled)
+//   - Low-freq items become ghosts (freq negated) instead of being removed
+//   - Adapts k based on graduation rate
+//
+// With a FrequencyWindow, entries are judged by their recent rather than lifetime
+// frequency (see recentFreq).
+// Under PolicyGDSF the entry with the lowest GreedyDual value is evicted instead
+// (see gdsfValue) and the shard's GDSF clock advances to that value.
+// Under PolicyLIRS the least recently used HIR entry is evicted (see lirs.go).
+func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int, incomingClass uint8, candidate int32) RejectReason {
 	shard := &c.shards[shardID]
 	k := shard.k.Load()
+	windowed := c.config.FrequencyWindow > 0
+	probationOnly := c.probationOnly(shard)
+	balanced := c.config.AdaptiveBalance
+	preferRecency := balanced && c.preferRecency(shard)
+	var epoch uint32
+	if windowed {
+		epoch = c.advanceEpoch(shard)
+	}
+	bottom := shard.lirsBottom.Load()
+	floor := c.validFloor()
 
 	// Calculate scan range
-	maxScan := slotsPerShard * c.sweepPercent / 100
-	if maxScan < 1 {
-		maxScan = 1
+	maxScan := c.scanLength(shard, slotsPerShard)
+	samples, sampled := c.config.EvictionSamples, 0
+	if samples > 0 {
+		maxScan = slotsPerShard // probes, ended early once enough entries are sampled
 	}
 
 	// Advance CLOCK hand
-	advance := (maxScan + 1) / 2
-	startSlot := int(shard.hand.Add(uint64(advance)) % uint64(slotsPerShard))
+	var startSlot int
+	if samples == 0 {
+		advance := (maxScan + 1) / 2
+		startSlot = int(shard.hand.Add(uint64(advance)) % uint64(slotsPerShard))
+	}
 
 	// Track the best victims: low-freq preferred, any as fallback
 	// Also track oldest ghost for eviction when ghost capacity is full
 	var lowFreqVictim, lowFreqPrev *recordNode[K, V]
 	var lowFreqSlot *atomic.Pointer[recordNode[K, V]]
 	lowFreqAccess := uint64(^uint64(0)) // max value
+	var lowFreqCost, lowFreqSeg int
+	var lowFreqValue float64 // PolicyGDSF
 
 	var fallbackVictim, fallbackPrev *recordNode[K, V]
 	var fallbackSlot *atomic.Pointer[recordNode[K, V]]
 	fallbackAccess := uint64(^uint64(0))
+	var fallbackPrio int32
+	var fallbackCost int
+
+	var softVictim, softPrev *recordNode[K, V]
+	var softSlot *atomic.Pointer[recordNode[K, V]]
+	softAccess := uint64(^uint64(0))
 
 	var oldestGhost, oldestGhostPrev *recordNode[K, V]
 	var oldestGhostSlot *atomic.Pointer[recordNode[K, V]]
 	oldestGhostAccess := uint64(^uint64(0))
 
-	for scanned := 0; scanned < maxScan; scanned++ {
+	// Oldest unprotected entry scanned, which the victim is scored against
+	minAccess := uint64(^uint64(0))
+
+	slots := shard.slots()
+	for scanned := 0; scanned < maxScan && (samples == 0 || sampled < samples); scanned++ {
 		slotID := (startSlot + scanned) % slotsPerShard
-		slot := &shard.slots[slotID]
+		if samples > 0 {
+			slotID = c.sampleSlot(slotsPerShard)
+		}
+		slot := &slots[slotID]
 
 		node := slot.Load()
 		var prev *recordNode[K, V]
@@ -435,21 +1165,126 @@ func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
 				node = node.next.Load()
 				continue
 			}
+			sampled++
+
+			// Invalidated and expired entries go first, without becoming ghosts
+			if node.gen.Load() < floor {
+				c.removeLocked(shard, slot, prev, node, EvictReasonInvalidated)
+				return RejectNone
+			}
+			if c.expired(node) {
+				c.removeLocked(shard, slot, prev, node, EvictReasonExpired)
+				return RejectNone
+			}
 
-			// Track LRU among low-freq items (freq <= k, unprotected)
-			if freq <= k && access < lowFreqAccess {
+			// Higher priority classes at or below their floor can't be displaced by this insert
+			if c.classFloors != nil && c.classReserved(shard, node.class, incomingClass) {
+				prev = node
+				node = node.next.Load()
+				continue
+			}
+
+			// Soft entries go before any other candidate, oldest first
+			if node.soft.Load() {
+				if access < softAccess {
+					softVictim = node
+					softPrev = prev
+					softSlot = slot
+					softAccess = access
+				}
+				prev = node
+				node = node.next.Load()
+				continue
+			}
+
+			// The protected segment can't be displaced while it is within its share
+			if probationOnly && freq > initialFreq {
+				prev = node
+				node = node.next.Load()
+				continue
+			}
+
+			// Under AdaptiveBalance, entries outside the portion over its share go last
+			seg := 0
+			if balanced && (freq <= initialFreq) != preferRecency {
+				seg = 1
+			}
+
+			if windowed {
+				freq = recentFreq(node, epoch)
+			}
+
+			if c.config.Policy == PolicyLIRS {
+				last := node.lirsLast.Load()
+				switch irr := node.irr.Load(); {
+				case node.lir:
+					// Oldest LIR entry: demoted if the set outgrew its share, evicted if no HIR
+					if fallbackVictim == nil || last < fallbackAccess {
+						fallbackVictim = node
+						fallbackPrev = prev
+						fallbackSlot = slot
+						fallbackAccess = last
+					}
+				case irr != 0 && last-irr > bottom:
+					// Re-accessed while inside the LIRS stack: joins the LIR set
+					node.lir = true
+					shard.lirCount.Add(1)
+				case lowFreqVictim == nil || last < lowFreqAccess:
+					lowFreqVictim = node
+					lowFreqPrev = prev
+					lowFreqSlot = slot
+					lowFreqAccess = last
+				}
+				if !node.lir {
+					minAccess = min(minAccess, access)
+				}
+				prev = node
+				node = node.next.Load()
+				continue
+			}
+
+			// Priority shifts the frequency an entry is judged by
+			prio := node.priority.Load()
+			if c.config.Policy == PolicyGDSF {
+				// Lowest GreedyDual value goes first (LRU among equals); nothing is protected
+				minAccess = min(minAccess, access)
+				v := gdsfValue(node, freq+prio)
+				if lowFreqVictim == nil || v < lowFreqValue || (v == lowFreqValue && access < lowFreqAccess) {
+					lowFreqVictim = node
+					lowFreqPrev = prev
+					lowFreqSlot = slot
+					lowFreqAccess = access
+					lowFreqValue = v
+				}
+				prev = node
+				node = node.next.Load()
+				continue
+			}
+
+			// Track LRU among low-freq items (freq <= k, unprotected), cheapest first
+			cost := costBand(node.cost.Load())
+			if freq+prio <= k {
+				minAccess = min(minAccess, access)
+			}
+			if freq+prio <= k && (lowFreqVictim == nil || seg < lowFreqSeg ||
+				(seg == lowFreqSeg && (cost < lowFreqCost || (cost == lowFreqCost && access < lowFreqAccess)))) {
 				lowFreqVictim = node
 				lowFreqPrev = prev
 				lowFreqSlot = slot
 				lowFreqAccess = access
+				lowFreqCost = cost
+				lowFreqSeg = seg
 			}
 
-			// Track LRU overall (fallback)
-			if access < fallbackAccess {
+			// Track LRU overall (fallback), lowest priority then cheapest first
+			if fallbackVictim == nil || prio < fallbackPrio ||
+				(prio == fallbackPrio && (cost < fallbackCost || (cost == fallbackCost && access < fallbackAccess))) {
 				fallbackVictim = node
 				fallbackPrev = prev
 				fallbackSlot = slot
 				fallbackAccess = access
+				fallbackPrio = prio
+				fallbackCost = cost
 			}
 
 			prev = node
@@ -457,26 +1292,54 @@ func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
 		}
 	}
 
+	if c.config.Policy == PolicyLIRS && fallbackVictim != nil {
+		shard.lirsBottom.Store(fallbackAccess)
+		if shard.lirCount.Load() > lirsTarget(shard) {
+			c.lirsLeft(shard, fallbackVictim) // demoted to HIR
+		}
+	}
+
 	// Choose a victim: prefer low-freq, protect high-freq items
 	var victim, victimPrev *recordNode[K, V]
 	var victimSlot *atomic.Pointer[recordNode[K, V]]
 	isUnprotected := false
 
-	if lowFreqVictim != nil {
-		shard.evictedUnprotected.Add(1) // evicting low-freq (unprotected) item
+	if softVictim != nil {
+		victim = softVictim
+		victimPrev = softPrev
+		victimSlot = softSlot
+		isUnprotected = true
+	} else if lowFreqVictim != nil {
 		victim = lowFreqVictim
 		victimPrev = lowFreqPrev
 		victimSlot = lowFreqSlot
 		isUnprotected = true
 	} else if fallbackVictim != nil {
-		shard.evictedProtected.Add(1) // forced to evict high-freq (protected) item
 		victim = fallbackVictim
 		victimPrev = fallbackPrev
 		victimSlot = fallbackSlot
 	}
 
 	if victim == nil {
-		return 0
+		return RejectNoVictim
+	}
+	if !c.admits(candidate, victim) {
+		return RejectAdmission
+	}
+
+	if isUnprotected {
+		shard.evictedUnprotected.Add(1) // evicting low-freq (unprotected) item
+		if c.config.Policy == PolicyGDSF && victim == lowFreqVictim && lowFreqValue > math.Float64frombits(shard.gdsfClock.Load()) {
+			shard.gdsfClock.Store(math.Float64bits(lowFreqValue))
+		}
+	} else {
+		shard.evictedProtected.Add(1) // forced to evict high-freq (protected) item
+		c.logDebug("evicting protected entry: no unprotected victim in scan window",
+			"shard", shardID, "k", k, "freq", victim.freq.Load(), "scanned_slots", maxScan)
+	}
+	score := scoreVictim(shard, victim.lastAccess.Load(), minAccess, !isUnprotected)
+	if c.config.AdaptiveSweep && samples == 0 {
+		c.adaptSweep(shardID, shard, score, slotsPerShard)
 	}
 
 	// Check if we can convert to ghost (only for unprotected items with ghost capacity)
@@ -492,28 +1355,34 @@ func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
 			oldestGhostPrev.next.Store(next)
 		}
 		shard.ghostCount.Add(-1)
+		c.unlinked(oldestGhost)
+		// Ghosts normally weigh nothing; this reconciles a racing lock-free update
+		shard.liveBytes.Add(-oldestGhost.size.Swap(0))
+		c.finalize(shard, oldestGhost.fullKey(), oldestGhost.value.Swap(nil))
 		canGhost = true
+	} else if isUnprotected && shard.ghostCapacity > 0 && !canGhost {
+		c.logDebug("ghost capacity exhausted: no ghost in scan window to replace, dropping frequency history",
+			"shard", shardID, "ghosts", shard.ghostCount.Load(), "ghost_capacity", shard.ghostCapacity)
 	}
 
+	c.preserve(shard, victim.keyHash)
+	victimKey := victim.fullKey()
 	if canGhost {
-		// Convert to ghost: atomically negate freq to claim victim and preserve frequency.
-		// CAS ensures we capture the correct freq even if concurrent Gets bump it.
-		// We explicitly don't clear the value: this prevents a race with concurrent Gets.
-		for {
-			f := victim.freq.Load()
-			if victim.freq.CompareAndSwap(f, -f) {
-				shard.entryCount.Add(-1)
-				shard.ghostCount.Add(1)
-				break
-			}
-			// CAS failed - freq was bumped by concurrent access, retry with fresh value
-		}
+		gen := victim.gen.Load()
+		c.demote(shard, victimKey, victim.keyHash, gen, c.ghostLocked(shard, victim, EvictReasonGhosted))
 	} else {
 		// Fully evict: unlink from chain
 		if c.collectStats {
 			c.evictions.Add(1)
 		}
+		// Zero the frequency so a racing Get can't bump the unlinked node, and
+		// before its size so a racing update sees it left (see updated)
+		c.freqChanged(shard, victim.freq.Swap(0), 0)
 		shard.entryCount.Add(-1)
+		shard.liveBytes.Add(-victim.size.Swap(0))
+		c.classLive(shard, victim, -1)
+		c.lirsLeft(shard, victim)
+		c.ttlLeft(shard, victim)
 
 		next := victim.next.Load()
 		if victimPrev == nil {
@@ -521,6 +1390,13 @@ func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
 		} else {
 			victimPrev.next.Store(next)
 		}
+		c.unlinked(victim)
+		vp := victim.value.Swap(nil)
+		c.valueChanged(victimKey, vp, nil)
+		if c.hooks != nil && c.hooks.OnEvict != nil && vp != nil {
+			c.hooks.OnEvict(victimKey, *vp, EvictReasonRemoved)
+		}
+		c.demote(shard, victimKey, victim.keyHash, victim.gen.Load(), vp)
 	}
 
 	// Periodically adapt k based on graduation rate
@@ -528,18 +1404,41 @@ func (c *CloxCache[K, V]) evictFromShard(shardID, slotsPerShard int) int {
 	lastCheck := shard.lastAdaptCheck.Load()
 	if totalEvictions-lastCheck >= adaptiveCheckInterval {
 		if shard.lastAdaptCheck.CompareAndSwap(lastCheck, totalEvictions) {
-			c.adaptThreshold(shard)
+			c.adaptThreshold(shardID, shard)
 		}
 	}
 
-	return 1
+	return RejectNone
+}
+
+// window returns the hits and ops counted since the current measurement window started
+func (s *shard[K, V]) window() (hits, ops uint64) {
+	// Load window starts first: they never exceed the lifetime counters read after them
+	startHits, startOps := s.windowHits.Load(), s.windowOps.Load()
+	return s.hits.Load() - startHits, s.ops.Load() - startOps
+}
+
+// costWindow returns the extra hit weight from costed entries counted since the
+// current measurement window started (see costWeight)
+func (s *shard[K, V]) costWindow() uint64 {
+	start := s.windowCostHits.Load()
+	return s.costHits.Load() - start
+}
+
+// counters returns lifetime hits and Get calls for the shard
+func (s *shard[K, V]) counters() (hits, ops uint64) {
+	// Load hits first so a concurrent Get can't make hits exceed ops
+	hits = s.hits.Load()
+	return hits, s.ops.Load()
 }
 
 // adaptThreshold adjusts the per-shard k based on graduation rate.
 // Also implements self-tuning: adjusts the rate thresholds based on whether
-// k changes actually improved hit rate (gradient descent on hit rate).
+// k changes actually improved hit rate (gradient descent on hit rate). Hits are
+// weighted by the miss cost of the entry they hit (see PutWithCost), so the learned
+// thresholds favour the k that saves the most backend time rather than the most hits.
 // Called periodically during eviction.
-func (c *CloxCache[K, V]) adaptThreshold(shard *shard[K, V]) {
+func (c *CloxCache[K, V]) adaptThreshold(shardID int, shard *shard[K, V]) {
 	graduated := shard.reachedProtected.Load()
 	totalEvictions := shard.evictedUnprotected.Load() + shard.evictedProtected.Load()
 
@@ -548,10 +1447,10 @@ func (c *CloxCache[K, V]) adaptThreshold(shard *shard[K, V]) {
 	}
 
 	// First, check if we have enough data to evaluate the effect of the last k change
-	windowOps := shard.windowOps.Load()
+	windowCostHits := shard.costWindow()
+	windowHits, windowOps := shard.window()
 	if windowOps >= hitRateWindowSize {
-		windowHits := shard.windowHits.Load()
-		currentHitRate := uint64(float64(windowHits) / float64(windowOps) * 10000)
+		currentHitRate := uint64(float64(windowHits+windowCostHits) / float64(windowOps) * 10000)
 		prevHitRate := shard.prevHitRate.Load()
 		lastDirection := shard.lastKDirection.Load()
 
@@ -592,8 +1491,9 @@ func (c *CloxCache[K, V]) adaptThreshold(shard *shard[K, V]) {
 
 		// Save current hit rate and reset window
 		shard.prevHitRate.Store(currentHitRate)
-		shard.windowHits.Store(0)
-		shard.windowOps.Store(0)
+		shard.windowCostHits.Add(windowCostHits)
+		shard.windowHits.Add(windowHits)
+		shard.windowOps.Add(windowOps)
 	}
 
 	// Graduation rate = items that crossed threshold k / total evictions
@@ -618,6 +1518,11 @@ func (c *CloxCache[K, V]) adaptThreshold(shard *shard[K, V]) {
 		kDirection = 1
 	}
 	shard.lastKDirection.Store(kDirection)
+	if kDirection != 0 {
+		c.logDebug("adapted protection threshold",
+			"shard", shardID, "old_k", currentK, "new_k", currentK+kDirection,
+			"graduation_rate", rate, "rate_low", rateLow, "rate_high", rateHigh)
+	}
 
 	// Decay counters to weight recent behavior (but keep minimum for signal)
 	if graduated > 100 {
@@ -629,6 +1534,11 @@ func (c *CloxCache[K, V]) adaptThreshold(shard *shard[K, V]) {
 	}
 }
 
+// Config returns the normalized configuration the cache was built with
+func (c *CloxCache[K, V]) Config() Config {
+	return c.config
+}
+
 // Stats return cache statistics
 func (c *CloxCache[K, V]) Stats() (hits, misses, evictions uint64) {
 	return c.hits.Load(), c.misses.Load(), c.evictions.Load()
@@ -646,6 +1556,30 @@ type AdaptiveStats struct {
 	LearnedRateLow  float64 // learned low threshold (rate below which k decreases)
 	LearnedRateHigh float64 // learned high threshold (rate above which k increases)
 	WindowHitRate   float64 // current window hit rate
+	// Current window hits per Get weighted by miss cost: the objective the thresholds
+	// are tuned on (a hit on an entry costing Nµs counts N, uncosted hits count 1)
+	WindowWeightedHitRate float64
+	// Learned target for once-seen entries (only adapted with AdaptiveBalance)
+	RecencyTarget int64
+	// Effective capacity (governed by TargetHitRate, adjusted by borrowing)
+	Capacity int64
+	// Capacity currently borrowed from and lent to other shards, and lifetime
+	// units borrowed and lent (only with BorrowPercent)
+	Borrowed, Lent int64
+	Borrows, Lends uint64
+	// How close recent victims came to the oldest unprotected entry in their scan
+	// window, from 1 (always the oldest) to 0 (the newest, or a protected entry);
+	// and their average distance from it in access ticks. See victim.go.
+	VictimQuality float64
+	VictimLag     float64
+	// Slots an eviction scan visits (tuned with AdaptiveSweep)
+	ScanSlots int
+	// Entries reclaimed after their TTL passed: lazily, by the reads, writes and
+	// scans that ran into them, or proactively by the clock's reaper (see ttl.go)
+	ExpiredLazily, ExpiredProactively uint64
+	// Lifetime counters (always collected, independent of CollectStats)
+	Hits   uint64
+	Misses uint64
 }
 
 // GetAdaptiveStats returns adaptive threshold stats for all shards
@@ -664,27 +1598,65 @@ func (c *CloxCache[K, V]) GetAdaptiveStats() []AdaptiveStats {
 		}
 
 		// Calculate current window hit rate
-		windowOps := shard.windowOps.Load()
-		var windowHitRate float64
+		windowCostHits := shard.costWindow()
+		windowHits, windowOps := shard.window()
+		hits, ops := shard.counters()
+		var windowHitRate, weightedHitRate float64
 		if windowOps > 0 {
-			windowHitRate = float64(shard.windowHits.Load()) / float64(windowOps)
+			windowHitRate = float64(windowHits) / float64(windowOps)
+			weightedHitRate = float64(windowHits+windowCostHits) / float64(windowOps)
 		}
 
+		quality, lag := shard.victimQuality()
+		reaped := shard.ttlReaped.Load()
 		stats[i] = AdaptiveStats{
-			ShardID:            i,
-			K:                  shard.k.Load(),
-			GraduationRate:     rate,
-			EvictedUnprotected: evictedU,
-			EvictedProtected:   evictedP,
-			ReachedProtected:   graduated,
-			LearnedRateLow:     float64(shard.rateLow.Load()) / 10000.0,
-			LearnedRateHigh:    float64(shard.rateHigh.Load()) / 10000.0,
-			WindowHitRate:      windowHitRate,
+			ShardID:               i,
+			K:                     shard.k.Load(),
+			GraduationRate:        rate,
+			EvictedUnprotected:    evictedU,
+			EvictedProtected:      evictedP,
+			ReachedProtected:      graduated,
+			LearnedRateLow:        float64(shard.rateLow.Load()) / 10000.0,
+			LearnedRateHigh:       float64(shard.rateHigh.Load()) / 10000.0,
+			WindowHitRate:         windowHitRate,
+			WindowWeightedHitRate: weightedHitRate,
+			RecencyTarget:         shard.recencyTarget.Load(),
+			Capacity:              shard.shardCapacity(),
+			Borrowed:              shard.borrowed.Load(),
+			Lent:                  shard.lent.Load(),
+			Borrows:               shard.borrows.Load(),
+			Lends:                 shard.lends.Load(),
+			Hits:                  hits,
+			VictimQuality:         quality,
+			VictimLag:             lag,
+			ScanSlots:             c.scanLength(shard, len(shard.slots())),
+			ExpiredLazily:         shard.ttlReclaimed.Load() - reaped,
+			ExpiredProactively:    reaped,
+			Misses:                ops - hits,
 		}
 	}
 	return stats
 }
 
+// ShardCounters holds always-on per-shard hit/miss counters
+type ShardCounters struct {
+	ShardID int
+	Hits    uint64
+	Misses  uint64
+}
+
+// GetShardCounters returns lifetime hit/miss counters for every shard.
+// These are maintained even when CollectStats is false, so shard imbalance can be
+// monitored without enabling the global counters.
+func (c *CloxCache[K, V]) GetShardCounters() []ShardCounters {
+	counters := make([]ShardCounters, c.numShards)
+	for i := range c.shards {
+		hits, ops := c.shards[i].counters()
+		counters[i] = ShardCounters{ShardID: i, Hits: hits, Misses: ops - hits}
+	}
+	return counters
+}
+
 // AverageK returns the average protection threshold across all shards
 func (c *CloxCache[K, V]) AverageK() float64 {
 	var sum int32
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -441,3 +442,36 @@ func BenchmarkCloxCachePointers(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkCloxCacheLongKeys benchmarks Get with long keys sharing a prefix, where
+// key comparison after a hash match dominates
+func BenchmarkCloxCacheLongKeys(b *testing.B) {
+	for _, size := range []int{256, 1024} {
+		b.Run(fmt.Sprintf("string-%d", size), func(b *testing.B) {
+			benchmarkLongKeys(b, size, strings.Clone)
+		})
+		b.Run(fmt.Sprintf("bytes-%d", size), func(b *testing.B) {
+			benchmarkLongKeys(b, size, func(s string) []byte { return []byte(s) })
+		})
+	}
+}
+
+func benchmarkLongKeys[K Key](b *testing.B, size int, toKey func(string) K) {
+	cache := NewCloxCache[K, int](Config{NumShards: 16, SlotsPerShard: 1024})
+	defer cache.Close()
+
+	const numKeys = 1000
+	keys := make([]HashedKey[K], numKeys)
+	for i := range keys {
+		s := fmt.Sprintf("%0*d", size, i)
+		// Separate copies, so comparisons can't shortcut on identical pointers
+		cache.Put(toKey(s), i)
+		keys[i] = Prehash(toKey(s))
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cache.GetHashed(keys[i%numKeys])
+	}
+}
@@ -504,3 +504,84 @@ func TestCloxCacheKeyBufferReuse(t *testing.T) {
 		}
 	}
 }
+
+func TestCloxCacheShardCounters(t *testing.T) {
+	cfg := Config{
+		NumShards:     8,
+		SlotsPerShard: 64,
+		// CollectStats deliberately off: shard counters are always on
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := range 10 {
+		cache.Get(fmt.Sprintf("key-%d", i))   // hit
+		cache.Get(fmt.Sprintf("other-%d", i)) // miss
+	}
+
+	var hits, misses uint64
+	for _, sc := range cache.GetShardCounters() {
+		hits += sc.Hits
+		misses += sc.Misses
+	}
+	if hits != 10 || misses != 10 {
+		t.Errorf("Shard counters: hits=%d misses=%d, want 10/10", hits, misses)
+	}
+
+	// Global counters stay untouched without CollectStats
+	if h, m, _ := cache.Stats(); h != 0 || m != 0 {
+		t.Errorf("Global stats should be zero without CollectStats, got hits=%d misses=%d", h, m)
+	}
+
+	// The same counters are reported alongside adaptive stats
+	hits, misses = 0, 0
+	for _, as := range cache.GetAdaptiveStats() {
+		hits += as.Hits
+		misses += as.Misses
+	}
+	if hits != 10 || misses != 10 {
+		t.Errorf("Adaptive stats counters: hits=%d misses=%d, want 10/10", hits, misses)
+	}
+}
+
+func TestCloxCacheClosed(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache[string, int](cfg)
+	cache.Put("a", 1)
+	cache.Close()
+	cache.Close() // idempotent
+
+	if !cache.Closed() {
+		t.Fatal("Expected Closed to report true after Close")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get after Close should miss")
+	}
+	if ok, reason := cache.PutReason("b", 2); ok || reason != RejectClosed {
+		t.Errorf("PutReason after Close = %v, %s; want false, %s", ok, reason, RejectClosed)
+	}
+	if err := cache.PutE("b", 2); err != ErrClosed {
+		t.Errorf("PutE after Close = %v, want ErrClosed", err)
+	}
+	if _, _, err := cache.GetE("a"); err != ErrClosed {
+		t.Errorf("GetE after Close = %v, want ErrClosed", err)
+	}
+	if _, ok := cache.Update("a", func(old int, _ bool) int { return old + 1 }); ok {
+		t.Error("Update after Close should store nothing")
+	}
+	if cache.Delete("a") {
+		t.Error("Delete after Close should remove nothing")
+	}
+	if _, err := cache.ReadSnapshot(nil); err != ErrClosed {
+		t.Errorf("ReadSnapshot after Close = %v, want ErrClosed", err)
+	}
+	if n := cache.Rejections(RejectClosed); n != 2 {
+		t.Errorf("Rejections(%s) = %d, want 2", RejectClosed, n)
+	}
+	if _, ok := cache.peek("a"); !ok {
+		t.Error("Close should keep the final contents for introspection")
+	}
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"unsafe"
+)
+
+// Codec converts values to and from their encoded byte representation.
+// Implementations must be safe for concurrent use.
+type Codec[V any] interface {
+	// Encode appends the encoded form of v to dst and returns the extended slice
+	Encode(dst []byte, v V) ([]byte, error)
+	// Decode parses data into a value. data must not be retained or modified.
+	Decode(data []byte) (V, error)
+}
+
+// BytesCodec stores []byte values as-is (Decode returns a copy)
+type BytesCodec struct{}
+
+func (BytesCodec) Encode(dst []byte, v []byte) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return append([]byte(nil), data...), nil
+}
+
+// StringCodec stores string values as their raw bytes
+type StringCodec struct{}
+
+func (StringCodec) Encode(dst []byte, v string) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// JSONCodec encodes values with encoding/json
+type JSONCodec[V any] struct{}
+
+func (JSONCodec[V]) Encode(dst []byte, v V) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, data...), nil
+}
+
+func (JSONCodec[V]) Decode(data []byte) (V, error) {
+	var v V
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+// GobCodec encodes values with encoding/gob. Each value carries its own type
+// information, so it is larger and slower than a dedicated codec, but works for
+// arbitrary Go structs. Interface-typed values must be registered with gob.Register.
+type GobCodec[V any] struct{}
+
+func (GobCodec[V]) Encode(dst []byte, v V) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	if err := gob.NewEncoder(buf).Encode(&v); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[V]) Decode(data []byte) (V, error) {
+	var v V
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v)
+	return v, err
+}
+
+// defaultCodec returns the codec used when none is configured:
+// raw bytes for []byte and string values, gob for everything else
+func defaultCodec[V any]() Codec[V] {
+	if codec, ok := any(BytesCodec{}).(Codec[V]); ok {
+		return codec
+	}
+	if codec, ok := any(StringCodec{}).(Codec[V]); ok {
+		return codec
+	}
+	return GobCodec[V]{}
+}
+
+// EncodedCache stores values in encoded form and decodes them lazily on Get.
+// An optional front cache keeps recently decoded objects so hot keys skip decoding.
+// The encoded store is the source of truth: a front entry is only used when it was
+// decoded from the exact bytes currently stored, so Get stays linearizable with Put.
+type EncodedCache[K Key, V any] struct {
+	raw   *CloxCache[K, []byte]
+	front *CloxCache[K, decodedEntry[V]] // nil when the front is disabled
+	codec Codec[V]
+}
+
+// decodedEntry is a decoded value tagged with the encoded bytes it came from
+type decodedEntry[V any] struct {
+	src   *byte
+	n     int
+	value V
+}
+
+// NewEncodedCache creates a cache that stores values encoded with codec.
+// frontCapacity is the number of decoded objects to keep (0 disables the front).
+func NewEncodedCache[K Key, V any](cfg Config, codec Codec[V], frontCapacity int) *EncodedCache[K, V] {
+	if codec == nil {
+		panic("codec must not be nil")
+	}
+	e := &EncodedCache[K, V]{
+		raw:   NewCloxCache[K, []byte](cfg),
+		codec: codec,
+	}
+	if frontCapacity > 0 {
+		e.front = NewCloxCache[K, decodedEntry[V]](ConfigFromCapacity(frontCapacity))
+	}
+	return e
+}
+
+// Put encodes value and stores it. Returns false if eviction failed.
+func (e *EncodedCache[K, V]) Put(key K, value V) (bool, error) {
+	data, err := e.codec.Encode(nil, value)
+	if err != nil {
+		return false, err
+	}
+	if !e.raw.Put(key, data) {
+		return false, nil
+	}
+	if e.front != nil {
+		e.front.Put(key, decodedEntry[V]{src: unsafe.SliceData(data), n: len(data), value: value})
+	}
+	return true, nil
+}
+
+// Get returns the decoded value for key, using the decoded front when it is current
+func (e *EncodedCache[K, V]) Get(key K) (V, bool, error) {
+	var zero V
+
+	data, ok := e.raw.Get(key)
+	if !ok {
+		return zero, false, nil
+	}
+
+	if e.front != nil {
+		if d, ok := e.front.Get(key); ok && d.src == unsafe.SliceData(data) && d.n == len(data) {
+			return d.value, true, nil
+		}
+	}
+
+	value, err := e.codec.Decode(data)
+	if err != nil {
+		return zero, false, err
+	}
+	if e.front != nil {
+		e.front.Put(key, decodedEntry[V]{src: unsafe.SliceData(data), n: len(data), value: value})
+	}
+	return value, true, nil
+}
+
+// GetRaw returns the encoded bytes for key without decoding.
+// The returned slice is shared with the cache and must not be modified.
+func (e *EncodedCache[K, V]) GetRaw(key K) ([]byte, bool) {
+	return e.raw.Get(key)
+}
+
+// Raw returns the underlying cache holding encoded values
+func (e *EncodedCache[K, V]) Raw() *CloxCache[K, []byte] {
+	return e.raw
+}
+
+// Codec returns the codec used to encode values
+func (e *EncodedCache[K, V]) Codec() Codec[V] {
+	return e.codec
+}
+
+// Close stops both the encoded store and the decoded front
+func (e *EncodedCache[K, V]) Close() {
+	e.raw.Close()
+	if e.front != nil {
+		e.front.Close()
+	}
+}
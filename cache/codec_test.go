@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+type countingCodec struct {
+	decodes atomic.Int64
+}
+
+func (c *countingCodec) Encode(dst []byte, v string) ([]byte, error) {
+	if v == "bad" {
+		return dst, errors.New("cannot encode")
+	}
+	return append(dst, v...), nil
+}
+
+func (c *countingCodec) Decode(data []byte) (string, error) {
+	c.decodes.Add(1)
+	return string(data), nil
+}
+
+func TestEncodedCacheBasicOperations(t *testing.T) {
+	type Record struct {
+		ID   int
+		Name string
+	}
+
+	cfg := Config{NumShards: 16, SlotsPerShard: 256}
+	cache := NewEncodedCache[string, Record](cfg, JSONCodec[Record]{}, 0)
+	defer cache.Close()
+
+	want := Record{ID: 7, Name: "seven"}
+	if ok, err := cache.Put("record", want); !ok || err != nil {
+		t.Fatalf("Put failed: %v, %v", ok, err)
+	}
+
+	got, ok, err := cache.Get("record")
+	if err != nil || !ok {
+		t.Fatalf("Get failed: %v, %v", ok, err)
+	}
+	if got != want {
+		t.Fatalf("Get returned %+v, want %+v", got, want)
+	}
+
+	raw, ok := cache.GetRaw("record")
+	if !ok || string(raw) != `{"ID":7,"Name":"seven"}` {
+		t.Fatalf("GetRaw returned %q, %v", raw, ok)
+	}
+
+	if _, ok, _ := cache.Get("missing"); ok {
+		t.Fatal("Get succeeded on non-existent key")
+	}
+}
+
+func TestEncodedCacheDecodedFront(t *testing.T) {
+	codec := &countingCodec{}
+	cfg := Config{NumShards: 16, SlotsPerShard: 256}
+	cache := NewEncodedCache[string, string](cfg, codec, 100)
+	defer cache.Close()
+
+	cache.Put("key", "v1")
+	for range 10 {
+		if v, ok, _ := cache.Get("key"); !ok || v != "v1" {
+			t.Fatalf("Get returned %q, %v; want v1", v, ok)
+		}
+	}
+	if n := codec.decodes.Load(); n != 0 {
+		t.Errorf("Expected front to serve all reads, got %d decodes", n)
+	}
+
+	// Updating the raw store directly invalidates the front entry
+	cache.Raw().Put("key", []byte("v2"))
+	if v, ok, _ := cache.Get("key"); !ok || v != "v2" {
+		t.Fatalf("Get returned %q, %v after raw update; want v2", v, ok)
+	}
+	if n := codec.decodes.Load(); n != 1 {
+		t.Errorf("Expected exactly one decode after raw update, got %d", n)
+	}
+
+	if _, err := cache.Put("key", "bad"); err == nil {
+		t.Error("Expected encode error to be returned")
+	}
+}
+
+func TestEncodedCacheConcurrentPuts(t *testing.T) {
+	cfg := Config{NumShards: 16, SlotsPerShard: 256}
+	cache := NewEncodedCache[string, string](cfg, StringCodec{}, 100)
+	defer cache.Close()
+
+	done := make(chan struct{})
+	for w := range 4 {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for i := range 1000 {
+				cache.Put("shared", fmt.Sprintf("w%d-%d", w, i))
+			}
+		}()
+	}
+	for range 4 {
+		<-done
+	}
+
+	raw, _ := cache.GetRaw("shared")
+	got, ok, _ := cache.Get("shared")
+	if !ok || got != string(raw) {
+		t.Fatalf("Decoded value %q does not match stored bytes %q", got, raw)
+	}
+}
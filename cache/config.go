@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultSweepPercent is used when Config.SweepPercent is unset
+const defaultSweepPercent = 15
+
+// maxFrequencyLogFactor bounds Config.FrequencyLogFactor, beyond which frequencies
+// would hardly ever rise past 2
+const maxFrequencyLogFactor = 1000
+
+// derivedConfig holds the parameters NewCloxCache computes from a Config
+type derivedConfig struct {
+	totalCapacity    int
+	perShardCapacity int64
+	ghostCapacity    int64 // per shard
+	sweepPercent     int
+
+	protectedCapacity int64 // per shard, when ProbationPercent is set
+	borrowCapacity    int64 // per shard, when BorrowPercent is set
+}
+
+// Validate reports whether the config can be used to build a cache
+func (c Config) Validate() error {
+	// Validate positive values
+	if c.NumShards <= 0 {
+		return errors.New("NumShards must be positive")
+	}
+	if c.SlotsPerShard <= 0 {
+		return errors.New("SlotsPerShard must be positive")
+	}
+
+	// Validate power-of-2 requirements
+	if c.NumShards&(c.NumShards-1) != 0 {
+		return errors.New("NumShards must be a power of 2")
+	}
+	if c.SlotsPerShard&(c.SlotsPerShard-1) != 0 {
+		return errors.New("SlotsPerShard must be a power of 2")
+	}
+
+	if int(c.Policy) >= len(policyNames) {
+		return fmt.Errorf("unknown eviction policy %d", c.Policy)
+	}
+	if c.FrequencyWindow < 0 {
+		return errors.New("FrequencyWindow must not be negative")
+	}
+	if c.ProbationPercent < 0 || c.ProbationPercent > 99 {
+		return errors.New("ProbationPercent must be between 0 and 99")
+	}
+	if c.TargetHitRate < 0 || c.TargetHitRate >= 1 {
+		return errors.New("TargetHitRate must be at least 0 and below 1")
+	}
+	if c.BorrowPercent < 0 || c.BorrowPercent > 100 {
+		return errors.New("BorrowPercent must be between 0 and 100")
+	}
+	if c.MaxKeyBytes < 0 || c.MaxValueBytes < 0 {
+		return errors.New("MaxKeyBytes and MaxValueBytes must not be negative")
+	}
+	if c.FrequencyLogFactor < 0 || c.FrequencyLogFactor > maxFrequencyLogFactor {
+		return fmt.Errorf("FrequencyLogFactor must be between 0 and %d", maxFrequencyLogFactor)
+	}
+	if c.GrowChainLength != 0 && c.GrowChainLength < 1 {
+		return errors.New("GrowChainLength must be 0 or at least 1")
+	}
+	if c.EvictionSamples < 0 {
+		return errors.New("EvictionSamples must not be negative")
+	}
+	if c.AdaptiveSweep && c.EvictionSamples > 0 {
+		return errors.New("AdaptiveSweep and EvictionSamples are mutually exclusive")
+	}
+	if c.TTLResolution != 0 && c.TTLResolution < minTTLResolution {
+		return fmt.Errorf("TTLResolution must be 0 or at least %s", minTTLResolution)
+	}
+	return nil
+}
+
+// derive computes the per-shard parameters for a valid config
+func (c Config) derive() derivedConfig {
+	var d derivedConfig
+
+	d.sweepPercent = c.SweepPercent
+	if d.sweepPercent <= 0 {
+		d.sweepPercent = defaultSweepPercent
+	} else if d.sweepPercent > 100 {
+		d.sweepPercent = 100
+	}
+
+	d.totalCapacity = c.Capacity
+	if d.totalCapacity <= 0 {
+		d.totalCapacity = c.NumShards * c.SlotsPerShard
+	}
+	d.perShardCapacity = int64(d.totalCapacity / c.NumShards)
+	if d.perShardCapacity < 1 {
+		d.perShardCapacity = 1
+	}
+
+	// Ghost capacity uses unused slot space, capped at 100% of live capacity
+	d.ghostCapacity = int64(c.SlotsPerShard) - d.perShardCapacity
+	if d.ghostCapacity < 0 {
+		d.ghostCapacity = 0
+	}
+	if d.ghostCapacity > d.perShardCapacity {
+		d.ghostCapacity = d.perShardCapacity
+	}
+
+	d.protectedCapacity = d.perShardCapacity - d.perShardCapacity*int64(c.ProbationPercent)/100
+	if c.BorrowPercent > 0 {
+		d.borrowCapacity = max(d.perShardCapacity*int64(c.BorrowPercent)/100, 1)
+	}
+
+	return d
+}
+
+// Describe explains what NewCloxCache will build from this config: the derived
+// per-shard capacity, ghost capacity, total slots, and estimated memory.
+func (c Config) Describe() string {
+	if err := c.Validate(); err != nil {
+		return fmt.Sprintf("invalid config: %v", err)
+	}
+	d := c.derive()
+	totalSlots := c.NumShards * c.SlotsPerShard
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "shards:            %d\n", c.NumShards)
+	fmt.Fprintf(&b, "slots per shard:   %d (%d total, %.1f per entry)\n",
+		c.SlotsPerShard, totalSlots, float64(totalSlots)/float64(d.totalCapacity))
+	fmt.Fprintf(&b, "capacity:          %d entries (%d per shard)\n",
+		d.perShardCapacity*int64(c.NumShards), d.perShardCapacity)
+	if d.ghostCapacity == 0 {
+		fmt.Fprintf(&b, "ghost capacity:    none (SlotsPerShard must exceed per-shard capacity)\n")
+	} else {
+		fmt.Fprintf(&b, "ghost capacity:    %d (%d per shard)\n", d.ghostCapacity*int64(c.NumShards), d.ghostCapacity)
+	}
+	if c.EvictionSamples > 0 {
+		fmt.Fprintf(&b, "eviction scan:     sampling %d live entries from random slots per eviction\n",
+			c.EvictionSamples)
+	} else if c.AdaptiveSweep {
+		fmt.Fprintf(&b, "eviction scan:     adaptive, starting at %d%% = %d slots per eviction\n",
+			d.sweepPercent, max(c.SlotsPerShard*d.sweepPercent/100, 1))
+	} else {
+		fmt.Fprintf(&b, "eviction scan:     %d%% = %d slots per eviction\n",
+			d.sweepPercent, max(c.SlotsPerShard*d.sweepPercent/100, 1))
+	}
+	fmt.Fprintf(&b, "eviction policy:   %s\n", c.Policy)
+	if c.FrequencyWindow > 0 {
+		fmt.Fprintf(&b, "frequency window:  %s (eviction counts accesses from the last %s to %s)\n",
+			c.FrequencyWindow, c.FrequencyWindow, 2*c.FrequencyWindow)
+	}
+	if c.ProbationPercent > 0 {
+		fmt.Fprintf(&b, "probation:         %d%% (inserts evict probationary entries while the protected segment holds at most %d per shard)\n",
+			c.ProbationPercent, d.protectedCapacity)
+	}
+	if c.AdaptiveBalance {
+		fmt.Fprintf(&b, "adaptive balance:  recency/frequency split learned from ghost hits\n")
+	}
+	if c.Admission {
+		fmt.Fprintf(&b, "admission:         inserts may not evict more frequently requested entries\n")
+	}
+	if c.TargetHitRate > 0 {
+		fmt.Fprintf(&b, "target hit rate:   %.1f%% (capacity governed between %d and %d per shard)\n",
+			c.TargetHitRate*100, max(d.perShardCapacity/governorFloorShare, 1), d.perShardCapacity)
+	}
+	if c.BorrowPercent > 0 {
+		fmt.Fprintf(&b, "borrowing:         %d%% (a full shard may borrow up to %d entries of unused capacity from other shards)\n",
+			c.BorrowPercent, d.borrowCapacity)
+	}
+	if c.MaxKeyBytes > 0 || c.MaxValueBytes > 0 {
+		fmt.Fprintf(&b, "entry size limits: key %s, value %s\n",
+			describeLimit(int64(c.MaxKeyBytes)), describeLimit(c.MaxValueBytes))
+	}
+	if c.GrowChainLength > 0 {
+		fmt.Fprintf(&b, "slot growth:       a shard doubles its slots once chains average over %g nodes\n",
+			c.GrowChainLength)
+	}
+	if c.FrequencyLogFactor > 0 {
+		fmt.Fprintf(&b, "frequency counter: logarithmic (factor %d, about %d hits to saturate)\n",
+			c.FrequencyLogFactor, saturationHits(c.FrequencyLogFactor))
+	}
+	if c.TTLResolution > 0 {
+		fmt.Fprintf(&b, "ttl resolution:    %s (TTL deadlines round up to a multiple of it)\n", c.TTLResolution)
+	}
+	if c.BatchAccesses {
+		fmt.Fprintf(&b, "access recording:  batched per P (%d hits per batch)\n", accessBatchSize)
+	}
+	if c.NoCopyKeys {
+		fmt.Fprintf(&b, "key copies:        none ([]byte keys must not be modified after a Put)\n")
+	}
+	fmt.Fprintf(&b, "stats:             %t\n", c.CollectStats)
+	if c.AvgKeySize > 0 || c.AvgValueSize > 0 {
+		fmt.Fprintf(&b, "estimated memory:  %s (avg key %d B, avg value %d B)\n",
+			FormatMemory(c.EstimateMemoryUsage()), c.AvgKeySize, c.AvgValueSize)
+	} else {
+		fmt.Fprintf(&b, "estimated memory:  %s (structure only; set AvgKeySize/AvgValueSize to include payload)\n",
+			FormatMemory(c.EstimateMemoryUsage()))
+	}
+	return b.String()
+}
+
+// describeLimit formats a byte limit for Describe (0 = unlimited)
+func describeLimit(n int64) string {
+	if n == 0 {
+		return "unlimited"
+	}
+	return FormatMemory(uint64(n))
+}
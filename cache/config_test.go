@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"valid", Config{NumShards: 4, SlotsPerShard: 64}, ""},
+		{"zero shards", Config{NumShards: 0, SlotsPerShard: 64}, "NumShards must be positive"},
+		{"zero slots", Config{NumShards: 4, SlotsPerShard: 0}, "SlotsPerShard must be positive"},
+		{"shards not power of 2", Config{NumShards: 3, SlotsPerShard: 64}, "NumShards must be a power of 2"},
+		{"slots not power of 2", Config{NumShards: 4, SlotsPerShard: 100}, "SlotsPerShard must be a power of 2"},
+		{"growth below 1", Config{NumShards: 4, SlotsPerShard: 64, GrowChainLength: 0.5}, "GrowChainLength must be 0 or at least 1"},
+		{"negative samples", Config{NumShards: 4, SlotsPerShard: 64, EvictionSamples: -1}, "EvictionSamples must not be negative"},
+		{"sweep and samples", Config{NumShards: 4, SlotsPerShard: 64, AdaptiveSweep: true, EvictionSamples: 5}, "AdaptiveSweep and EvictionSamples are mutually exclusive"},
+		{"fine ttl resolution", Config{NumShards: 4, SlotsPerShard: 64, TTLResolution: 1}, "TTLResolution must be 0 or at least 100ms"},
+		{"negative log factor", Config{NumShards: 4, SlotsPerShard: 64, FrequencyLogFactor: -1}, "FrequencyLogFactor must be between 0 and 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigDescribe(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 256, Capacity: 512, SweepPercent: 10}
+	desc := cfg.Describe()
+
+	for _, want := range []string{
+		"shards:            4",
+		"1024 total",
+		"capacity:          512 entries (128 per shard)",
+		"ghost capacity:    512 (128 per shard)",
+		"10% = 25 slots",
+		"estimated memory:  " + FormatMemory(cfg.EstimateMemoryUsage()),
+	} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("Describe() missing %q:\n%s", want, desc)
+		}
+	}
+
+	// Derived values must match what NewCloxCache builds
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+	d := cfg.derive()
+	if cache.shards[0].capacity != d.perShardCapacity || cache.shards[0].ghostCapacity != d.ghostCapacity {
+		t.Errorf("derive() = %+v, cache built capacity=%d ghosts=%d",
+			d, cache.shards[0].capacity, cache.shards[0].ghostCapacity)
+	}
+
+	noGhosts := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64}
+	if !strings.Contains(noGhosts.Describe(), "ghost capacity:    none") {
+		t.Errorf("Expected no ghost capacity:\n%s", noGhosts.Describe())
+	}
+
+	if got := (Config{NumShards: 3, SlotsPerShard: 64}).Describe(); !strings.HasPrefix(got, "invalid config:") {
+		t.Errorf("Expected invalid config description, got %q", got)
+	}
+}
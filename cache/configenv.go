@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envSettings maps environment variable suffixes to configSpec field names
+var envSettings = []struct {
+	suffix, name string
+}{
+	{"CAPACITY", "capacity"},
+	{"MEMORY_BUDGET", "memoryBudget"},
+	{"NUM_SHARDS", "numShards"},
+	{"SLOTS_PER_SHARD", "slotsPerShard"},
+	{"SWEEP_PERCENT", "sweepPercent"},
+	{"COLLECT_STATS", "collectStats"},
+	{"AVG_KEY_SIZE", "avgKeySize"},
+	{"AVG_VALUE_SIZE", "avgValueSize"},
+	{"POLICY", "policy"},
+	{"FREQUENCY_WINDOW", "frequencyWindow"},
+	{"PROBATION_PERCENT", "probationPercent"},
+	{"ADMISSION", "admission"},
+	{"ADAPTIVE_BALANCE", "adaptiveBalance"},
+	{"TARGET_HIT_RATE", "targetHitRate"},
+	{"BORROW_PERCENT", "borrowPercent"},
+	{"MAX_KEY_BYTES", "maxKeyBytes"},
+	{"MAX_VALUE_BYTES", "maxValueBytes"},
+	{"GROW_CHAIN_LENGTH", "growChainLength"},
+	{"BATCH_ACCESSES", "batchAccesses"},
+	{"FREQUENCY_LOG_FACTOR", "frequencyLogFactor"},
+	{"EVICTION_SAMPLES", "evictionSamples"},
+	{"ADAPTIVE_SWEEP", "adaptiveSweep"},
+	{"TTL_RESOLUTION", "ttlResolution"},
+}
+
+// ConfigFromEnv builds a config from environment variables named <prefix>_<SETTING>
+// (prefix defaults to "CLOX"):
+//
+//	CLOX_CAPACITY, CLOX_MEMORY_BUDGET (bytes or "256MB"), CLOX_NUM_SHARDS,
+//	CLOX_SLOTS_PER_SHARD, CLOX_SWEEP_PERCENT, CLOX_COLLECT_STATS,
+//	CLOX_AVG_KEY_SIZE, CLOX_AVG_VALUE_SIZE, CLOX_POLICY ("protected-freq", "gdsf" or "lirs"),
+//	CLOX_FREQUENCY_WINDOW (a duration such as "5m"), CLOX_PROBATION_PERCENT,
+//	CLOX_ADMISSION, CLOX_ADAPTIVE_BALANCE, CLOX_TARGET_HIT_RATE (a fraction such as 0.95),
+//	CLOX_BORROW_PERCENT, CLOX_MAX_KEY_BYTES, CLOX_MAX_VALUE_BYTES (bytes or "1MB"),
+//	CLOX_GROW_CHAIN_LENGTH, CLOX_BATCH_ACCESSES, CLOX_FREQUENCY_LOG_FACTOR,
+//	CLOX_EVICTION_SAMPLES, CLOX_ADAPTIVE_SWEEP, CLOX_TTL_RESOLUTION (a duration such as "100ms")
+//
+// At least one sizing variable must be set. Use WithEnv to apply the environment
+// on top of a default such as ConfigFromCapacity. Errors wrap ErrInvalidConfig.
+func ConfigFromEnv(prefix string) (Config, error) {
+	return Config{}.WithEnv(prefix)
+}
+
+// WithEnv returns a copy of the config with any <prefix>_<SETTING> environment
+// variables applied (see ConfigFromEnv). Setting CAPACITY or MEMORY_BUDGET resizes
+// the cache; other settings are preserved unless overridden.
+func (c Config) WithEnv(prefix string) (Config, error) {
+	if prefix == "" {
+		prefix = "CLOX"
+	}
+	prefix = strings.TrimSuffix(prefix, "_") + "_"
+
+	spec := configSpec{
+		Capacity:           c.Capacity,
+		NumShards:          c.NumShards,
+		SlotsPerShard:      c.SlotsPerShard,
+		SweepPercent:       c.SweepPercent,
+		CollectStats:       c.CollectStats,
+		AvgKeySize:         c.AvgKeySize,
+		AvgValueSize:       c.AvgValueSize,
+		Policy:             c.Policy,
+		FrequencyWindow:    duration(c.FrequencyWindow),
+		ProbationPercent:   c.ProbationPercent,
+		Admission:          c.Admission,
+		AdaptiveBalance:    c.AdaptiveBalance,
+		TargetHitRate:      c.TargetHitRate,
+		BorrowPercent:      c.BorrowPercent,
+		MaxKeyBytes:        c.MaxKeyBytes,
+		MaxValueBytes:      memorySize(c.MaxValueBytes),
+		GrowChainLength:    c.GrowChainLength,
+		BatchAccesses:      c.BatchAccesses,
+		FrequencyLogFactor: c.FrequencyLogFactor,
+		EvictionSamples:    c.EvictionSamples,
+		AdaptiveSweep:      c.AdaptiveSweep,
+		TTLResolution:      duration(c.TTLResolution),
+	}
+	set := make(map[string]bool)
+	for _, e := range envSettings {
+		value, ok := os.LookupEnv(prefix + e.suffix)
+		if !ok {
+			continue
+		}
+		if err := spec.set(e.name, strings.TrimSpace(value)); err != nil {
+			return Config{}, fmt.Errorf("%w: %s%s: %v", ErrInvalidConfig, prefix, e.suffix, err)
+		}
+		set[e.name] = true
+	}
+
+	// A new size replaces the base layout unless the layout is also given
+	if set["capacity"] || set["memoryBudget"] {
+		if !set["numShards"] {
+			spec.NumShards = 0
+		}
+		if !set["slotsPerShard"] {
+			spec.SlotsPerShard = 0
+		}
+		if set["memoryBudget"] && !set["capacity"] {
+			spec.Capacity = 0
+		}
+	}
+
+	cfg, err := spec.config()
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Logger = c.Logger
+	return cfg, nil
+}
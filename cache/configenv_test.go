@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CLOX_CAPACITY", "20000")
+	t.Setenv("CLOX_SWEEP_PERCENT", "25")
+	t.Setenv("CLOX_COLLECT_STATS", "true")
+
+	cfg, err := ConfigFromEnv("")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+	want := ConfigFromCapacity(20000)
+	want.SweepPercent = 25
+	want.CollectStats = true
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	t.Setenv("APP_CACHE_MEMORY_BUDGET", "32MB")
+	cfg, err = ConfigFromEnv("APP_CACHE_")
+	if err != nil {
+		t.Fatalf("ConfigFromEnv failed: %v", err)
+	}
+	if want := ConfigFromMemorySize(32 << 20); cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	if _, err := ConfigFromEnv("UNSET_PREFIX"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig without sizing variables, got %v", err)
+	}
+}
+
+func TestConfigWithEnv(t *testing.T) {
+	base := ConfigFromCapacity(1000)
+	base.CollectStats = true
+
+	// Nothing set: unchanged
+	cfg, err := base.WithEnv("CLOXTEST")
+	if err != nil || cfg != base {
+		t.Fatalf("Expected unchanged config, got %+v (%v)", cfg, err)
+	}
+
+	// Non-sizing override keeps the layout
+	t.Setenv("CLOXTEST_SWEEP_PERCENT", "5")
+	cfg, err = base.WithEnv("CLOXTEST")
+	if err != nil {
+		t.Fatalf("WithEnv failed: %v", err)
+	}
+	if cfg.NumShards != base.NumShards || cfg.SweepPercent != 5 || !cfg.CollectStats {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	// A memory budget resizes
+	t.Setenv("CLOXTEST_MEMORY_BUDGET", "1GB")
+	cfg, err = base.WithEnv("CLOXTEST")
+	if err != nil {
+		t.Fatalf("WithEnv failed: %v", err)
+	}
+	want := ConfigFromMemorySize(1 << 30)
+	if cfg.Capacity != want.Capacity || cfg.NumShards != want.NumShards || cfg.SlotsPerShard != want.SlotsPerShard {
+		t.Errorf("Expected layout of %+v, got %+v", want, cfg)
+	}
+
+	t.Setenv("CLOXTEST_NUM_SHARDS", "12")
+	_, err = base.WithEnv("CLOXTEST")
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "power of 2") {
+		t.Errorf("Expected power-of-2 error, got %v", err)
+	}
+
+	t.Setenv("CLOXTEST_NUM_SHARDS", "many")
+	_, err = base.WithEnv("CLOXTEST")
+	if err == nil || !strings.Contains(err.Error(), "CLOXTEST_NUM_SHARDS") {
+		t.Errorf("Expected error naming the variable, got %v", err)
+	}
+}
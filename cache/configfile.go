@@ -0,0 +1,400 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidConfig is returned when a config file or environment describes an unusable cache
+var ErrInvalidConfig = errors.New("cache: invalid config")
+
+// configSpec is the external (file/environment) representation of a Config.
+// Sizing can be given as a capacity or a memory budget and is turned into
+// power-of-two shard/slot counts; explicit numShards/slotsPerShard override that.
+type configSpec struct {
+	Capacity           int        `json:"capacity"`
+	MemoryBudget       memorySize `json:"memoryBudget"` // bytes, or a string such as "256MB"
+	NumShards          int        `json:"numShards"`
+	SlotsPerShard      int        `json:"slotsPerShard"`
+	SweepPercent       int        `json:"sweepPercent"`
+	CollectStats       bool       `json:"collectStats"`
+	AvgKeySize         int        `json:"avgKeySize"`
+	AvgValueSize       int        `json:"avgValueSize"`
+	Policy             Policy     `json:"policy"`          // "protected-freq", "gdsf" or "lirs"
+	FrequencyWindow    duration   `json:"frequencyWindow"` // a string such as "5m"
+	ProbationPercent   int        `json:"probationPercent"`
+	Admission          bool       `json:"admission"`
+	AdaptiveBalance    bool       `json:"adaptiveBalance"`
+	TargetHitRate      float64    `json:"targetHitRate"` // a fraction such as 0.95
+	BorrowPercent      int        `json:"borrowPercent"`
+	MaxKeyBytes        int        `json:"maxKeyBytes"`
+	MaxValueBytes      memorySize `json:"maxValueBytes"` // bytes, or a string such as "1MB"
+	GrowChainLength    float64    `json:"growChainLength"`
+	BatchAccesses      bool       `json:"batchAccesses"`
+	FrequencyLogFactor int        `json:"frequencyLogFactor"`
+	EvictionSamples    int        `json:"evictionSamples"`
+	AdaptiveSweep      bool       `json:"adaptiveSweep"`
+	TTLResolution      duration   `json:"ttlResolution"` // a string such as "100ms"
+}
+
+// set assigns a field by its JSON name from its text form (used for YAML and the environment)
+func (s *configSpec) set(name, value string) error {
+	var err error
+	switch name {
+	case "capacity":
+		s.Capacity, err = strconv.Atoi(value)
+	case "memoryBudget":
+		err = s.MemoryBudget.parse(value)
+	case "numShards":
+		s.NumShards, err = strconv.Atoi(value)
+	case "slotsPerShard":
+		s.SlotsPerShard, err = strconv.Atoi(value)
+	case "sweepPercent":
+		s.SweepPercent, err = strconv.Atoi(value)
+	case "collectStats":
+		s.CollectStats, err = strconv.ParseBool(value)
+	case "avgKeySize":
+		s.AvgKeySize, err = strconv.Atoi(value)
+	case "avgValueSize":
+		s.AvgValueSize, err = strconv.Atoi(value)
+	case "policy":
+		s.Policy, err = ParsePolicy(value)
+	case "frequencyWindow":
+		err = s.FrequencyWindow.parse(value)
+	case "probationPercent":
+		s.ProbationPercent, err = strconv.Atoi(value)
+	case "admission":
+		s.Admission, err = strconv.ParseBool(value)
+	case "adaptiveBalance":
+		s.AdaptiveBalance, err = strconv.ParseBool(value)
+	case "targetHitRate":
+		s.TargetHitRate, err = strconv.ParseFloat(value, 64)
+	case "borrowPercent":
+		s.BorrowPercent, err = strconv.Atoi(value)
+	case "maxKeyBytes":
+		s.MaxKeyBytes, err = strconv.Atoi(value)
+	case "maxValueBytes":
+		err = s.MaxValueBytes.parse(value)
+	case "growChainLength":
+		s.GrowChainLength, err = strconv.ParseFloat(value, 64)
+	case "batchAccesses":
+		s.BatchAccesses, err = strconv.ParseBool(value)
+	case "frequencyLogFactor":
+		s.FrequencyLogFactor, err = strconv.Atoi(value)
+	case "evictionSamples":
+		s.EvictionSamples, err = strconv.Atoi(value)
+	case "adaptiveSweep":
+		s.AdaptiveSweep, err = strconv.ParseBool(value)
+	case "ttlResolution":
+		err = s.TTLResolution.parse(value)
+	default:
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return fmt.Errorf("%s: invalid value %q: %v", name, value, err)
+	}
+	return nil
+}
+
+// config resolves the spec into a validated Config
+func (s configSpec) config() (Config, error) {
+	switch {
+	case s.Capacity < 0:
+		return Config{}, fmt.Errorf("%w: capacity must not be negative", ErrInvalidConfig)
+	case s.Capacity > 0 && s.MemoryBudget > 0:
+		return Config{}, fmt.Errorf("%w: capacity and memoryBudget are mutually exclusive", ErrInvalidConfig)
+	case s.SweepPercent < 0 || s.SweepPercent > 100:
+		return Config{}, fmt.Errorf("%w: sweepPercent must be between 1 and 100 (0 = default), got %d",
+			ErrInvalidConfig, s.SweepPercent)
+	case s.AvgKeySize < 0 || s.AvgValueSize < 0:
+		return Config{}, fmt.Errorf("%w: avgKeySize and avgValueSize must not be negative", ErrInvalidConfig)
+	case s.FrequencyWindow < 0:
+		return Config{}, fmt.Errorf("%w: frequencyWindow must not be negative", ErrInvalidConfig)
+	case s.ProbationPercent < 0 || s.ProbationPercent > 99:
+		return Config{}, fmt.Errorf("%w: probationPercent must be between 0 and 99, got %d",
+			ErrInvalidConfig, s.ProbationPercent)
+	case s.TargetHitRate < 0 || s.TargetHitRate >= 1:
+		return Config{}, fmt.Errorf("%w: targetHitRate must be a fraction at least 0 and below 1, got %v",
+			ErrInvalidConfig, s.TargetHitRate)
+	case s.BorrowPercent < 0 || s.BorrowPercent > 100:
+		return Config{}, fmt.Errorf("%w: borrowPercent must be between 0 and 100, got %d",
+			ErrInvalidConfig, s.BorrowPercent)
+	case s.MaxKeyBytes < 0:
+		return Config{}, fmt.Errorf("%w: maxKeyBytes must not be negative", ErrInvalidConfig)
+	case s.MaxValueBytes > math.MaxInt64:
+		return Config{}, fmt.Errorf("%w: maxValueBytes overflows int64", ErrInvalidConfig)
+	case s.GrowChainLength != 0 && s.GrowChainLength < 1:
+		return Config{}, fmt.Errorf("%w: growChainLength must be 0 or at least 1, got %v",
+			ErrInvalidConfig, s.GrowChainLength)
+	case s.FrequencyLogFactor < 0 || s.FrequencyLogFactor > maxFrequencyLogFactor:
+		return Config{}, fmt.Errorf("%w: frequencyLogFactor must be between 0 and %d, got %d",
+			ErrInvalidConfig, maxFrequencyLogFactor, s.FrequencyLogFactor)
+	case s.EvictionSamples < 0:
+		return Config{}, fmt.Errorf("%w: evictionSamples must not be negative", ErrInvalidConfig)
+	case s.AdaptiveSweep && s.EvictionSamples > 0:
+		return Config{}, fmt.Errorf("%w: adaptiveSweep and evictionSamples are mutually exclusive", ErrInvalidConfig)
+	case s.TTLResolution != 0 && time.Duration(s.TTLResolution) < minTTLResolution:
+		return Config{}, fmt.Errorf("%w: ttlResolution must be 0 or at least %s, got %s",
+			ErrInvalidConfig, minTTLResolution, time.Duration(s.TTLResolution))
+	}
+
+	var cfg Config
+	switch {
+	case s.MemoryBudget > 0 && (s.AvgKeySize > 0 || s.AvgValueSize > 0):
+		cfg = ConfigFromMemorySizeFor(uint64(s.MemoryBudget), s.AvgKeySize, s.AvgValueSize)
+	case s.MemoryBudget > 0:
+		cfg = ConfigFromMemorySize(uint64(s.MemoryBudget))
+	case s.Capacity > 0 && (s.NumShards == 0 || s.SlotsPerShard == 0):
+		cfg = ConfigFromCapacity(s.Capacity)
+	case s.NumShards == 0 || s.SlotsPerShard == 0:
+		return Config{}, fmt.Errorf("%w: set capacity, memoryBudget, or both numShards and slotsPerShard",
+			ErrInvalidConfig)
+	}
+
+	if s.NumShards != 0 {
+		cfg.NumShards = s.NumShards
+	}
+	if s.SlotsPerShard != 0 {
+		cfg.SlotsPerShard = s.SlotsPerShard
+	}
+	if s.Capacity > 0 {
+		cfg.Capacity = s.Capacity
+	}
+	cfg.SweepPercent = s.SweepPercent
+	cfg.CollectStats = s.CollectStats
+	cfg.AvgKeySize = s.AvgKeySize
+	cfg.AvgValueSize = s.AvgValueSize
+	cfg.Policy = s.Policy
+	cfg.FrequencyWindow = time.Duration(s.FrequencyWindow)
+	cfg.ProbationPercent = s.ProbationPercent
+	cfg.Admission = s.Admission
+	cfg.AdaptiveBalance = s.AdaptiveBalance
+	cfg.TargetHitRate = s.TargetHitRate
+	cfg.BorrowPercent = s.BorrowPercent
+	cfg.MaxKeyBytes = s.MaxKeyBytes
+	cfg.MaxValueBytes = int64(s.MaxValueBytes)
+	cfg.GrowChainLength = s.GrowChainLength
+	cfg.BatchAccesses = s.BatchAccesses
+	cfg.FrequencyLogFactor = s.FrequencyLogFactor
+	cfg.EvictionSamples = s.EvictionSamples
+	cfg.AdaptiveSweep = s.AdaptiveSweep
+	cfg.TTLResolution = time.Duration(s.TTLResolution)
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("%w: %v%s", ErrInvalidConfig, err, powerOf2Hint(cfg))
+	}
+	return cfg, nil
+}
+
+// powerOf2Hint suggests the nearest valid values when shard or slot counts aren't powers of 2
+func powerOf2Hint(cfg Config) string {
+	for _, f := range []struct {
+		name string
+		n    int
+	}{{"numShards", cfg.NumShards}, {"slotsPerShard", cfg.SlotsPerShard}} {
+		if f.n > 0 && f.n&(f.n-1) != 0 {
+			up := nextPowerOf2(f.n)
+			return fmt.Sprintf(" (%s=%d: try %d or %d, or omit it and set capacity)", f.name, f.n, up/2, up)
+		}
+	}
+	return ""
+}
+
+// ConfigFromJSON parses a JSON cache configuration, for example:
+//
+//	{"capacity": 100000, "sweepPercent": 15, "collectStats": true}
+//	{"memoryBudget": "256MB", "avgKeySize": 32, "avgValueSize": 1024}
+//
+// Unknown fields are rejected. The result is validated; errors wrap ErrInvalidConfig.
+func ConfigFromJSON(data []byte) (Config, error) {
+	var spec configSpec
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return Config{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if dec.More() {
+		return Config{}, fmt.Errorf("%w: unexpected data after JSON object", ErrInvalidConfig)
+	}
+	return spec.config()
+}
+
+// ConfigFromYAML parses a cache configuration written as flat YAML ("key: value"
+// lines with the same keys as ConfigFromJSON; comments and quoted values are allowed).
+// Nested mappings and lists are not supported.
+func ConfigFromYAML(data []byte) (Config, error) {
+	var spec configSpec
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := stripYAMLComment(sc.Text())
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' || strings.HasPrefix(line, "- ") {
+			return Config{}, fmt.Errorf("%w: line %d: nested YAML is not supported", ErrInvalidConfig, lineNo)
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Config{}, fmt.Errorf("%w: line %d: expected \"key: value\"", ErrInvalidConfig, lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return Config{}, fmt.Errorf("%w: line %d: nested YAML is not supported", ErrInvalidConfig, lineNo)
+		}
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+			value = value[1 : n-1]
+		}
+		if err := spec.set(strings.TrimSpace(name), value); err != nil {
+			return Config{}, fmt.Errorf("%w: line %d: %v", ErrInvalidConfig, lineNo, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Config{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return spec.config()
+}
+
+// stripYAMLComment removes a trailing "# comment" outside of quotes and trailing space
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRightFunc(line[:i], unicode.IsSpace)
+		}
+	}
+	return strings.TrimRightFunc(line, unicode.IsSpace)
+}
+
+// ConfigFromFile loads a cache configuration from a .json, .yaml, or .yml file
+func ConfigFromFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		cfg, err = ConfigFromJSON(data)
+	case ".yaml", ".yml":
+		cfg, err = ConfigFromYAML(data)
+	default:
+		return Config{}, fmt.Errorf("cache: unsupported config file type %q (want .json, .yaml, or .yml)", ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// memorySize is a byte count that can be written as a number or a string with a unit
+type memorySize uint64
+
+func (m *memorySize) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return m.parse(s)
+	}
+	var n uint64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("memoryBudget: want a byte count or a string such as \"256MB\": %v", err)
+	}
+	*m = memorySize(n)
+	return nil
+}
+
+func (m *memorySize) parse(s string) error {
+	n, err := ParseMemory(s)
+	if err != nil {
+		return err
+	}
+	*m = memorySize(n)
+	return nil
+}
+
+// duration is a time.Duration written as a string such as "90s" or "5m"
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("want a duration string such as \"5m\": %v", err)
+	}
+	return d.parse(s)
+}
+
+func (d *duration) parse(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// ParseMemory parses a human-readable byte count such as "512", "64KB", "256 MB",
+// "1.5GiB", or the output of FormatMemory. Units are binary (1KB = 1024 bytes).
+func ParseMemory(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(s)
+	}
+	num, unit := s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+
+	var shift uint
+	switch unit {
+	case "", "B":
+		shift = 0
+	case "K", "KB", "KIB":
+		shift = 10
+	case "M", "MB", "MIB":
+		shift = 20
+	case "G", "GB", "GIB":
+		shift = 30
+	case "T", "TB", "TIB":
+		shift = 40
+	default:
+		return 0, fmt.Errorf("invalid memory size %q: unknown unit %q", s, s[i:])
+	}
+
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > (1<<64-1)>>shift {
+			return 0, fmt.Errorf("invalid memory size %q: overflows uint64", s)
+		}
+		return n << shift, nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid memory size %q", s)
+	}
+	bytes := f * float64(uint64(1)<<shift)
+	if bytes >= 1<<64 {
+		return 0, fmt.Errorf("invalid memory size %q: overflows uint64", s)
+	}
+	return uint64(bytes), nil
+}
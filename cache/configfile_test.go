@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromJSON(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 10000, "sweepPercent": 20, "collectStats": true}`))
+	if err != nil {
+		t.Fatalf("ConfigFromJSON failed: %v", err)
+	}
+	want := ConfigFromCapacity(10000)
+	want.SweepPercent = 20
+	want.CollectStats = true
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	cfg, err = ConfigFromJSON([]byte(`{"memoryBudget": "64MB", "avgKeySize": 16, "avgValueSize": 512}`))
+	if err != nil {
+		t.Fatalf("ConfigFromJSON failed: %v", err)
+	}
+	if want := ConfigFromMemorySizeFor(64<<20, 16, 512); cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	cfg, err = ConfigFromJSON([]byte(`{"memoryBudget": 1048576}`))
+	if err != nil {
+		t.Fatalf("ConfigFromJSON failed: %v", err)
+	}
+	if want := ConfigFromMemorySize(1 << 20); cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	// Explicit layout overrides the derived one
+	cfg, err = ConfigFromJSON([]byte(`{"numShards": 8, "slotsPerShard": 128, "capacity": 500}`))
+	if err != nil {
+		t.Fatalf("ConfigFromJSON failed: %v", err)
+	}
+	if want := (Config{NumShards: 8, SlotsPerShard: 128, Capacity: 500}); cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	cfg, err = ConfigFromJSON([]byte(`{"capacity": 1000, "maxKeyBytes": 256, "maxValueBytes": "1MB"}`))
+	if err != nil {
+		t.Fatalf("ConfigFromJSON failed: %v", err)
+	}
+	if cfg.MaxKeyBytes != 256 || cfg.MaxValueBytes != 1<<20 {
+		t.Errorf("Expected limits 256/1MB, got %d/%d", cfg.MaxKeyBytes, cfg.MaxValueBytes)
+	}
+}
+
+func TestConfigFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name, json, want string
+	}{
+		{"empty", `{}`, "set capacity, memoryBudget"},
+		{"unknown field", `{"capacty": 10}`, `unknown field "capacty"`},
+		{"both sizes", `{"capacity": 10, "memoryBudget": "1MB"}`, "mutually exclusive"},
+		{"not power of 2", `{"numShards": 100, "slotsPerShard": 64}`, "try 64 or 128"},
+		{"sweep range", `{"capacity": 10, "sweepPercent": 150}`, "sweepPercent must be between"},
+		{"bad unit", `{"memoryBudget": "12XB"}`, "unknown unit"},
+		{"wrong type", `{"numShards": "eight"}`, "numShards"},
+		{"trailing data", `{"capacity": 10} {}`, "unexpected data"},
+		{"negative key limit", `{"capacity": 10, "maxKeyBytes": -1}`, "maxKeyBytes must not be negative"},
+		{"growth below 1", `{"capacity": 10, "growChainLength": 0.5}`, "growChainLength must be 0 or at least 1"},
+		{"negative samples", `{"capacity": 10, "evictionSamples": -5}`, "evictionSamples must not be negative"},
+		{"sweep and samples", `{"capacity": 10, "adaptiveSweep": true, "evictionSamples": 5}`, "mutually exclusive"},
+		{"fine ttl resolution", `{"capacity": 10, "ttlResolution": "1ms"}`, "ttlResolution must be 0 or at least 100ms"},
+		{"log factor range", `{"capacity": 10, "frequencyLogFactor": 5000}`, "frequencyLogFactor must be between 0 and 1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConfigFromJSON([]byte(tt.json))
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Expected ErrInvalidConfig, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %q", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestConfigFromYAML(t *testing.T) {
+	cfg, err := ConfigFromYAML([]byte(`---
+# cache sizing
+memoryBudget: "256 MB"   # per instance
+sweepPercent: 10
+collectStats: true
+`))
+	if err != nil {
+		t.Fatalf("ConfigFromYAML failed: %v", err)
+	}
+	want := ConfigFromMemorySize(256 << 20)
+	want.SweepPercent = 10
+	want.CollectStats = true
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+
+	_, err = ConfigFromYAML([]byte("capacity: 10\nlimits:\n  numShards: 4\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2: nested") {
+		t.Errorf("Expected nested YAML error on line 2, got %v", err)
+	}
+	_, err = ConfigFromYAML([]byte("capacity: lots\n"))
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), `capacity: invalid value "lots"`) {
+		t.Errorf("Expected invalid value error, got %v", err)
+	}
+}
+
+func TestConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "cache.json")
+	yamlPath := filepath.Join(dir, "cache.yml")
+	if err := os.WriteFile(jsonPath, []byte(`{"capacity": 5000}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(yamlPath, []byte("capacity: 5000\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{jsonPath, yamlPath} {
+		cfg, err := ConfigFromFile(path)
+		if err != nil {
+			t.Fatalf("ConfigFromFile(%s) failed: %v", path, err)
+		}
+		if cfg != ConfigFromCapacity(5000) {
+			t.Errorf("ConfigFromFile(%s) = %+v", path, cfg)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"numShards": 3, "slotsPerShard": 64}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConfigFromFile(bad); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), bad) {
+		t.Errorf("Expected ErrInvalidConfig mentioning the path, got %v", err)
+	}
+	if _, err := ConfigFromFile(filepath.Join(dir, "cache.toml")); err == nil {
+		t.Error("Expected an error for an unsupported extension")
+	}
+}
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"64KB", 64 << 10},
+		{"256 MB", 256 << 20},
+		{"1.5GiB", 3 << 29},
+		{"2t", 2 << 40},
+		{FormatMemory(3 << 20), 3 << 20},
+	}
+	for _, tt := range tests {
+		got, err := ParseMemory(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMemory(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "MB", "12XB", "-1", "99999999999T"} {
+		if _, err := ParseMemory(in); err == nil {
+			t.Errorf("ParseMemory(%q) succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// configFlag is a flag.Value whose parsing and formatting are bound to a Config field
+type configFlag struct {
+	get func() string
+	set func(string) error
+}
+
+func (f configFlag) String() string {
+	if f.get == nil { // zero value used by flag.PrintDefaults
+		return ""
+	}
+	return f.get()
+}
+
+func (f configFlag) Set(s string) error { return f.set(s) }
+
+// RegisterFlags registers command-line flags that update c when fs is parsed
+// (fs == nil uses flag.CommandLine). The current values of c are the defaults.
+//
+//	-cache-capacity N       max entries; picks shards and slots for N
+//	-cache-memory SIZE      memory budget such as 256MB; picks capacity, shards and slots
+//	-cache-shards N         shard count (power of 2)
+//	-cache-slots N          slots per shard (power of 2)
+//	-cache-sweep PERCENT    percent of a shard scanned per eviction (1-100)
+//	-cache-stats            collect hit/miss/eviction counters
+//	-cache-avg-key-size N   average key size hint in bytes
+//	-cache-avg-value-size N average value size hint in bytes
+//	-cache-policy NAME      eviction policy: protected-freq, gdsf or lirs
+//	-cache-freq-window D    judge eviction by accesses in the last D to 2D (0 = lifetime)
+//	-cache-probation N      percent of a shard kept for entries not yet accessed twice (0-99)
+//	-cache-admission        reject inserts that would evict a more popular entry
+//	-cache-adaptive-balance learn the recency/frequency split from ghost hits (ARC-style)
+//	-cache-target-hit-rate F shrink capacity while the hit rate stays above F (0 = fixed)
+//	-cache-borrow N         percent of its capacity a full shard may borrow from others (0-100)
+//
+// Values are validated as they are parsed. Explicit -cache-shards/-cache-slots
+// take precedence over the layout chosen by -cache-capacity/-cache-memory
+// regardless of flag order.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	var shardsSet, slotsSet bool
+
+	// resize applies a sized layout, keeping explicitly set shard/slot counts
+	resize := func(sized Config) {
+		if !shardsSet {
+			c.NumShards = sized.NumShards
+		}
+		if !slotsSet {
+			c.SlotsPerShard = sized.SlotsPerShard
+		}
+		c.Capacity = sized.Capacity
+	}
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.Capacity) },
+		set: func(s string) error {
+			n, err := parseFlagInt(s, 1)
+			if err != nil {
+				return err
+			}
+			resize(ConfigFromCapacity(n))
+			return nil
+		},
+	}, "cache-capacity", "maximum number of cache entries")
+
+	fs.Var(configFlag{
+		get: func() string { return "" },
+		set: func(s string) error {
+			n, err := ParseMemory(s)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errors.New("must be positive")
+			}
+			sized := c.WithMemoryBudget(n)
+			resize(sized)
+			c.AvgKeySize, c.AvgValueSize = sized.AvgKeySize, sized.AvgValueSize
+			return nil
+		},
+	}, "cache-memory", "cache memory budget, e.g. 256MB (sets capacity, shards and slots)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.NumShards) },
+		set: func(s string) error {
+			n, err := parseFlagPowerOf2(s)
+			if err != nil {
+				return err
+			}
+			c.NumShards, shardsSet = n, true
+			return nil
+		},
+	}, "cache-shards", "number of cache shards (power of 2)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.SlotsPerShard) },
+		set: func(s string) error {
+			n, err := parseFlagPowerOf2(s)
+			if err != nil {
+				return err
+			}
+			c.SlotsPerShard, slotsSet = n, true
+			return nil
+		},
+	}, "cache-slots", "slots per cache shard (power of 2)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.SweepPercent) },
+		set: func(s string) error {
+			n, err := parseFlagInt(s, 1)
+			if err != nil {
+				return err
+			}
+			if n > 100 {
+				return errors.New("must be at most 100")
+			}
+			c.SweepPercent = n
+			return nil
+		},
+	}, "cache-sweep", "percent of a shard scanned per eviction (1-100)")
+
+	fs.BoolVar(&c.CollectStats, "cache-stats", c.CollectStats, "collect cache hit/miss/eviction counters")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.AvgKeySize) },
+		set: func(s string) (err error) {
+			c.AvgKeySize, err = parseFlagInt(s, 0)
+			return err
+		},
+	}, "cache-avg-key-size", "average key size in bytes (memory sizing hint)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.AvgValueSize) },
+		set: func(s string) (err error) {
+			c.AvgValueSize, err = parseFlagInt(s, 0)
+			return err
+		},
+	}, "cache-avg-value-size", "average value size in bytes (memory sizing hint)")
+
+	fs.TextVar(&c.Policy, "cache-policy", c.Policy, "cache eviction policy: protected-freq, gdsf or lirs")
+
+	fs.Var(configFlag{
+		get: func() string { return c.FrequencyWindow.String() },
+		set: func(s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return errors.New("must be a duration such as 5m")
+			}
+			if d < 0 {
+				return errors.New("must not be negative")
+			}
+			c.FrequencyWindow = d
+			return nil
+		},
+	}, "cache-freq-window", "judge eviction by accesses within this window, e.g. 5m (0 = lifetime frequency)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.ProbationPercent) },
+		set: func(s string) error {
+			n, err := parseFlagInt(s, 0)
+			if err != nil {
+				return err
+			}
+			if n > 99 {
+				return errors.New("must be at most 99")
+			}
+			c.ProbationPercent = n
+			return nil
+		},
+	}, "cache-probation", "percent of a shard kept for entries not yet accessed twice (0 = disabled)")
+
+	fs.BoolVar(&c.Admission, "cache-admission", c.Admission, "reject cache inserts that would evict a more frequently requested entry")
+
+	fs.BoolVar(&c.AdaptiveBalance, "cache-adaptive-balance", c.AdaptiveBalance,
+		"learn the cache's recency/frequency split from ghost hits (ARC-style)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.FormatFloat(c.TargetHitRate, 'g', -1, 64) },
+		set: func(s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+			if f < 0 || f >= 1 {
+				return errors.New("must be at least 0 and below 1")
+			}
+			c.TargetHitRate = f
+			return nil
+		},
+	}, "cache-target-hit-rate", "shrink cache capacity while the hit rate stays above this fraction, e.g. 0.95 (0 = fixed capacity)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.BorrowPercent) },
+		set: func(s string) error {
+			n, err := parseFlagInt(s, 0)
+			if err != nil {
+				return err
+			}
+			if n > 100 {
+				return errors.New("must be at most 100")
+			}
+			c.BorrowPercent = n
+			return nil
+		},
+	}, "cache-borrow", "percent of its capacity a full cache shard may borrow from emptier shards (0 = disabled)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.MaxKeyBytes) },
+		set: func(s string) (err error) {
+			c.MaxKeyBytes, err = parseFlagInt(s, 0)
+			return err
+		},
+	}, "cache-max-key-bytes", "reject cache keys longer than this many bytes (0 = unlimited)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.FormatInt(c.MaxValueBytes, 10) },
+		set: func(s string) error {
+			n, err := ParseMemory(s)
+			if err != nil {
+				return errors.New("must be a byte count such as 1048576 or 1MB")
+			}
+			if n > math.MaxInt64 {
+				return errors.New("overflows int64")
+			}
+			c.MaxValueBytes = int64(n)
+			return nil
+		},
+	}, "cache-max-value-bytes", "reject cache values larger than this, e.g. 1MB (0 = unlimited)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.FormatFloat(c.GrowChainLength, 'g', -1, 64) },
+		set: func(s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return errors.New("must be a number")
+			}
+			if f != 0 && f < 1 {
+				return errors.New("must be 0 or at least 1")
+			}
+			c.GrowChainLength = f
+			return nil
+		},
+	}, "cache-grow-chain-length", "double a cache shard's slots once its chains average more nodes than this (0 = fixed slots)")
+
+	fs.BoolVar(&c.BatchAccesses, "cache-batch-accesses", c.BatchAccesses,
+		"record cache hits in per-P batches instead of on every Get")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.FrequencyLogFactor) },
+		set: func(s string) error {
+			n, err := parseFlagInt(s, 0)
+			if err != nil {
+				return err
+			}
+			if n > maxFrequencyLogFactor {
+				return fmt.Errorf("must be at most %d", maxFrequencyLogFactor)
+			}
+			c.FrequencyLogFactor = n
+			return nil
+		},
+	}, "cache-frequency-log-factor", "raise cache frequencies with falling probability, like Redis's lfu-log-factor (0 = every hit counts)")
+
+	fs.Var(configFlag{
+		get: func() string { return strconv.Itoa(c.EvictionSamples) },
+		set: func(s string) (err error) {
+			c.EvictionSamples, err = parseFlagInt(s, 0)
+			return err
+		},
+	}, "cache-eviction-samples", "evict the best of this many live entries sampled from random slots instead of scanning a slot window (0 = window scan)")
+
+	fs.BoolVar(&c.AdaptiveSweep, "cache-adaptive-sweep", c.AdaptiveSweep,
+		"tune each cache shard's eviction scan width from its victim quality, starting at -cache-sweep")
+
+	fs.Var(configFlag{
+		get: func() string { return c.TTLResolution.String() },
+		set: func(s string) error {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return errors.New("must be a duration such as 100ms")
+			}
+			if d != 0 && d < minTTLResolution {
+				return fmt.Errorf("must be 0 or at least %s", minTTLResolution)
+			}
+			c.TTLResolution = d
+			return nil
+		},
+	}, "cache-ttl-resolution", "round cache entry TTLs up to a multiple of this, e.g. 100ms (0 = 1s)")
+}
+
+// parseFlagInt parses an integer flag value that must be at least minimum
+func parseFlagInt(s string, minimum int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.New("must be an integer")
+	}
+	if n < minimum {
+		return 0, fmt.Errorf("must be at least %d", minimum)
+	}
+	return n, nil
+}
+
+// parseFlagPowerOf2 parses a positive power-of-2 flag value, suggesting neighbours otherwise
+func parseFlagPowerOf2(s string) (int, error) {
+	n, err := parseFlagInt(s, 1)
+	if err != nil {
+		return 0, err
+	}
+	if n&(n-1) != 0 {
+		up := nextPowerOf2(n)
+		return 0, fmt.Errorf("must be a power of 2 (try %d or %d)", up/2, up)
+	}
+	return n, nil
+}
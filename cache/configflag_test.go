@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestConfigRegisterFlags(t *testing.T) {
+	cfg := ConfigFromCapacity(1000)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+
+	err := fs.Parse([]string{"-cache-shards", "8", "-cache-capacity", "50000", "-cache-sweep", "20", "-cache-stats"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	sized := ConfigFromCapacity(50000)
+	want := Config{NumShards: 8, SlotsPerShard: sized.SlotsPerShard, Capacity: 50000, SweepPercent: 20, CollectStats: true}
+	if cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Parsed config is invalid: %v", err)
+	}
+}
+
+func TestConfigRegisterFlagsMemory(t *testing.T) {
+	var cfg Config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-cache-avg-value-size", "400", "-cache-memory", "16MB"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if want := ConfigFromMemorySizeFor(16<<20, 0, 400); cfg != want {
+		t.Errorf("Expected %+v, got %+v", want, cfg)
+	}
+}
+
+func TestConfigRegisterFlagsValidation(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-cache-shards", "12"}, "try 8 or 16"},
+		{[]string{"-cache-slots", "0"}, "must be at least 1"},
+		{[]string{"-cache-sweep", "101"}, "at most 100"},
+		{[]string{"-cache-capacity", "lots"}, "must be an integer"},
+		{[]string{"-cache-memory", "12XB"}, "unknown unit"},
+		{[]string{"-cache-max-key-bytes", "-1"}, "must be at least 0"},
+		{[]string{"-cache-max-value-bytes", "huge"}, "must be a byte count"},
+		{[]string{"-cache-grow-chain-length", "0.5"}, "must be 0 or at least 1"},
+		{[]string{"-cache-frequency-log-factor", "5000"}, "must be at most 1000"},
+		{[]string{"-cache-eviction-samples", "-1"}, "must be at least 0"},
+		{[]string{"-cache-ttl-resolution", "soon"}, "must be a duration"},
+	}
+	for _, tt := range tests {
+		var cfg Config
+		var out bytes.Buffer
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&out)
+		cfg.RegisterFlags(fs)
+		err := fs.Parse(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%v) = %v, want error containing %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestConfigRegisterFlagsDefaults(t *testing.T) {
+	cfg := Config{NumShards: 16, SlotsPerShard: 256}
+	var out bytes.Buffer
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&out)
+	cfg.RegisterFlags(fs)
+	fs.PrintDefaults()
+
+	if !strings.Contains(out.String(), "(default 16)") || !strings.Contains(out.String(), "(default 256)") {
+		t.Errorf("Expected current values as defaults:\n%s", out.String())
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"math/bits"
+	"time"
+)
+
+// PutWithCost stores value like Put and records how expensive the entry is to
+// refetch on a miss (for example the backend latency of the load that produced it).
+// Among entries that are equally evictable by frequency and priority, cheaper
+// entries are chosen as victims first, so the cache keeps the entries whose misses
+// hurt the most. Costs are compared in power-of-two bands (1µs, 2µs, 4µs, ...), and
+// entries in the same band fall back to LRU order. Entries stored with Put have no
+// cost and are the cheapest to evict.
+//
+// Like priority, the cost sticks to the key until it is removed from the cache.
+func (c *CloxCache[K, V]) PutWithCost(key K, value V, cost time.Duration) bool {
+	if !c.Put(key, value) {
+		return false
+	}
+	c.SetCost(key, cost)
+	return true
+}
+
+// SetCost changes the recorded miss cost of a live or ghost key (see PutWithCost).
+// Negative costs are treated as zero. Returns false if the key is not in the cache.
+func (c *CloxCache[K, V]) SetCost(key K, cost time.Duration) bool {
+	cost = max(cost, 0)
+
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		node.cost.Store(int64(cost))
+		return true
+	}
+	return false
+}
+
+// Cost returns the recorded miss cost of a live or ghost key
+func (c *CloxCache[K, V]) Cost(key K) (time.Duration, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		return time.Duration(node.cost.Load()), true
+	}
+	return 0, false
+}
+
+// costBand buckets a miss cost into power-of-two microsecond bands so that costs
+// of similar magnitude compare equal (0 for entries without a cost)
+func costBand(cost int64) int {
+	return bits.Len64(uint64(cost / int64(time.Microsecond)))
+}
+
+// costWeight returns the extra weight a hit on an entry with the given miss cost adds
+// to the self-tuning objective. Every hit counts 1; a costed hit counts its cost in
+// microseconds, so uncosted hits weigh the same as a 1µs backend fetch.
+func costWeight(cost int64) uint64 {
+	return uint64(max(cost/int64(time.Microsecond)-1, 0))
+}
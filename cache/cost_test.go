@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCloxCachePutWithCost(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// The expensive entries are the oldest, so plain LRU would evict them first
+	for i := range 4 {
+		cache.PutWithCost(fmt.Sprintf("slow-%d", i), i, 50*time.Millisecond)
+	}
+	for i := range 4 {
+		cache.PutWithCost(fmt.Sprintf("fast-%d", i), i, time.Millisecond)
+	}
+	for i := range 4 {
+		cache.PutWithCost(fmt.Sprintf("new-%d", i), i, time.Millisecond)
+	}
+
+	for i := range 4 {
+		if _, ok := cache.Get(fmt.Sprintf("slow-%d", i)); !ok {
+			t.Errorf("Expensive entry slow-%d was evicted before cheap ones", i)
+		}
+		if _, ok := cache.Get(fmt.Sprintf("fast-%d", i)); ok {
+			t.Errorf("Cheap entry fast-%d survived", i)
+		}
+	}
+	if c, ok := cache.Cost("slow-0"); !ok || c != 50*time.Millisecond {
+		t.Errorf("Cost = %v, %v; want 50ms", c, ok)
+	}
+}
+
+func TestCloxCacheCostSimilarBand(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// Costs within the same power-of-two band fall back to LRU order
+	cache.PutWithCost("old", 0, 40*time.Millisecond)
+	cache.PutWithCost("a", 1, 33*time.Millisecond)
+	cache.PutWithCost("b", 2, 35*time.Millisecond)
+	cache.PutWithCost("c", 3, 37*time.Millisecond)
+	cache.PutWithCost("d", 4, 38*time.Millisecond)
+
+	if _, ok := cache.Get("old"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+}
+
+func TestCloxCacheSetCost(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+
+	if cache.SetCost("missing", time.Second) {
+		t.Error("SetCost on a missing key should fail")
+	}
+	cache.Put("a", 1)
+	if c, ok := cache.Cost("a"); !ok || c != 0 {
+		t.Errorf("Cost = %v, %v; want 0 for a plain Put", c, ok)
+	}
+	if !cache.SetCost("a", -time.Second) {
+		t.Fatal("SetCost failed")
+	}
+	if c, _ := cache.Cost("a"); c != 0 {
+		t.Errorf("Expected negative cost clamped to 0, got %v", c)
+	}
+	cache.SetCost("a", time.Second)
+	cache.Put("a", 2)
+	if c, _ := cache.Cost("a"); c != time.Second {
+		t.Errorf("Plain Put reset the cost to %v", c)
+	}
+}
+
+func TestCloxCacheCostWeightedHitRate(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64})
+	defer cache.Close()
+
+	cache.PutWithCost("slow", 1, 10*time.Microsecond)
+	cache.Put("plain", 2)
+
+	cache.Get("slow")
+	cache.Get("plain")
+	cache.Get("missing")
+	cache.Get("missing")
+
+	stats := cache.GetAdaptiveStats()[0]
+	if stats.WindowHitRate != 0.5 {
+		t.Errorf("WindowHitRate = %v, want 0.5", stats.WindowHitRate)
+	}
+	// One hit worth 10µs plus one uncosted hit over four Gets
+	if stats.WindowWeightedHitRate != 11.0/4 {
+		t.Errorf("WindowWeightedHitRate = %v, want %v", stats.WindowWeightedHitRate, 11.0/4)
+	}
+}
@@ -0,0 +1,112 @@
+package cache
+
+import "iter"
+
+// Copy-on-write snapshots.
+//
+// While a Snapshot runs, every shard remembers which of its slots the snapshot
+// has already read (a cursor, since slots are read in order). A write under the
+// shard lock to a slot the snapshot hasn't reached first saves that slot's live
+// entries, and the snapshot reads the saved copy instead of the chain when it gets
+// there. Lock-free value updates take the locked path for the duration, so every
+// write that can change what the snapshot sees goes through preserve. Only the
+// slots written during the snapshot are ever copied.
+
+// cowEntry is a live entry saved for a running Snapshot
+type cowEntry[K Key, V any] struct {
+	key   K
+	value V
+}
+
+// Snapshot returns an iterator over a consistent view of the live entries: every
+// entry yielded was live at the moment the iteration started, with the value it
+// had then, and entries stored, changed or removed afterwards are neither seen nor
+// missed. Writers keep going while it runs; the first write to each slot the
+// snapshot hasn't reached yet pays for copying that slot. Writes racing with the
+// start of the iteration may or may not be included.
+//
+// Entries are read without recording an access, and no locks are held while
+// yielding, so the loop body may use the cache, but must not start another
+// Snapshot: snapshots run one at a time and a second one waits for the first.
+func (c *CloxCache[K, V]) Snapshot() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.snapshotMu.Lock()
+		defer c.snapshotMu.Unlock()
+
+		// Lock every shard so the view starts at the same point in all of them
+		c.snapshotting.Store(true)
+		for i := range c.shards {
+			c.shards[i].mu.Lock()
+		}
+		floor := c.validFloor()
+		for i := range c.shards {
+			c.shards[i].cowSaved = make(map[int][]cowEntry[K, V])
+			c.shards[i].cowCursor = 0
+			c.shards[i].cowFloor = floor
+		}
+		for i := range c.shards {
+			c.shards[i].mu.Unlock()
+		}
+		defer func() {
+			for i := range c.shards {
+				c.shards[i].mu.Lock()
+				c.shards[i].cowSaved = nil
+				c.shards[i].mu.Unlock()
+			}
+			c.snapshotting.Store(false)
+		}()
+
+		for i := range c.shards {
+			shard := &c.shards[i]
+			slots := shard.slots()
+			for s := range slots {
+				shard.mu.Lock()
+				entries, saved := shard.cowSaved[s]
+				if saved {
+					delete(shard.cowSaved, s)
+				} else {
+					entries = c.liveEntries(shard, s)
+				}
+				shard.cowCursor = s + 1
+				shard.mu.Unlock()
+
+				for _, e := range entries {
+					if !yield(copyKey(e.key), e.value) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// preserve saves the live entries of the slot holding hash for the running
+// Snapshot before a write changes them. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) preserve(shard *shard[K, V], hash uint64) {
+	if shard.cowSaved == nil {
+		return
+	}
+	s := int((hash >> c.shardBits) & uint64(len(shard.slots())-1))
+	if s < shard.cowCursor {
+		return
+	}
+	if _, saved := shard.cowSaved[s]; !saved {
+		shard.cowSaved[s] = c.liveEntries(shard, s)
+	}
+}
+
+// liveEntries copies the entries of slot s that were live when the snapshot
+// started (an InvalidateAll since then doesn't hide them), leaving out those
+// past their TTL. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) liveEntries(shard *shard[K, V], s int) []cowEntry[K, V] {
+	var entries []cowEntry[K, V]
+	for node := shard.slots()[s].Load(); node != nil; node = node.next.Load() {
+		if node.freq.Load() <= 0 || node.gen.Load() < shard.cowFloor || c.expired(node) {
+			continue
+		}
+		if vp := node.value.Load(); vp != nil {
+			entries = append(entries, cowEntry[K, V]{key: node.fullKey(), value: *vp})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheSnapshotConsistent(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	seen := make(map[string]int)
+	first := true
+	for key, value := range cache.Snapshot() {
+		if first {
+			// Rewrite everything the snapshot hasn't read yet
+			first = false
+			for i := range 100 {
+				key := fmt.Sprintf("key-%d", i)
+				switch i % 3 {
+				case 0:
+					cache.Put(key, -1)
+				case 1:
+					cache.Delete(key)
+				default:
+					cache.Update(key, func(old int, _ bool) int { return old + 1000 })
+				}
+			}
+			for i := range 20 {
+				cache.Put(fmt.Sprintf("new-%d", i), i)
+			}
+			cache.InvalidateAll()
+		}
+		seen[key] = value
+	}
+
+	if len(seen) != 100 {
+		t.Errorf("Snapshot yielded %d entries, want 100", len(seen))
+	}
+	for i := range 100 {
+		if v, ok := seen[fmt.Sprintf("key-%d", i)]; !ok || v != i {
+			t.Errorf("key-%d = %d, %v in snapshot; want %d as of the start", i, v, ok, i)
+		}
+	}
+	if cache.snapshotting.Load() {
+		t.Error("Snapshot still marked running after the iteration")
+	}
+	for i := range cache.shards {
+		if cache.shards[i].cowSaved != nil {
+			t.Errorf("Shard %d kept saved slots after the iteration", i)
+		}
+	}
+}
+
+func TestCloxCacheSnapshotSkipsExpired(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 2, SlotsPerShard: 64, Capacity: 128})
+	defer cache.Close()
+
+	cache.Put("kept", 1)
+	cache.PutWithTTL("expiring", 2, time.Hour)
+	// Move the expiry watermark past every bucket without reaping, as if the
+	// reaper hadn't reached the entry yet
+	cache.ttlClock.Store(math.MaxUint32)
+
+	seen := make(map[string]int)
+	for key, value := range cache.Snapshot() {
+		seen[key] = value
+	}
+	if len(seen) != 1 || seen["kept"] != 1 {
+		t.Errorf("Snapshot yielded %v, want only kept", seen)
+	}
+}
+
+func TestCloxCacheSnapshotBreak(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	for range cache.Snapshot() {
+		break
+	}
+	if cache.snapshotting.Load() {
+		t.Error("Snapshot still marked running after break")
+	}
+	n := 0
+	for range cache.Snapshot() {
+		n++
+	}
+	if n != 10 {
+		t.Errorf("Second snapshot yielded %d entries, want 10", n)
+	}
+}
+
+func TestCloxCacheSnapshotConcurrentWriters(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 64 {
+		cache.Put(fmt.Sprintf("key-%d", i), 0)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				key := fmt.Sprintf("key-%d", (i*7+w)%64)
+				cache.Put(key, i)
+				cache.Get(key)
+				if i%5 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}()
+	}
+
+	for range 20 {
+		seen := make(map[string]bool)
+		for key := range cache.Snapshot() {
+			if seen[key] {
+				t.Fatalf("Snapshot yielded %s twice", key)
+			}
+			seen[key] = true
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after concurrent snapshots: %v", report.Issues)
+	}
+}
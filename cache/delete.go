@@ -0,0 +1,124 @@
+package cache
+
+import "sync/atomic"
+
+// Delete removes key from the cache, including any ghost frequency history.
+// Returns true if a live entry was removed.
+func (c *CloxCache[K, V]) Delete(key K) bool {
+	if c.closed.Load() {
+		return false
+	}
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	slot := c.slotIn(shard, hash)
+	var prev *recordNode[K, V]
+	for node := slot.Load(); node != nil; node = node.next.Load() {
+		if c.matches(node, hash, hi, key) {
+			return c.removeLocked(shard, slot, prev, node, EvictReasonDeleted)
+		}
+		prev = node
+	}
+	return false
+}
+
+// DeleteFunc removes every live entry for which fn returns true, taking each
+// shard lock once, and returns the number of entries removed. fn runs while the
+// shard lock is held, so it must not call back into the cache.
+func (c *CloxCache[K, V]) DeleteFunc(fn func(key K, value V) bool) int {
+	if c.closed.Load() {
+		return 0
+	}
+	removed := 0
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		slots := shard.slots()
+		for s := range slots {
+			slot := &slots[s]
+			var prev *recordNode[K, V]
+			for node := slot.Load(); node != nil; {
+				next := node.next.Load()
+				if node.freq.Load() > 0 && c.stale(node) {
+					c.removeLocked(shard, slot, prev, node, c.staleReason(node))
+				} else if vp := node.value.Load(); node.freq.Load() > 0 && vp != nil && fn(node.fullKey(), *vp) {
+					c.removeLocked(shard, slot, prev, node, EvictReasonDeleted)
+					removed++
+				} else {
+					prev = node
+				}
+				node = next
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return removed
+}
+
+// unlinkLocked finds a live node's predecessor in its chain and removes it with
+// removeLocked. Returns false if the node already left the live set.
+func (c *CloxCache[K, V]) unlinkLocked(shard *shard[K, V], node *recordNode[K, V], reason EvictReason) bool {
+	if node.freq.Load() <= 0 {
+		return false
+	}
+	slot := c.slotIn(shard, node.keyHash)
+	var prev *recordNode[K, V]
+	for n := slot.Load(); n != nil; n = n.next.Load() {
+		if n == node {
+			return c.removeLocked(shard, slot, prev, node, reason)
+		}
+		prev = n
+	}
+	return false
+}
+
+// removeLocked unlinks node (live or ghost) from its chain and fixes the shard
+// counters. prev is the node's predecessor in slot (nil if it is the head).
+// Live nodes are reported to OnEvict with reason.
+// Returns true if the node was live. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) removeLocked(shard *shard[K, V], slot *atomic.Pointer[recordNode[K, V]],
+	prev, node *recordNode[K, V], reason EvictReason) bool {
+	return c.removeNodeLocked(shard, slot, prev, node, reason, true)
+}
+
+// removeNodeLocked is removeLocked that leaves the key's overflow value in place
+// unless dropOverflow is set. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) removeNodeLocked(shard *shard[K, V], slot *atomic.Pointer[recordNode[K, V]],
+	prev, node *recordNode[K, V], reason EvictReason, dropOverflow bool) bool {
+	c.preserve(shard, node.keyHash)
+	key := node.fullKey()
+	next := node.next.Load()
+	if prev == nil {
+		slot.Store(next)
+	} else {
+		prev.next.Store(next)
+	}
+	c.unlinked(node)
+	if dropOverflow {
+		c.dropOverflow(key)
+	}
+
+	// Zero the frequency so lock-free Puts holding a stale reference take the locked path
+	f := node.freq.Swap(0)
+	shard.liveBytes.Add(-node.size.Swap(0))
+	vp := node.value.Swap(nil)
+	c.valueChanged(key, vp, nil)
+	c.finalize(shard, key, vp)
+	if f <= 0 {
+		shard.ghostCount.Add(-1)
+		return false
+	}
+
+	shard.entryCount.Add(-1)
+	c.classLive(shard, node, -1)
+	c.lirsLeft(shard, node)
+	c.ttlLeft(shard, node)
+	c.freqChanged(shard, f, 0)
+	if c.hooks != nil && c.hooks.OnEvict != nil && vp != nil {
+		c.hooks.OnEvict(key, *vp, reason)
+	}
+	return true
+}
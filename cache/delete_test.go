@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheDelete(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	if !cache.Delete("a") {
+		t.Error("Expected Delete to report a live entry")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Deleted key is still readable")
+	}
+	if cache.Delete("a") {
+		t.Error("Second Delete should report nothing removed")
+	}
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Errorf("Unrelated key affected: %d, %v", v, ok)
+	}
+
+	// Re-insert after delete starts from scratch
+	cache.Put("a", 3)
+	if v, ok := cache.Get("a"); !ok || v != 3 {
+		t.Errorf("Re-inserted key = %d, %v; want 3", v, ok)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after delete: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheDeleteGhost(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 16, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	var ghost string
+	for _, g := range cache.Ghosts() {
+		ghost = g.Key
+		break
+	}
+	if ghost == "" {
+		t.Fatal("Expected at least one ghost")
+	}
+
+	before, _ := cache.GhostCount()
+	if cache.Delete(ghost) {
+		t.Error("Deleting a ghost should not report a live entry")
+	}
+	if cache.IsGhost(ghost) {
+		t.Error("Ghost history should be dropped by Delete")
+	}
+	if after, _ := cache.GhostCount(); after != before-1 {
+		t.Errorf("Expected ghost count %d, got %d", before-1, after)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after deleting a ghost: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheDeleteFunc(t *testing.T) {
+	var deleted []string
+	var mu sync.Mutex
+	cache := NewCloxCache(Config{NumShards: 4, SlotsPerShard: 256, Capacity: 500},
+		WithHooks(Hooks[string, int]{
+			OnEvict: func(key string, _ int, reason EvictReason) {
+				if reason == EvictReasonDeleted {
+					mu.Lock()
+					deleted = append(deleted, key)
+					mu.Unlock()
+				}
+			},
+		}))
+	defer cache.Close()
+
+	for tenant := range 3 {
+		for i := range 20 {
+			cache.Put(fmt.Sprintf("tenant-%d/item-%d", tenant, i), i)
+		}
+	}
+	sizeBefore := cache.SizeBytes()
+
+	removed := cache.DeleteFunc(func(key string, _ int) bool {
+		return strings.HasPrefix(key, "tenant-1/")
+	})
+	if removed != 20 || len(deleted) != 20 {
+		t.Errorf("Expected 20 removed and 20 hook calls, got %d and %d", removed, len(deleted))
+	}
+	for i := range 20 {
+		if _, ok := cache.Get(fmt.Sprintf("tenant-1/item-%d", i)); ok {
+			t.Errorf("tenant-1/item-%d survived DeleteFunc", i)
+		}
+		if _, ok := cache.Get(fmt.Sprintf("tenant-2/item-%d", i)); !ok {
+			t.Errorf("tenant-2/item-%d was removed", i)
+		}
+	}
+	if cache.SizeBytes() >= sizeBefore {
+		t.Errorf("Expected size to shrink from %d, got %d", sizeBefore, cache.SizeBytes())
+	}
+
+	// Values are passed to the predicate
+	if removed := cache.DeleteFunc(func(_ string, v int) bool { return v >= 10 }); removed != 20 {
+		t.Errorf("Expected 20 removed by value, got %d", removed)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after DeleteFunc: %v", report.Issues)
+	}
+}
@@ -0,0 +1,144 @@
+package cache
+
+import "sync"
+
+// depGraph records which keys were derived from which dependencies
+type depGraph struct {
+	mu         sync.Mutex
+	dependents map[string]map[string]struct{} // dependency -> keys derived from it
+	deps       map[string][]string            // key -> its dependencies
+}
+
+func newDepGraph() *depGraph {
+	return &depGraph{
+		dependents: make(map[string]map[string]struct{}),
+		deps:       make(map[string][]string),
+	}
+}
+
+// set replaces the dependencies of key
+func (g *depGraph) set(key string, deps []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.forgetLocked(key)
+	if len(deps) == 0 {
+		return
+	}
+	g.deps[key] = deps
+	for _, dep := range deps {
+		keys := g.dependents[dep]
+		if keys == nil {
+			keys = make(map[string]struct{})
+			g.dependents[dep] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// has reports whether key has dependencies recorded
+func (g *depGraph) has(key string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	_, ok := g.deps[key]
+	return ok
+}
+
+// forget drops key's dependencies
+func (g *depGraph) forget(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.forgetLocked(key)
+}
+
+func (g *depGraph) forgetLocked(key string) {
+	for _, dep := range g.deps[key] {
+		keys := g.dependents[dep]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(g.dependents, dep)
+		}
+	}
+	delete(g.deps, key)
+}
+
+// closure returns every key that depends on dep, directly or transitively
+func (g *depGraph) closure(dep string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var out []string
+	seen := map[string]struct{}{dep: {}}
+	queue := []string{dep}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for key := range g.dependents[next] {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, key)
+			queue = append(queue, key)
+		}
+	}
+	return out
+}
+
+// PutWithDeps stores value like Put and records that it was derived from deps
+// (typically other cache keys), replacing any dependencies previously recorded
+// for key. A later InvalidateDep on any of deps removes the entry. A plain Put to
+// key keeps its recorded dependencies; they are dropped when the entry leaves the
+// cache (Delete, eviction, or expiry). Returns false if the value wasn't stored,
+// or was deleted again because an InvalidateDep on its deps ran during the Put.
+func (c *CloxCache[K, V]) PutWithDeps(key K, value V, deps ...K) bool {
+	g := c.deps.Load()
+	if g == nil {
+		c.deps.CompareAndSwap(nil, newDepGraph())
+		g = c.deps.Load()
+	}
+
+	names := make([]string, len(deps))
+	for i, dep := range deps {
+		names[i] = string(dep)
+	}
+	// Record before storing, so an InvalidateDep after the Put finds the entry. One
+	// that runs during the Put misses the entry and forgets the record instead;
+	// the value may be built from the invalidated parts, so it is deleted here (or
+	// by InvalidateDep, if it was stored before the record was forgotten).
+	g.set(string(key), names)
+	if !c.Put(key, value) {
+		g.forget(string(key))
+		return false
+	}
+	if len(names) > 0 && !g.has(string(key)) {
+		c.Delete(key)
+		return false
+	}
+	return true
+}
+
+// InvalidateDep deletes every entry derived from dep, directly or through other
+// derived entries, and returns the number of live entries removed. dep itself is
+// not removed. Entries are deleted one at a time, not atomically.
+func (c *CloxCache[K, V]) InvalidateDep(dep K) int {
+	g := c.deps.Load()
+	if g == nil {
+		return 0
+	}
+
+	removed := 0
+	for _, key := range g.closure(string(dep)) {
+		k := keyFromString[K](key)
+		if c.Delete(k) {
+			removed++ // removal already dropped its dependencies
+			continue
+		}
+		// Not cached (yet): a PutWithDeps in flight stores after its record is
+		// gone and deletes its own entry, unless it stored before the forget
+		g.forget(key)
+		if c.Delete(k) {
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheInvalidateDep(t *testing.T) {
+	cache := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	cache.Put("user:1", "alice")
+	cache.Put("user:2", "bob")
+	cache.PutWithDeps("fragment:header", "<h1>alice</h1>", "user:1")
+	cache.PutWithDeps("fragment:list", "<li>alice</li><li>bob</li>", "user:1", "user:2")
+	cache.PutWithDeps("page:home", "<html>...</html>", "fragment:header")
+	cache.PutWithDeps("fragment:footer", "<footer/>", "user:2")
+
+	if removed := cache.InvalidateDep("user:1"); removed != 3 {
+		t.Errorf("Expected 3 dependents removed, got %d", removed)
+	}
+	for _, key := range []string{"fragment:header", "fragment:list", "page:home"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("%s survived invalidation of user:1", key)
+		}
+	}
+	for _, key := range []string{"user:1", "user:2", "fragment:footer"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("%s should not have been invalidated", key)
+		}
+	}
+
+	// Removed entries no longer hold dependency edges
+	g := cache.deps.Load()
+	if _, ok := g.dependents["user:1"]; ok {
+		t.Error("user:1 still has dependents after invalidation")
+	}
+	if deps := g.deps["fragment:footer"]; len(deps) != 1 {
+		t.Errorf("fragment:footer deps = %v", deps)
+	}
+
+	if removed := cache.InvalidateDep("unknown"); removed != 0 {
+		t.Errorf("Expected nothing removed for an unknown dep, got %d", removed)
+	}
+}
+
+func TestCloxCachePutWithDepsReplaces(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	cache.PutWithDeps("derived", 1, "a")
+	cache.PutWithDeps("derived", 2, "b")
+	if removed := cache.InvalidateDep("a"); removed != 0 {
+		t.Errorf("Replaced dependency a still invalidates derived (%d removed)", removed)
+	}
+
+	// A plain Put keeps the recorded dependencies
+	cache.Put("derived", 3)
+	if removed := cache.InvalidateDep("b"); removed != 1 {
+		t.Errorf("Expected derived to be invalidated by b, got %d", removed)
+	}
+
+	// Cycles terminate, and the invalidated dep itself is kept
+	cache.PutWithDeps("x", 1, "y")
+	cache.PutWithDeps("y", 2, "x")
+	if removed := cache.InvalidateDep("x"); removed != 1 {
+		t.Errorf("Expected only y removed, got %d", removed)
+	}
+	if _, ok := cache.Get("x"); !ok {
+		t.Error("The invalidated dep itself should not be removed")
+	}
+}
+
+func TestCloxCacheDepsDroppedOnEviction(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 100 {
+		cache.PutWithDeps(fmt.Sprintf("derived-%d", i), i, "base")
+	}
+	g := cache.deps.Load()
+	g.mu.Lock()
+	tracked := len(g.dependents["base"])
+	g.mu.Unlock()
+	if live := int(cache.shards[0].entryCount.Load()); tracked != live {
+		t.Errorf("Graph tracks %d dependents, cache holds %d live entries", tracked, live)
+	}
+}
+
+func TestCloxCachePutWithDepsRacingInvalidate(t *testing.T) {
+	var cache *CloxCache[string, string]
+	// The weigher runs before the new entry is stored, so it can invalidate the
+	// dependency while the Put is in flight
+	invalidated := false
+	cache = NewCloxCache(Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100},
+		WithWeigher(func(key, value string) int64 {
+			if key == "fragment:header" && !invalidated {
+				invalidated = true
+				cache.InvalidateDep("user:1")
+			}
+			return int64(len(key) + len(value))
+		}))
+	defer cache.Close()
+
+	cache.Put("user:1", "alice")
+	if cache.PutWithDeps("fragment:header", "<h1>alice</h1>", "user:1") {
+		t.Error("PutWithDeps reported success for a value invalidated during the Put")
+	}
+	if !invalidated {
+		t.Fatal("InvalidateDep did not run during the Put")
+	}
+	if _, ok := cache.Get("fragment:header"); ok {
+		t.Error("A value built from invalidated parts survived without its dependency record")
+	}
+
+	// Stored again after the invalidation, it is tracked as usual
+	if !cache.PutWithDeps("fragment:header", "<h1>alice</h1>", "user:1") {
+		t.Fatal("PutWithDeps failed")
+	}
+	if removed := cache.InvalidateDep("user:1"); removed != 1 {
+		t.Errorf("InvalidateDep removed %d entries, want 1", removed)
+	}
+}
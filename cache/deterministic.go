@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Deterministic mode (WithDeterministic).
+//
+// Adaptation is already keyed to operation counts: timestamps are logical, and
+// each shard re-tunes k every adaptiveCheckInterval evictions. What keeps two
+// runs over the same operations from matching is everything else the policy
+// consults: the random slots sampled for eviction (EvictionSamples), the random
+// draws behind probabilistic frequency increments (FrequencyLogFactor), the
+// wall clock that starts frequency epochs (FrequencyWindow) and places TTL
+// deadlines, the goroutine that advances the TTL watermark on its own schedule,
+// and batched access recording, whose pooled buffers are dropped at GC time.
+// Deterministic mode draws from a seeded generator, reads the injected clock,
+// moves the TTL watermark only on Tick and records every hit inline, so a
+// single goroutine replaying a trace sees the same k trajectory and the same
+// evictions every time. Concurrent callers still interleave as the scheduler
+// pleases.
+
+// WithDeterministic makes the cache's policy decisions a function of the
+// operations it sees: random choices come from a generator seeded with seed,
+// and now (nil = a clock frozen at the Unix epoch) stands in for the wall clock.
+// The TTL clock then only advances when Tick is called, and
+// Config.BatchAccesses is ignored. Meant for debugging the policy and for
+// replaying traces reproducibly, not for production.
+func WithDeterministic[K Key, V any](seed uint64, now func() time.Time) Option[K, V] {
+	if now == nil {
+		now = func() time.Time { return time.Unix(0, 0) }
+	}
+	return func(c *CloxCache[K, V]) {
+		c.clock = now
+		c.rng = rand.New(rand.NewPCG(seed, seed))
+		c.accessBuffers = nil
+		start := now().UnixNano()
+		c.ttlBase = start
+		for i := range c.shards {
+			c.shards[i].epochStart.Store(start)
+		}
+	}
+}
+
+// Deterministic reports whether the cache was built WithDeterministic
+func (c *CloxCache[K, V]) Deterministic() bool {
+	return c.clock != nil
+}
+
+// Tick advances the TTL clock to the current time, expiring the entries whose
+// TTL has passed. Without WithDeterministic a background goroutine does this
+// every TTLResolution and Tick is never needed; in deterministic mode nothing
+// expires until it is called.
+func (c *CloxCache[K, V]) Tick() {
+	if !c.closed.Load() {
+		c.advanceTTL(c.now())
+	}
+}
+
+// now returns the current time from the injected clock, if any
+func (c *CloxCache[K, V]) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// randN returns a random number in [0, n). Deterministic draws are serialized,
+// which callers racing each other would make nondeterministic anyway.
+func (c *CloxCache[K, V]) randN(n uint32) uint32 {
+	if c.rng == nil {
+		return rand.Uint32N(n)
+	}
+	c.rngMu.Lock()
+	defer c.rngMu.Unlock()
+	return c.rng.Uint32N(n)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// deterministicRun replays a skewed trace through a deterministic cache with
+// every randomized policy feature on, and returns the evicted keys in order and
+// each shard's k after every 1000 operations
+func deterministicRun(seed uint64) (evicted []string, ks []int32) {
+	cfg := ConfigFromCapacity(2000)
+	cfg.EvictionSamples = 5
+	cfg.FrequencyLogFactor = 10
+	cfg.FrequencyWindow = time.Second
+	cfg.BatchAccesses = true
+
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewCloxCache[string, int](cfg,
+		WithDeterministic[string, int](seed, func() time.Time { return now }),
+		WithHooks(Hooks[string, int]{
+			OnEvict: func(key string, _ int, reason EvictReason) {
+				evicted = append(evicted, fmt.Sprintf("%s/%v", key, reason))
+			},
+		}))
+	defer cache.Close()
+
+	z := newZipfGenerator(20000, 0.9, 7)
+	for i := range 100000 {
+		now = now.Add(time.Millisecond)
+		key := fmt.Sprintf("key-%d", z.next())
+		if _, ok := cache.Get(key); !ok {
+			cache.PutWithTTL(key, i, time.Duration(i%50)*time.Second)
+		}
+		if i%1000 == 999 {
+			cache.Tick()
+			for _, s := range cache.GetAdaptiveStats() {
+				ks = append(ks, s.K)
+			}
+		}
+	}
+	return evicted, ks
+}
+
+func TestCloxCacheDeterministic(t *testing.T) {
+	evicted, ks := deterministicRun(42)
+	if len(evicted) == 0 {
+		t.Fatal("Trace caused no evictions")
+	}
+	for run := range 2 {
+		again, againKs := deterministicRun(42)
+		if !slices.Equal(evicted, again) {
+			t.Fatalf("Run %d: evictions diverged (%d vs %d evictions)", run+2, len(evicted), len(again))
+		}
+		if !slices.Equal(ks, againKs) {
+			t.Fatalf("Run %d: k trajectories diverged", run+2)
+		}
+	}
+
+	// Sampling depends on the seed, so another one picks other victims
+	if other, _ := deterministicRun(43); slices.Equal(evicted, other) {
+		t.Error("Different seeds evicted the same keys")
+	}
+}
+
+func TestCloxCacheDeterministicTick(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cache := NewCloxCache[string, int](ConfigFromCapacity(100),
+		WithDeterministic[string, int](1, func() time.Time { return now }))
+	defer cache.Close()
+
+	if !cache.Deterministic() {
+		t.Fatal("Deterministic() = false")
+	}
+	cache.PutWithTTL("a", 1, time.Second)
+	if ttl, _ := cache.TTL("a"); ttl != time.Second {
+		t.Errorf("TTL = %v, want 1s from the injected clock", ttl)
+	}
+
+	now = now.Add(2 * time.Second)
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Entry expired before Tick")
+	}
+	cache.Tick()
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Entry still live after Tick past its TTL")
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import "fmt"
+
+// Anomaly is a single invariant violation found by Diagnose
+type Anomaly struct {
+	Shard  int
+	Check  string // short identifier of the failed check
+	Detail string
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("shard %d: %s: %s", a.Shard, a.Check, a.Detail)
+}
+
+// Diagnosis is the result of a self-diagnostic pass
+type Diagnosis struct {
+	ShardsChecked int
+	Anomalies     []Anomaly
+}
+
+// Healthy reports whether no anomalies were found
+func (d Diagnosis) Healthy() bool {
+	return len(d.Anomalies) == 0
+}
+
+// Healthy runs Diagnose and reports whether all invariants hold.
+// Suitable for readiness probes; it briefly locks each shard in turn.
+func (c *CloxCache[K, V]) Healthy() bool {
+	return c.Diagnose().Healthy()
+}
+
+// Diagnose checks per-shard invariants and reports anomalies:
+// counters that drifted from the actual chain contents, ghost and live counts
+// outside their bounds, adaptive state out of range, and a stalled CLOCK hand.
+// Each shard is locked while it is checked, so counts are exact.
+func (c *CloxCache[K, V]) Diagnose() Diagnosis {
+	var d Diagnosis
+	for i := range c.shards {
+		d.Anomalies = append(d.Anomalies, c.diagnoseShard(i)...)
+		d.ShardsChecked++
+	}
+	return d
+}
+
+func (c *CloxCache[K, V]) diagnoseShard(shardID int) []Anomaly {
+	shard := &c.shards[shardID]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	var anomalies []Anomaly
+	report := func(check, format string, args ...any) {
+		anomalies = append(anomalies, Anomaly{Shard: shardID, Check: check, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	var live, ghosts int64
+	slots := shard.slots()
+	for s := range slots {
+		for node := slots[s].Load(); node != nil; node = node.next.Load() {
+			if node.freq.Load() > 0 {
+				live++
+			} else {
+				ghosts++
+			}
+		}
+	}
+
+	entryCount := shard.entryCount.Load()
+	ghostCount := shard.ghostCount.Load()
+	if entryCount != live {
+		report("entry-count-drift", "entryCount=%d but chains hold %d live entries", entryCount, live)
+	}
+	if ghostCount != ghosts {
+		report("ghost-count-drift", "ghostCount=%d but chains hold %d ghosts", ghostCount, ghosts)
+	}
+	if ghostCount < 0 || ghostCount > shard.ghostCapacity {
+		report("ghost-bounds", "ghostCount=%d outside [0, %d]", ghostCount, shard.ghostCapacity)
+	}
+	// Ghost promotions may briefly overshoot capacity until the next insert evicts,
+	// and a shard may hold up to borrowCapacity more than its own (see BorrowPercent)
+	if bound := shard.capacity + shard.borrowCapacity + shard.ghostCapacity; entryCount < 0 || entryCount > bound {
+		report("entry-bounds", "entryCount=%d outside [0, %d]", entryCount, bound)
+	}
+	if bytes := shard.liveBytes.Load(); bytes < 0 {
+		report("negative-size", "liveBytes=%d", bytes)
+	}
+
+	if k := shard.k.Load(); k < 1 || k > maxFrequency-1 {
+		report("k-bounds", "k=%d outside [1, %d]", k, maxFrequency-1)
+	}
+	if low := shard.rateLow.Load(); low < minRateLow || low > maxRateLow {
+		report("rate-low-bounds", "rateLow=%d outside [%d, %d]", low, minRateLow, maxRateLow)
+	}
+	if high := shard.rateHigh.Load(); high < minRateHigh || high > maxRateHigh {
+		report("rate-high-bounds", "rateHigh=%d outside [%d, %d]", high, minRateHigh, maxRateHigh)
+	}
+
+	// Every scanning eviction advances the hand, so evictions with a hand at zero
+	// mean it is stuck. Sampled eviction (EvictionSamples) doesn't use the hand.
+	evicted := shard.evictedUnprotected.Load() + shard.evictedProtected.Load()
+	if c.config.EvictionSamples == 0 && evicted > 0 && shard.hand.Load() == 0 {
+		report("hand-stalled", "%d evictions recorded but the CLOCK hand never advanced", evicted)
+	}
+
+	return anomalies
+}
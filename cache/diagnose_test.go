@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCloxCacheDiagnoseHealthy(t *testing.T) {
+	cfg := Config{
+		NumShards:     4,
+		SlotsPerShard: 64,
+		Capacity:      64,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("key-%d", i%300), i)
+		cache.Get(fmt.Sprintf("key-%d", i%50))
+	}
+
+	d := cache.Diagnose()
+	if !d.Healthy() {
+		t.Fatalf("Expected healthy cache, got anomalies: %v", d.Anomalies)
+	}
+	if d.ShardsChecked != 4 {
+		t.Errorf("Expected 4 shards checked, got %d", d.ShardsChecked)
+	}
+	if !cache.Healthy() {
+		t.Error("Healthy() disagrees with Diagnose()")
+	}
+}
+
+func TestCloxCacheDiagnoseSampledEviction(t *testing.T) {
+	// Sampled eviction never moves the CLOCK hand, which isn't a stall
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 64, EvictionSamples: 5}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if d := cache.Diagnose(); !d.Healthy() {
+		t.Errorf("Expected healthy cache, got anomalies: %v", d.Anomalies)
+	}
+}
+
+func TestCloxCacheDiagnoseBorrowedCapacity(t *testing.T) {
+	// A shard that borrowed capacity legitimately holds more than its own share
+	cfg := Config{NumShards: 2, SlotsPerShard: 8, Capacity: 16, BorrowPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	full := &cache.shards[0]
+	for i := 0; full.borrowed.Load() < full.borrowCapacity && i < 10000; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if shard, _ := cache.locate(hashKey(key)); shard == full {
+			cache.Put(key, i)
+		}
+	}
+	if full.entryCount.Load() <= full.capacity+full.ghostCapacity {
+		t.Fatalf("Shard holds %d entries, not more than its own capacity %d plus ghosts %d",
+			full.entryCount.Load(), full.capacity, full.ghostCapacity)
+	}
+	if d := cache.Diagnose(); !d.Healthy() {
+		t.Errorf("Expected healthy cache, got anomalies: %v", d.Anomalies)
+	}
+}
+
+func TestCloxCacheDiagnoseDetectsDrift(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 2, SlotsPerShard: 64})
+	defer cache.Close()
+
+	cache.Put("key", 1)
+	shard, _ := cache.locate(hashKey("key"))
+	shard.entryCount.Add(5)    // simulate a counter drift bug
+	shard.ghostCount.Store(-1) // and a ghost underflow
+	shard.k.Store(0)
+
+	d := cache.Diagnose()
+	if d.Healthy() {
+		t.Fatal("Expected anomalies for corrupted counters")
+	}
+	checks := map[string]bool{}
+	for _, a := range d.Anomalies {
+		checks[a.Check] = true
+	}
+	for _, want := range []string{"entry-count-drift", "ghost-count-drift", "ghost-bounds", "k-bounds"} {
+		if !checks[want] {
+			t.Errorf("Missing anomaly %q in %v", want, d.Anomalies)
+		}
+	}
+	if !strings.Contains(d.Anomalies[0].String(), "shard") {
+		t.Errorf("Anomaly string lacks shard: %q", d.Anomalies[0].String())
+	}
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheDumpJSON(t *testing.T) {
+	type Record struct {
+		ID int `json:"id"`
+	}
+
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	cache := NewCloxCache[string, Record](cfg)
+	defer cache.Close()
+
+	const numKeys = 20
+	for i := range numKeys {
+		cache.Put(fmt.Sprintf("key-%d", i), Record{ID: i})
+	}
+	cache.Get("key-3")
+	cache.PutWithTTL("key-5", Record{ID: 5}, time.Hour)
+
+	var buf bytes.Buffer
+	if err := cache.DumpJSON(&buf, true); err != nil {
+		t.Fatalf("DumpJSON failed: %v", err)
+	}
+
+	var entries []struct {
+		Key   string  `json:"key"`
+		Freq  int32   `json:"freq"`
+		TTL   *string `json:"ttl"`
+		Value *Record `json:"value"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("DumpJSON produced invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(entries) != numKeys {
+		t.Fatalf("Expected %d entries, got %d", numKeys, len(entries))
+	}
+	for _, e := range entries {
+		if e.Value == nil || fmt.Sprintf("key-%d", e.Value.ID) != e.Key {
+			t.Errorf("Entry %q has wrong value %+v", e.Key, e.Value)
+		}
+		if e.Key == "key-3" && e.Freq != 2 {
+			t.Errorf("Expected freq 2 for key-3, got %d", e.Freq)
+		}
+		if e.Key != "key-5" {
+			if e.TTL != nil {
+				t.Errorf("Entry %q without a TTL has ttl %q", e.Key, *e.TTL)
+			}
+			continue
+		}
+		if e.TTL == nil {
+			t.Errorf("Entry %q has no ttl", e.Key)
+		} else if ttl, err := time.ParseDuration(*e.TTL); err != nil || ttl < 59*time.Minute || ttl > time.Hour+time.Minute {
+			t.Errorf("Entry %q has ttl %q, want about 1h", e.Key, *e.TTL)
+		}
+	}
+
+	// Without values
+	buf.Reset()
+	if err := cache.DumpJSON(&buf, false); err != nil {
+		t.Fatalf("DumpJSON failed: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"value"`)) {
+		t.Error("DumpJSON included values when includeValues is false")
+	}
+}
+
+func TestCloxCacheDumpJSONEmpty(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+
+	var buf bytes.Buffer
+	if err := cache.DumpJSON(&buf, true); err != nil {
+		t.Fatalf("DumpJSON failed: %v", err)
+	}
+	var entries []any
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil || len(entries) != 0 {
+		t.Fatalf("Expected empty JSON array, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestCloxCacheDumpShard(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.DumpShard(0, &buf); err != nil {
+		t.Fatalf("DumpShard failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "shard 0: entries=4/4 ghosts=4/4") {
+		t.Errorf("Unexpected header:\n%s", out)
+	}
+	if got := strings.Count(out, " ghost freq="); got != 4 {
+		t.Errorf("Expected 4 ghost nodes in dump, got %d:\n%s", got, out)
+	}
+	if got := strings.Count(out, "["); got != 8 {
+		t.Errorf("Expected 8 nodes in dump, got %d:\n%s", got, out)
+	}
+	if !strings.Contains(out, fmt.Sprintf("%016x", hashKey("key-7"))) {
+		t.Errorf("Dump is missing key-7's hash:\n%s", out)
+	}
+
+	if err := cache.DumpShard(1, &buf); err == nil {
+		t.Error("Expected an error for an out-of-range shard")
+	}
+}
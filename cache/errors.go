@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrClosed is returned by operations on a closed cache
+	ErrClosed = errors.New("cache: closed")
+	// ErrTooLarge is returned when an entry weighs more than the cache accepts
+	ErrTooLarge = errors.New("cache: entry too large")
+	// ErrRejected is returned when the cache declined to store a value; the error
+	// names the RejectReason
+	ErrRejected = errors.New("cache: put rejected")
+)
+
+// Err returns the error PutE reports for the reason (nil for RejectNone)
+func (r RejectReason) Err() error {
+	switch r {
+	case RejectNone:
+		return nil
+	case RejectClosed:
+		return ErrClosed
+	case RejectOverWeight:
+		return ErrTooLarge
+	default:
+		return fmt.Errorf("%w: %s", ErrRejected, r)
+	}
+}
+
+// PutE is Put for callers that need to tell failure modes apart: it returns nil
+// once the value is stored, ErrClosed, ErrTooLarge, or an error wrapping
+// ErrRejected that names the RejectReason. Use errors.Is to match them.
+func (c *CloxCache[K, V]) PutE(key K, value V) error {
+	_, reason := c.PutReason(key, value)
+	return reason.Err()
+}
+
+// GetE is Get with an error result: a missing key is reported as found == false
+// with a nil error, so a non-nil error always means the lookup itself failed
+// (ErrClosed after Close).
+func (c *CloxCache[K, V]) GetE(key K) (value V, found bool, err error) {
+	if c.closed.Load() {
+		return value, false, ErrClosed
+	}
+	value, found = c.Get(key)
+	return value, found, nil
+}
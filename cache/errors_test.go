@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCloxCachePutE(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 2, SweepPercent: 100}
+	cache := NewCloxCache(cfg, WithPriorityClasses[string, int](0, 1))
+	defer cache.Close()
+
+	for i := range 2 {
+		if !cache.PutWithClass(fmt.Sprintf("vip-%d", i), i, 1) {
+			t.Fatalf("PutWithClass vip-%d failed", i)
+		}
+	}
+	err := cache.PutE("crawl", 0)
+	if !errors.Is(err, ErrRejected) {
+		t.Fatalf("PutE = %v, want ErrRejected", err)
+	}
+	if got := err.Error(); got != "cache: put rejected: victim-not-found" {
+		t.Errorf("PutE error = %q", got)
+	}
+
+	if err := cache.PutE("vip-0", 5); err != nil {
+		t.Errorf("PutE update = %v", err)
+	}
+	if v, found, err := cache.GetE("vip-0"); err != nil || !found || v != 5 {
+		t.Errorf("GetE = %d, %v, %v", v, found, err)
+	}
+	if _, found, err := cache.GetE("missing"); err != nil || found {
+		t.Errorf("GetE(missing) = %v, %v; want a miss without error", found, err)
+	}
+}
+
+func TestRejectReasonErr(t *testing.T) {
+	if err := RejectNone.Err(); err != nil {
+		t.Errorf("RejectNone.Err() = %v", err)
+	}
+	if err := RejectClosed.Err(); err != ErrClosed {
+		t.Errorf("RejectClosed.Err() = %v", err)
+	}
+	if err := RejectOverWeight.Err(); err != ErrTooLarge {
+		t.Errorf("RejectOverWeight.Err() = %v", err)
+	}
+	if err := RejectAdmission.Err(); !errors.Is(err, ErrRejected) {
+		t.Errorf("RejectAdmission.Err() = %v, want ErrRejected", err)
+	}
+}
@@ -0,0 +1,58 @@
+package cache
+
+// ExpireFunc converts every live entry for which fn returns true into a ghost and
+// returns the number of entries expired. Unlike DeleteFunc, the frequency history
+// is kept, so keys re-fetched right after an invalidation (e.g. a schema or version
+// bump) come back with their earned priority. When a shard's ghost capacity is
+// full, matching entries are removed outright.
+//
+// Each shard lock is taken once; fn runs while it is held, so it must not call
+// back into the cache.
+func (c *CloxCache[K, V]) ExpireFunc(fn func(key K, value V) bool) int {
+	if c.closed.Load() {
+		return 0
+	}
+	expired := 0
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		slots := shard.slots()
+		for s := range slots {
+			slot := &slots[s]
+			var prev *recordNode[K, V]
+			for node := slot.Load(); node != nil; {
+				next := node.next.Load()
+				if node.freq.Load() > 0 && c.stale(node) {
+					c.removeLocked(shard, slot, prev, node, c.staleReason(node))
+					node = next
+					continue
+				}
+				vp := node.value.Load()
+				if node.freq.Load() <= 0 || vp == nil {
+					prev = node
+					node = next
+					continue
+				}
+				key := node.fullKey()
+				if !fn(key, *vp) {
+					prev = node
+					node = next
+					continue
+				}
+
+				if shard.ghostCount.Load() < shard.ghostCapacity {
+					c.preserve(shard, node.keyHash)
+					c.finalize(shard, key, c.ghostLocked(shard, node, EvictReasonGhosted))
+					c.dropOverflow(key)
+					prev = node
+				} else {
+					c.removeLocked(shard, slot, prev, node, EvictReasonRemoved)
+				}
+				expired++
+				node = next
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return expired
+}
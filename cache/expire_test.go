@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCloxCacheExpireFunc(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 32}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 10 {
+		key := fmt.Sprintf("v1/item-%d", i)
+		cache.Put(key, i)
+		cache.Get(key)
+		cache.Get(key)
+	}
+	cache.Put("v2/item-0", 0)
+
+	expired := cache.ExpireFunc(func(key string, _ int) bool {
+		return strings.HasPrefix(key, "v1/")
+	})
+	if expired != 10 {
+		t.Errorf("Expected 10 expired, got %d", expired)
+	}
+	for i := range 10 {
+		key := fmt.Sprintf("v1/item-%d", i)
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("%s is still readable after expiry", key)
+		}
+		if f, ok := cache.GhostFreq(key); !ok || f != 3 {
+			t.Errorf("%s: expected ghost with freq 3, got %d, %v", key, f, ok)
+		}
+	}
+	if _, ok := cache.Get("v2/item-0"); !ok {
+		t.Error("Non-matching entry was expired")
+	}
+
+	// Re-fetch after the bump restores the earned frequency
+	cache.Put("v1/item-0", 100)
+	if f := liveFreq(cache, "v1/item-0"); f != 4 {
+		t.Errorf("Expected promoted frequency 4, got %d", f)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after ExpireFunc: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheExpireFuncGhostCapacityFull(t *testing.T) {
+	// 8 live, 8 ghost capacity
+	cfg := Config{NumShards: 1, SlotsPerShard: 16, Capacity: 8}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.shards[0].ghostCount.Store(cache.shards[0].ghostCapacity - 3)
+
+	if expired := cache.ExpireFunc(func(string, int) bool { return true }); expired != 8 {
+		t.Errorf("Expected 8 expired, got %d", expired)
+	}
+	ghosts := len(cache.Ghosts())
+	if ghosts != 3 {
+		t.Errorf("Expected 3 entries to become ghosts, got %d", ghosts)
+	}
+	if n := cache.shards[0].entryCount.Load(); n != 0 {
+		t.Errorf("Expected no live entries, got %d", n)
+	}
+}
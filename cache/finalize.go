@@ -0,0 +1,88 @@
+package cache
+
+import "io"
+
+// WithFinalizer registers fn to release resources held by values (file handles,
+// mmap regions, pooled buffers). It runs exactly once for every value that leaves
+// the cache: replaced by a Put, Update or Merge, evicted or ghosted, deleted,
+// expired, invalidated or released, and for the values still cached on Close,
+// which then removes them. Ghosts hold no value and never trigger it, and a value
+// demoted to an overflow store (see WithOverflow) has not left and is not
+// finalized.
+//
+// fn usually runs while the shard lock is held (lock-free updates and readers
+// releasing a pin call it without), so it must be fast and must not call back
+// into the cache. Values passed to GetFunc are finalized only after it returns;
+// readers that obtained the value with Get before it left may still be using it.
+// Clone requires WithCloneValue, so clones don't share the resources.
+func WithFinalizer[K Key, V any](fn func(key K, value V)) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.finalizer = fn
+	}
+}
+
+// WithAutoClose closes values that implement io.Closer when they leave the cache,
+// with the same exactly-once rules as WithFinalizer (after the finalizer, if both
+// are set). Use GetFunc to read values that may be closed concurrently. Close
+// errors are logged as warnings.
+func WithAutoClose[K Key, V any]() Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.autoClose = true
+	}
+}
+
+// finalize runs the finalizer for a value that left shard (nil = none), or
+// defers it while readers hold the value pinned. The shard is passed in rather
+// than found from key, which is the zero K with WithHashOnlyKeys.
+func (c *CloxCache[K, V]) finalize(shard *shard[K, V], key K, vp *V) {
+	if vp == nil || !c.finalizes() {
+		return
+	}
+	if shard.deferFinalizer(vp) {
+		return
+	}
+	c.runFinalizer(key, vp)
+}
+
+// runFinalizer finalizes and closes a value that left the cache
+func (c *CloxCache[K, V]) runFinalizer(key K, vp *V) {
+	if c.finalizer != nil {
+		c.finalizer(key, *vp)
+	}
+	if c.autoClose {
+		if closer, ok := any(*vp).(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				c.logWarn("closing value that left the cache failed", "error", err)
+			}
+		}
+	}
+}
+
+// finalizeAll removes every live entry and finalizes its value. Close calls it
+// when a finalizer is set so no resource outlives the cache. An overflow store
+// that outlives the process keeps the copies Close just gave it.
+func (c *CloxCache[K, V]) finalizeAll() {
+	if !c.finalizes() {
+		return
+	}
+	dropOverflow := !c.retainsOverflow()
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		slots := shard.slots()
+		for s := range slots {
+			slot := &slots[s]
+			var prev *recordNode[K, V]
+			for node := slot.Load(); node != nil; {
+				next := node.next.Load()
+				if node.freq.Load() > 0 {
+					c.removeNodeLocked(shard, slot, prev, node, EvictReasonRemoved, dropOverflow)
+				} else {
+					prev = node
+				}
+				node = next
+			}
+		}
+		shard.mu.Unlock()
+	}
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCloxCacheFinalizer(t *testing.T) {
+	// 4 live, 4 ghost capacity
+	cfg := Config{NumShards: 1, SlotsPerShard: 8, Capacity: 4, SweepPercent: 100}
+	finalized := make(map[string]int)
+	cache := NewCloxCache(cfg, WithFinalizer(func(key string, value int) {
+		finalized[fmt.Sprintf("%s=%d", key, value)]++
+	}))
+
+	for i := range 4 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.Put("key-0", 100)                                         // replaced
+	cache.Update("key-1", func(old int, _ bool) int { return 101 }) // replaced
+	cache.Delete("key-2")
+	cache.Put("key-4", 4)
+	cache.Put("key-5", 5) // evicts one entry
+	cache.Close()
+
+	want := 8 // 2 replaced, 1 deleted, 1 evicted, 4 still cached on Close
+	total := 0
+	for entry, n := range finalized {
+		if n != 1 {
+			t.Errorf("%s finalized %d times, want once", entry, n)
+		}
+		total += n
+	}
+	if total != want {
+		t.Errorf("Finalized %d values, want %d: %v", total, want, finalized)
+	}
+	for _, entry := range []string{"key-0=0", "key-1=1", "key-2=2", "key-0=100", "key-5=5"} {
+		if finalized[entry] != 1 {
+			t.Errorf("%s was not finalized", entry)
+		}
+	}
+	if n := cache.StatsSnapshot().Entries; n != 0 {
+		t.Errorf("Entries after Close = %d, want 0 (finalized entries are removed)", n)
+	}
+}
+
+func TestCloxCacheFinalizerSkipsDemoted(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 8, Capacity: 2, SweepPercent: 100}
+	store := &mapOverflow{entries: make(map[string]int)}
+	finalized := 0
+	cache := NewCloxCache(cfg, WithOverflow[string, int](store),
+		WithFinalizer(func(string, int) { finalized++ }))
+	defer cache.Close()
+
+	for i := range 4 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if demoted, _ := cache.OverflowStats(); demoted != 2 {
+		t.Fatalf("Demoted %d values, want 2", demoted)
+	}
+	if finalized != 0 {
+		t.Errorf("Finalized %d demoted values, want 0", finalized)
+	}
+}
+
+// closerValue records whether it was closed
+type closerValue struct {
+	closed *int
+}
+
+func (v closerValue) Close() error {
+	*v.closed++
+	return nil
+}
+
+func TestCloxCacheAutoClose(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache(cfg, WithAutoClose[string, closerValue]())
+
+	var first, second int
+	cache.Put("tmpl", closerValue{closed: &first})
+
+	// Replacing the value while a reader uses it defers the close until it's done
+	found := cache.GetFunc("tmpl", func(v closerValue) {
+		cache.Put("tmpl", closerValue{closed: &second})
+		if first != 0 {
+			t.Error("Value closed while a reader was using it")
+		}
+	})
+	if !found {
+		t.Fatal("GetFunc missed a live key")
+	}
+	if first != 1 {
+		t.Errorf("Replaced value closed %d times after the reader finished, want 1", first)
+	}
+
+	cache.Close()
+	if second != 1 {
+		t.Errorf("Value still cached on Close closed %d times, want 1", second)
+	}
+	if cache.GetFunc("tmpl", func(closerValue) { t.Error("GetFunc called fn after Close") }) {
+		t.Error("GetFunc after Close should miss")
+	}
+}
+
+func TestCloxCacheFinalizerRacingDelete(t *testing.T) {
+	// Lock-free updates race the Deletes of their key: every stored value must
+	// still be finalized exactly once
+	var finalized atomic.Int64
+	cache := NewCloxCache(Config{NumShards: 1, SlotsPerShard: 16, Capacity: 16},
+		WithFinalizer(func(string, int) { finalized.Add(1) }))
+
+	var puts atomic.Int64
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20000 {
+				if cache.Put("k", w*20000+i) {
+					puts.Add(1)
+				}
+				if i%3 == 0 {
+					cache.Delete("k")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	cache.Close()
+
+	if p, f := puts.Load(), finalized.Load(); p != f {
+		t.Errorf("%d values stored, %d finalized", p, f)
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// flusher is implemented by overflow stores that buffer writes (see OverflowStore)
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// retainer is implemented by overflow stores that can outlive the process, which
+// Close fills with the values still cached (see OpenSharedStore)
+type retainer interface {
+	Retains() bool
+}
+
+// WithSnapshotOnClose writes a final snapshot (see WriteSnapshot) when the cache is
+// closed, after Puts have stopped, so the next process can restore exactly what
+// this one held. open is called once during Close; the writer it returns is
+// closed after the snapshot is written. Requires a codec for V.
+func WithSnapshotOnClose[K Key, V any](open func() (io.WriteCloser, error)) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.closeSnapshot = open
+	}
+}
+
+// flush persists what the cache still holds as it closes: it hands the live
+// values to an overflow store that outlives the process, flushes an overflow
+// store that buffers writes (one with a Flush(ctx) error method), then writes the
+// final snapshots. Writing stops with ctx.Err() once ctx is done.
+func (c *CloxCache[K, V]) flush(ctx context.Context) error {
+	var errs []error
+	if c.retainsOverflow() {
+		errs = append(errs, c.retainLive(ctx))
+	}
+	if f, ok := c.overflow.(flusher); ok {
+		errs = append(errs, f.Flush(ctx))
+	}
+	if c.closeSnapshot != nil {
+		errs = append(errs, c.writeCloseSnapshot(ctx))
+	}
+	if c.snapshotStore != nil {
+		if cancel := c.persistCancel.Load(); cancel != nil {
+			(*cancel)() // abandon a periodic save in progress
+		}
+		errs = append(errs, c.persist(ctx, SnapshotOptions{}))
+	}
+	return errors.Join(errs...)
+}
+
+// retainsOverflow reports whether the overflow store outlives the process
+func (c *CloxCache[K, V]) retainsOverflow() bool {
+	r, ok := c.overflow.(retainer)
+	return ok && r.Retains()
+}
+
+// retainLive stores every live value in the overflow store, leaving the cache as it
+// is: the values are finalized when Close removes them, as the store holds copies
+func (c *CloxCache[K, V]) retainLive(ctx context.Context) error {
+	if c.hashOnly {
+		return nil
+	}
+	var err error
+	c.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		if node.freq.Load() <= 0 || c.stale(node) {
+			return true
+		}
+		if vp := node.value.Load(); vp != nil {
+			c.overflow.Store(node.fullKey(), *vp)
+		}
+		err = ctx.Err()
+		return err == nil
+	})
+	return err
+}
+
+// writeCloseSnapshot writes the final snapshot configured by WithSnapshotOnClose
+func (c *CloxCache[K, V]) writeCloseSnapshot(ctx context.Context) error {
+	w, err := c.closeSnapshot()
+	if err != nil {
+		return err
+	}
+	err = c.WriteSnapshotContext(ctx, w, SnapshotOptions{})
+	return errors.Join(err, w.Close())
+}
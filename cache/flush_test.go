@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// bufferCloser records whether the snapshot writer was closed
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+// flushingOverflow is a mapOverflow that counts flushes
+type flushingOverflow struct {
+	mapOverflow
+	flushes int
+}
+
+func (f *flushingOverflow) Flush(ctx context.Context) error {
+	f.flushes++
+	return ctx.Err()
+}
+
+func TestCloxCacheSnapshotOnClose(t *testing.T) {
+	var out bufferCloser
+	store := &flushingOverflow{mapOverflow: mapOverflow{entries: make(map[string]int)}}
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache(cfg,
+		WithOverflow[string, int](store),
+		WithSnapshotOnClose[string, int](func() (io.WriteCloser, error) { return &out, nil }))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Close()
+	cache.Close() // flushes only once
+
+	if store.flushes != 1 {
+		t.Errorf("Overflow store flushed %d times, want 1", store.flushes)
+	}
+	if !out.closed {
+		t.Error("Expected the snapshot writer to be closed")
+	}
+
+	restored := NewCloxCache[string, int](cfg)
+	defer restored.Close()
+	if n, err := restored.ReadSnapshot(&out.Buffer); err != nil || n != 2 {
+		t.Fatalf("ReadSnapshot = %d, %v; want 2 entries", n, err)
+	}
+	if v, ok := restored.Get("b"); !ok || v != 2 {
+		t.Errorf("Restored b = %d, %v", v, ok)
+	}
+}
+
+func TestCloxCacheFlushContext(t *testing.T) {
+	var out bufferCloser
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache(cfg,
+		WithSnapshotOnClose[string, string](func() (io.WriteCloser, error) { return &out, nil }))
+	cache.Put("a", "1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cache.flush(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("flush with a cancelled context = %v, want context.Canceled", err)
+	}
+	if !out.closed {
+		t.Error("Expected the snapshot writer to be closed after a failed write")
+	}
+}
+
+func TestCloxCacheCloseContext(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache[string, int](cfg)
+
+	// A background goroutine that outlives the deadline
+	cache.wg.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := cache.CloseContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CloseContext = %v, want context.DeadlineExceeded", err)
+	}
+	if !cache.Closed() {
+		t.Error("Expected the cache to be closed even though the wait timed out")
+	}
+
+	cache.wg.Done()
+	if err := cache.CloseContext(context.Background()); err != nil {
+		t.Errorf("Second CloseContext = %v, want nil", err)
+	}
+}
+
+func TestCloxCacheCloseContextFlushError(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	openErr := errors.New("disk full")
+	cache := NewCloxCache(cfg,
+		WithSnapshotOnClose[string, int](func() (io.WriteCloser, error) { return nil, openErr }))
+	if err := cache.CloseContext(context.Background()); !errors.Is(err, openErr) {
+		t.Errorf("CloseContext = %v, want the snapshot error", err)
+	}
+}
+
+func TestCloxCacheReopen(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	var snapshots int
+	cache := NewCloxCache(cfg, WithSnapshotOnClose[string, int](func() (io.WriteCloser, error) {
+		snapshots++
+		return &bufferCloser{}, nil
+	}))
+	cache.Reopen() // no-op while open
+	cache.Put("a", 1)
+	cache.Close()
+
+	cache.Reopen()
+	if cache.Closed() {
+		t.Fatal("Expected Closed to report false after Reopen")
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) after Reopen = %d, %v; want contents kept", v, ok)
+	}
+	if !cache.Put("b", 2) {
+		t.Error("Put after Reopen should store")
+	}
+
+	cache.Close()
+	if !cache.Closed() || snapshots != 2 {
+		t.Errorf("Second Close: closed=%v snapshots=%d, want true/2", cache.Closed(), snapshots)
+	}
+}
@@ -0,0 +1,28 @@
+package cache
+
+// Freeze pauses structural changes so maintenance work such as a backup or an
+// export can walk a stable cache without locking each entry. While frozen, Puts
+// that would add an entry (a new key, a ghost promotion, or the replacement of an
+// invalidated entry) are rejected with RejectFrozen, so nothing is evicted; the
+// capacity governor and the eviction-driven adaptation of the protection threshold
+// pause with them. Updates to live keys still replace their values in place, Gets
+// keep recording accesses, and explicit deletions (Delete, DeleteFunc, ExpireFunc)
+// still apply. Entries whose TTL passes read as absent, but the clock leaves them
+// in place until its first tick after Thaw. Call Thaw to resume.
+func (c *CloxCache[K, V]) Freeze() {
+	if !c.frozen.Swap(true) {
+		c.logDebug("cache frozen")
+	}
+}
+
+// Thaw resumes inserts and evictions after Freeze. A no-op if the cache isn't frozen.
+func (c *CloxCache[K, V]) Thaw() {
+	if c.frozen.Swap(false) {
+		c.logDebug("cache thawed")
+	}
+}
+
+// Frozen reports whether the cache is between Freeze and Thaw
+func (c *CloxCache[K, V]) Frozen() bool {
+	return c.frozen.Load()
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheFreeze(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 4 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.Freeze()
+	if !cache.Frozen() {
+		t.Fatal("Expected Frozen to report true after Freeze")
+	}
+
+	if ok, reason := cache.PutReason("new", 1); ok || reason != RejectFrozen {
+		t.Errorf("PutReason(new) while frozen = %v, %s; want false, %s", ok, reason, RejectFrozen)
+	}
+	if !cache.Put("key-0", 100) {
+		t.Error("Updating a live key while frozen should succeed")
+	}
+	for i := range 4 {
+		if _, ok := cache.Get(fmt.Sprintf("key-%d", i)); !ok {
+			t.Errorf("key-%d was evicted while frozen", i)
+		}
+	}
+	if n := cache.Rejections(RejectFrozen); n != 1 {
+		t.Errorf("Rejections(%s) = %d, want 1", RejectFrozen, n)
+	}
+
+	cache.Thaw()
+	if cache.Frozen() {
+		t.Error("Expected Frozen to report false after Thaw")
+	}
+	if !cache.Put("new", 1) {
+		t.Error("Put after Thaw should store")
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+// Sliding-window frequency (Config.FrequencyWindow > 0).
+//
+// Besides its lifetime frequency, every node counts its accesses in two epochs:
+// the current one and the one before it. Each node packs the epoch it last counted
+// in with both counts, so epochs rotate lazily per node and nothing has to walk the
+// cache when the shard's epoch advances. Eviction judges entries by the sum of both
+// counts, which covers the last one to two windows of activity.
+
+const (
+	recentCountBits = 16
+	recentCountMask = 1<<recentCountBits - 1
+)
+
+// packRecent builds a node's windowed counters
+func packRecent(epoch uint32, prev, cur uint64) uint64 {
+	return uint64(epoch)<<(2*recentCountBits) | prev<<recentCountBits | cur
+}
+
+// unpackRecent splits a node's windowed counters
+func unpackRecent(w uint64) (epoch uint32, prev, cur uint64) {
+	return uint32(w >> (2 * recentCountBits)), (w >> recentCountBits) & recentCountMask, w & recentCountMask
+}
+
+// touchRecent counts an access to node in the shard's current epoch.
+// A no-op unless Config.FrequencyWindow is set. Lossy under contention, like freq.
+func (c *CloxCache[K, V]) touchRecent(shard *shard[K, V], node *recordNode[K, V]) {
+	if c.config.FrequencyWindow <= 0 {
+		return
+	}
+	epoch := shard.epoch.Load()
+	w := node.recent.Load()
+	e, prev, cur := unpackRecent(w)
+	switch {
+	case e == epoch:
+		if cur >= maxFrequency {
+			return
+		}
+		cur++
+	case e+1 == epoch:
+		prev, cur = cur, 1
+	default:
+		prev, cur = 0, 1
+	}
+	node.recent.CompareAndSwap(w, packRecent(epoch, prev, cur))
+}
+
+// recentFreq returns node's access count over the current and previous epochs
+func recentFreq[K Key, V any](node *recordNode[K, V], epoch uint32) int32 {
+	e, prev, cur := unpackRecent(node.recent.Load())
+	switch {
+	case e == epoch:
+		return int32(min(prev+cur, maxFrequency))
+	case e+1 == epoch:
+		return int32(cur)
+	default:
+		return 0
+	}
+}
+
+// advanceEpoch starts as many new epochs as whole windows have elapsed and returns
+// the current epoch. Epochs only advance during eviction, which is the only time
+// the windowed frequency is read. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) advanceEpoch(shard *shard[K, V]) uint32 {
+	window := int64(c.config.FrequencyWindow)
+	start := shard.epochStart.Load()
+	if elapsed := c.now().UnixNano() - start; elapsed >= window {
+		n := elapsed / window
+		shard.epochStart.Store(start + n*window)
+		// Advance by at most two: older counts are already discarded at that point
+		return shard.epoch.Add(uint32(min(n, 2)))
+	}
+	return shard.epoch.Load()
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// expireEpochs moves every shard's epoch start back by n windows
+func expireEpochs[K Key, V any](c *CloxCache[K, V], n int) {
+	for i := range c.shards {
+		c.shards[i].epochStart.Add(-int64(n) * int64(c.config.FrequencyWindow))
+	}
+}
+
+func TestCloxCacheFrequencyWindow(t *testing.T) {
+	for _, window := range []time.Duration{0, time.Hour} {
+		cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, FrequencyWindow: window}
+		cache := NewCloxCache[string, int](cfg)
+
+		// Yesterday's hot key
+		cache.Put("old-hot", 0)
+		for range 10 {
+			cache.Get("old-hot")
+		}
+		cache.Put("a", 1)
+		cache.Put("b", 2)
+		cache.Put("c", 3)
+
+		expireEpochs(cache, 2)
+		cache.Put("d", 4)
+
+		_, hotKept := cache.Get("old-hot")
+		if window == 0 && !hotKept {
+			t.Error("Lifetime frequency should protect the formerly hot key")
+		}
+		if window > 0 && hotKept {
+			t.Error("Expected the formerly hot key to lose protection after two windows")
+		}
+		cache.Close()
+	}
+}
+
+func TestCloxCacheFrequencyWindowPreviousEpoch(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, FrequencyWindow: time.Hour}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	cache.Put("hot", 0)
+	for range 10 {
+		cache.Get("hot")
+	}
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+
+	// One window later the previous epoch's accesses still count
+	expireEpochs(cache, 1)
+	cache.Put("d", 4)
+
+	if _, ok := cache.Get("hot"); !ok {
+		t.Error("Accesses from the previous window should still protect the key")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the least recently used cold key to be evicted")
+	}
+}
+
+func TestRecentFreq(t *testing.T) {
+	node := &recordNode[string, int]{}
+	c := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 8, FrequencyWindow: time.Minute})
+	defer c.Close()
+	shard := &c.shards[0]
+
+	for range 3 {
+		c.touchRecent(shard, node)
+	}
+	if f := recentFreq(node, 0); f != 3 {
+		t.Errorf("recentFreq in the same epoch = %d, want 3", f)
+	}
+	if f := recentFreq(node, 1); f != 3 {
+		t.Errorf("recentFreq one epoch later = %d, want 3", f)
+	}
+	if f := recentFreq(node, 2); f != 0 {
+		t.Errorf("recentFreq two epochs later = %d, want 0", f)
+	}
+
+	shard.epoch.Store(1)
+	c.touchRecent(shard, node)
+	if f := recentFreq(node, 1); f != 4 {
+		t.Errorf("recentFreq after rotating = %d, want 4 (3 previous + 1 current)", f)
+	}
+	for range 20 {
+		c.touchRecent(shard, node)
+	}
+	if f := recentFreq(node, 1); f != maxFrequency {
+		t.Errorf("recentFreq = %d, want saturation at %d", f, maxFrequency)
+	}
+}
+
+func TestConfigFrequencyWindowSources(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 1000, "frequencyWindow": "5m"}`))
+	if err != nil || cfg.FrequencyWindow != 5*time.Minute {
+		t.Errorf("ConfigFromJSON frequencyWindow = %v, %v", cfg.FrequencyWindow, err)
+	}
+	if _, err := ConfigFromJSON([]byte(`{"capacity": 1000, "frequencyWindow": 300}`)); err == nil {
+		t.Error("Expected ConfigFromJSON to reject a bare number")
+	}
+	if _, err := ConfigFromYAML([]byte("capacity: 1000\nfrequencyWindow: -1m\n")); err == nil {
+		t.Error("Expected ConfigFromYAML to reject a negative window")
+	}
+
+	t.Setenv("CLOX_CAPACITY", "1000")
+	t.Setenv("CLOX_FREQUENCY_WINDOW", "90s")
+	if cfg, err := ConfigFromEnv(""); err != nil || cfg.FrequencyWindow != 90*time.Second {
+		t.Errorf("ConfigFromEnv frequencyWindow = %v, %v", cfg.FrequencyWindow, err)
+	}
+
+	if err := (Config{NumShards: 1, SlotsPerShard: 1, FrequencyWindow: -1}).Validate(); err == nil {
+		t.Error("Expected Validate to reject a negative window")
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+// GhostEntry describes a key that was evicted but whose frequency is still remembered
+type GhostEntry[K Key] struct {
+	Key        K
+	Freq       int32  // remembered frequency (restored as Freq+1 on re-insert)
+	LastAccess uint64 // shard timestamp of the last access before ghosting
+}
+
+// IsGhost reports whether key is currently tracked as a ghost (lock-free)
+func (c *CloxCache[K, V]) IsGhost(key K) bool {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	return c.findNode(shard, hash, hi, key, isGhost) != nil
+}
+
+// GhostFreq returns the remembered frequency of a ghost key.
+// Returns false if the key is live or not tracked at all.
+func (c *CloxCache[K, V]) GhostFreq(key K) (int32, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+
+	if node := c.findNode(shard, hash, hi, key, isGhost); node != nil {
+		if f := node.freq.Load(); f <= 0 {
+			return -f, true
+		}
+	}
+	return 0, false
+}
+
+// isGhost reports whether node is a ghost
+func isGhost[K Key, V any](node *recordNode[K, V]) bool {
+	return node.freq.Load() <= 0
+}
+
+// Ghosts returns all ghost entries currently tracked by the cache.
+// The result is a point-in-time view; concurrent writes may promote or drop ghosts.
+func (c *CloxCache[K, V]) Ghosts() []GhostEntry[K] {
+	var ghosts []GhostEntry[K]
+	c.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		if f := node.freq.Load(); f <= 0 {
+			ghosts = append(ghosts, GhostEntry[K]{
+				Key:        copyKey(node.fullKey()),
+				Freq:       -f,
+				LastAccess: node.lastAccess.Load(),
+			})
+		}
+		return true
+	})
+	return ghosts
+}
+
+// GhostCount returns the total number of ghosts and the total ghost capacity
+func (c *CloxCache[K, V]) GhostCount() (count, capacity int64) {
+	for i := range c.shards {
+		count += c.shards[i].ghostCount.Load()
+		capacity += c.shards[i].ghostCapacity
+	}
+	return count, capacity
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheGhostInspection(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	const numKeys = 8
+	for i := range numKeys {
+		if !cache.Put(fmt.Sprintf("key-%d", i), i) {
+			t.Fatalf("Put failed for key-%d", i)
+		}
+	}
+
+	ghosts := cache.Ghosts()
+	count, capacity := cache.GhostCount()
+	if capacity != 4 {
+		t.Errorf("Expected ghost capacity 4, got %d", capacity)
+	}
+	if int64(len(ghosts)) != count {
+		t.Fatalf("Ghosts() returned %d entries, GhostCount reports %d", len(ghosts), count)
+	}
+	if count == 0 {
+		t.Fatal("Expected evicted keys to become ghosts")
+	}
+
+	for _, g := range ghosts {
+		if !cache.IsGhost(g.Key) {
+			t.Errorf("IsGhost(%q) = false for enumerated ghost", g.Key)
+		}
+		freq, ok := cache.GhostFreq(g.Key)
+		if !ok || freq != g.Freq {
+			t.Errorf("GhostFreq(%q) = %d, %v; want %d, true", g.Key, freq, ok, g.Freq)
+		}
+		if g.Freq != initialFreq {
+			t.Errorf("Expected remembered freq %d for %q, got %d", initialFreq, g.Key, g.Freq)
+		}
+		if _, ok := cache.Get(g.Key); ok {
+			t.Errorf("Get(%q) hit on a ghost", g.Key)
+		}
+	}
+
+	// Live and unknown keys are not ghosts
+	if cache.IsGhost("missing") {
+		t.Error("IsGhost reported an unknown key as ghost")
+	}
+
+	// Re-inserting a ghost promotes it back to a live entry
+	promoted := ghosts[0].Key
+	cache.Put(promoted, 42)
+	if cache.IsGhost(promoted) {
+		t.Errorf("Key %q is still a ghost after re-insert", promoted)
+	}
+	if v, ok := cache.Get(promoted); !ok || v != 42 {
+		t.Errorf("Get(%q) = %d, %v after promotion; want 42, true", promoted, v, ok)
+	}
+}
+
+func TestCloxCacheGhostReleasesValue(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), make([]byte, 1<<20))
+	}
+
+	ghosts := 0
+	shard := &cache.shards[0]
+	slots := shard.slots()
+	for s := range slots {
+		for node := slots[s].Load(); node != nil; node = node.next.Load() {
+			if node.freq.Load() > 0 {
+				continue
+			}
+			ghosts++
+			if vp := node.value.Load(); vp != nil {
+				t.Errorf("Ghost %q still retains a %d byte value", node.fullKey(), len(*vp))
+			}
+		}
+	}
+	if ghosts == 0 {
+		t.Fatal("Expected evicted keys to become ghosts")
+	}
+}
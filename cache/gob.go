@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// gobExportVersion identifies the layout of a gob export stream
+const gobExportVersion = 1
+
+// gobHeader is the first value in a gob export stream
+type gobHeader struct {
+	Version int
+}
+
+// GobEntry is a single live entry in a gob export stream
+type GobEntry[K Key, V any] struct {
+	Key     K
+	Freq    int32
+	Value   V
+	Expires time.Time // when the entry's TTL ends (zero for none)
+}
+
+// ExportGob writes all live entries to w as a gob stream. Unlike GobCodec, type
+// information is sent once for the whole stream, so this is the preferred way to
+// persist caches of plain Go structs without writing a codec.
+// Interface-typed values must be registered with gob.Register.
+func (c *CloxCache[K, V]) ExportGob(w io.Writer) error {
+	if c.hashOnly {
+		return ErrNoKeys
+	}
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(gobHeader{Version: gobExportVersion}); err != nil {
+		return err
+	}
+
+	var err error
+	c.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		f := node.freq.Load()
+		if f <= 0 || c.stale(node) {
+			return true
+		}
+		vp := node.value.Load()
+		if vp == nil {
+			return true
+		}
+		entry := GobEntry[K, V]{Key: node.fullKey(), Freq: f, Value: *vp}
+		if deadline := c.deadline(node); deadline != 0 {
+			entry.Expires = time.Unix(0, deadline)
+		}
+		err = enc.Encode(entry)
+		return err == nil
+	})
+	return err
+}
+
+// ImportGob loads entries from a stream written by ExportGob, restoring their
+// frequencies and the rest of their TTLs; entries whose TTL passed are dropped.
+// Entries are added to the current contents.
+// Returns the number of entries loaded.
+func (c *CloxCache[K, V]) ImportGob(r io.Reader) (int, error) {
+	loaded, err := c.importGob(r)
+	if err != nil {
+		c.logWarn("gob import failed", "loaded", loaded, "error", err)
+	}
+	return loaded, err
+}
+
+func (c *CloxCache[K, V]) importGob(r io.Reader) (int, error) {
+	if c.closed.Load() {
+		return 0, ErrClosed
+	}
+	dec := gob.NewDecoder(r)
+
+	var header gobHeader
+	if err := dec.Decode(&header); err != nil {
+		return 0, fmt.Errorf("%w: reading gob header: %v", ErrInvalidSnapshot, err)
+	}
+	if header.Version != gobExportVersion {
+		return 0, fmt.Errorf("%w: unsupported gob export version %d", ErrInvalidSnapshot, header.Version)
+	}
+
+	loaded := 0
+	for {
+		var entry GobEntry[K, V]
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return loaded, nil
+			}
+			return loaded, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		var deadline int64
+		if !entry.Expires.IsZero() {
+			deadline = entry.Expires.UnixNano()
+		}
+		ttl, live := remainingTTL(deadline)
+		if live && c.restore(c.ownKey(entry.Key), entry.Value, clampFreq(uint64(max(entry.Freq, 0))), ttl) {
+			loaded++
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type gobRecord struct {
+	ID   int
+	Tags []string
+	Meta map[string]float64
+}
+
+func TestCloxCacheGobExportImport(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	src := NewCloxCache[[]byte, gobRecord](cfg)
+	defer src.Close()
+
+	const numKeys = 30
+	for i := range numKeys {
+		src.Put(fmt.Appendf(nil, "key-%d", i), gobRecord{
+			ID:   i,
+			Tags: []string{"a", fmt.Sprint(i)},
+			Meta: map[string]float64{"score": float64(i) / 2},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportGob(&buf); err != nil {
+		t.Fatalf("ExportGob failed: %v", err)
+	}
+
+	dst := NewCloxCache[[]byte, gobRecord](cfg)
+	defer dst.Close()
+	n, err := dst.ImportGob(&buf)
+	if err != nil {
+		t.Fatalf("ImportGob failed: %v", err)
+	}
+	if n != numKeys {
+		t.Fatalf("Expected %d entries loaded, got %d", numKeys, n)
+	}
+
+	got, ok := dst.Get([]byte("key-12"))
+	if !ok || got.ID != 12 || got.Tags[1] != "12" || got.Meta["score"] != 6 {
+		t.Errorf("Get(key-12) = %+v, %v after import", got, ok)
+	}
+}
+
+func TestCloxCacheGobDefaultCodec(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	src := NewCloxCache[string, gobRecord](cfg)
+	defer src.Close()
+	src.Put("record", gobRecord{ID: 1, Tags: []string{"x"}})
+
+	// Struct values fall back to GobCodec, so binary snapshots work without configuration
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	dst := NewCloxCache[string, gobRecord](cfg)
+	defer dst.Close()
+	if err := dst.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if got, ok := dst.Get("record"); !ok || got.ID != 1 || got.Tags[0] != "x" {
+		t.Errorf("Get(record) = %+v, %v after unmarshal", got, ok)
+	}
+}
+
+func TestCloxCacheImportGobInvalid(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+
+	if _, err := cache.ImportGob(bytes.NewReader([]byte("not a gob stream"))); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("Expected ErrInvalidSnapshot, got %v", err)
+	}
+}
+
+func TestCloxCacheGobTTL(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, TTLResolution: 100 * time.Millisecond}
+	src := NewCloxCache[string, int](cfg)
+	defer src.Close()
+	src.PutWithTTL("session", 1, time.Hour)
+	src.PutWithTTL("flash", 2, 150*time.Millisecond)
+	src.Put("forever", 3)
+
+	var buf bytes.Buffer
+	if err := src.ExportGob(&buf); err != nil {
+		t.Fatalf("ExportGob: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	dst := NewCloxCache[string, int](cfg)
+	defer dst.Close()
+	if n, err := dst.ImportGob(&buf); err != nil || n != 2 {
+		t.Fatalf("ImportGob = %d, %v; want 2 entries", n, err)
+	}
+	if _, ok := dst.Get("flash"); ok {
+		t.Error("Expired entry was resurrected")
+	}
+	if ttl, ok := dst.TTL("session"); !ok || ttl < 59*time.Minute {
+		t.Errorf("TTL(session) = %v, %v after import; want about an hour", ttl, ok)
+	}
+	if ttl, ok := dst.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("TTL(forever) = %v, %v after import; want 0, true", ttl, ok)
+	}
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Hit-rate regression harness: bundled synthetic traces are replayed at a few
+// capacities and the hit rates compared with the ones recorded in
+// testdata/golden_hitrates.json, so a change to eviction or adaptation that makes
+// the policy worse fails the build. After an intended policy change, record the
+// new rates with
+//
+//	go test -run TestGoldenHitRates -update-golden
+var updateGolden = flag.Bool("update-golden", false, "rewrite testdata/golden_hitrates.json with the measured hit rates")
+
+const (
+	goldenPath = "testdata/golden_hitrates.json"
+	// goldenTolerance is how far (absolute) a hit rate may drift from its golden
+	// value: above it, the change is a regression or an unrecorded improvement
+	goldenTolerance = 0.01
+)
+
+// goldenTrace is a deterministic synthetic access sequence
+type goldenTrace struct {
+	name string
+	keys int             // distinct keys, which capacities are a percentage of
+	gen  func() []uint64 // the sequence of keys read
+}
+
+// goldenTraces are the bundled traces, each a workload shape the policy must
+// handle: plain skew, skew with scans, a hot set that moves, and one-hit wonders
+var goldenTraces = []goldenTrace{
+	{"zipf", 20000, func() []uint64 {
+		z := newZipfGenerator(20000, 0.99, 1)
+		trace := make([]uint64, 200000)
+		for i := range trace {
+			trace[i] = z.next()
+		}
+		return trace
+	}},
+	{"zipf-scan", 20000, func() []uint64 {
+		// Every 10,000 reads, a scan reads 5,000 keys that are never read again
+		z := newZipfGenerator(15000, 0.9, 2)
+		trace := make([]uint64, 0, 200000)
+		scanned := uint64(15000)
+		for len(trace) < 200000 {
+			for range 10000 {
+				trace = append(trace, z.next())
+			}
+			for range 5000 {
+				trace = append(trace, scanned)
+				scanned++
+			}
+		}
+		return trace
+	}},
+	{"shifting", 20000, func() []uint64 {
+		// The popular keys change every 40,000 reads
+		z := newZipfGenerator(5000, 0.95, 3)
+		trace := make([]uint64, 200000)
+		for i := range trace {
+			phase := uint64(i / 40000)
+			trace[i] = (z.next() + phase*3000) % 20000
+		}
+		return trace
+	}},
+	{"one-hit-wonders", 20000, func() []uint64 {
+		// Half the reads go to keys read only once
+		rng := rand.New(rand.NewSource(4))
+		z := newZipfGenerator(10000, 0.99, 4)
+		trace := make([]uint64, 200000)
+		wonder := uint64(10000)
+		for i := range trace {
+			if rng.Intn(2) == 0 {
+				trace[i] = z.next()
+			} else {
+				trace[i] = wonder
+				wonder++
+			}
+		}
+		return trace
+	}},
+}
+
+// goldenCapacities are the cache sizes traces are replayed at, in percent of their keys
+var goldenCapacities = []int{5, 20}
+
+// replayHitRate replays trace through a deterministic cache of capacity entries,
+// storing keys that miss, and returns the hit rate
+func replayHitRate(trace []uint64, capacity int) float64 {
+	cfg := ConfigFromCapacity(capacity)
+	cache := NewCloxCache[string, int](cfg, WithDeterministic[string, int](1, nil))
+	defer cache.Close()
+
+	keys := make(map[uint64]string)
+	hits := 0
+	for _, k := range trace {
+		key, ok := keys[k]
+		if !ok {
+			key = fmt.Sprintf("key-%d", k)
+			keys[k] = key
+		}
+		if _, ok := cache.Get(key); ok {
+			hits++
+		} else {
+			cache.Put(key, 0)
+		}
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+func TestGoldenHitRates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("replays 800,000 reads per capacity")
+	}
+	golden := make(map[string]float64)
+	if data, err := os.ReadFile(goldenPath); err == nil {
+		if err := json.Unmarshal(data, &golden); err != nil {
+			t.Fatalf("%s: %v", goldenPath, err)
+		}
+	} else if !*updateGolden {
+		t.Fatalf("%v (record the hit rates with -update-golden)", err)
+	}
+
+	measured := make(map[string]float64)
+	for _, tr := range goldenTraces {
+		trace := tr.gen()
+		for _, pct := range goldenCapacities {
+			name := fmt.Sprintf("%s/%d%%", tr.name, pct)
+			rate := replayHitRate(trace, tr.keys*pct/100)
+			measured[name] = rate
+
+			want, ok := golden[name]
+			switch {
+			case *updateGolden:
+			case !ok:
+				t.Errorf("%s: no golden hit rate (record it with -update-golden)", name)
+			case rate < want-goldenTolerance:
+				t.Errorf("%s: hit rate %.4f regressed from %.4f", name, rate, want)
+			case rate > want+goldenTolerance:
+				t.Errorf("%s: hit rate %.4f improved on %.4f; record it with -update-golden", name, rate, want)
+			default:
+				t.Logf("%s: hit rate %.4f (golden %.4f)", name, rate, want)
+			}
+		}
+	}
+
+	if *updateGolden {
+		// Map keys are written sorted, so the file diffs cleanly
+		data, err := json.MarshalIndent(measured, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("Recorded %d hit rates in %s", len(measured), goldenPath)
+	}
+}
@@ -0,0 +1,48 @@
+package cache
+
+// Capacity governor (Config.TargetHitRate > 0).
+//
+// Every governorWindow Gets, a shard compares its hit rate over the window with the
+// target: above it, the shard's effective capacity shrinks by governorSteps of its
+// configured capacity; below it, the capacity grows back by the same step, never
+// past the configured capacity or below 1/governorFloorShare of it. Shrinking does
+// not evict by itself: inserts evict down to the new limit, so memory is returned
+// as the cache turns over.
+
+const (
+	governorWindow     = 1000 // Gets per shard between governor decisions
+	governorSteps      = 32   // decisions it takes to move across the whole capacity
+	governorFloorShare = 16   // effective capacity never drops below 1/n of the configured one
+)
+
+// govern adjusts the shard's effective capacity at the end of a governor window
+func (c *CloxCache[K, V]) govern(shardID int, shard *shard[K, V]) {
+	hits := shard.hits.Load()
+	rate := float64(hits-shard.govHits.Swap(hits)) / governorWindow
+
+	step := max(shard.capacity/governorSteps, 1)
+	floor := max(shard.capacity/governorFloorShare, 1)
+	limit := shard.limit.Load()
+	next := limit
+	if rate > c.config.TargetHitRate {
+		next = max(limit-step, floor)
+	} else if rate < c.config.TargetHitRate {
+		next = min(limit+step, shard.capacity)
+	}
+	if next != limit && shard.limit.CompareAndSwap(limit, next) {
+		c.logDebug("governed shard capacity",
+			"shard", shardID, "old_capacity", limit, "new_capacity", next,
+			"hit_rate", rate, "target", c.config.TargetHitRate)
+	}
+}
+
+// EffectiveCapacity returns the number of entries the cache currently holds before
+// inserts evict. It equals the configured capacity unless a TargetHitRate is set
+// (or briefly exceeds it while a shard repays capacity it borrowed).
+func (c *CloxCache[K, V]) EffectiveCapacity() int {
+	var n int64
+	for i := range c.shards {
+		n += c.shards[i].shardCapacity()
+	}
+	return int(n)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCloxCacheTargetHitRate(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 256, Capacity: 128, SweepPercent: 100, TargetHitRate: 0.5}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// A small working set hits every time: capacity shrinks to its floor
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("hot-%d", i), i)
+	}
+	for i := range 40 * governorWindow {
+		cache.Get(fmt.Sprintf("hot-%d", i%10))
+	}
+	if got := cache.EffectiveCapacity(); got != 128/governorFloorShare {
+		t.Fatalf("EffectiveCapacity after a high hit rate = %d, want %d", got, 128/governorFloorShare)
+	}
+
+	// Inserts evict down to the governed capacity
+	for i := range 20 {
+		cache.Put(fmt.Sprintf("new-%d", i), i)
+	}
+	if n := cache.shards[0].entryCount.Load(); n > 128/governorFloorShare {
+		t.Errorf("Shard holds %d entries over its governed capacity %d", n, 128/governorFloorShare)
+	}
+
+	// Misses grow the capacity back, but never past the configured one
+	for i := range 40 * governorWindow {
+		cache.Get(fmt.Sprintf("cold-%d", i))
+	}
+	if got := cache.EffectiveCapacity(); got != 128 {
+		t.Errorf("EffectiveCapacity after a low hit rate = %d, want 128", got)
+	}
+	if got := cache.GetAdaptiveStats()[0].Capacity; got != 128 {
+		t.Errorf("AdaptiveStats.Capacity = %d, want 128", got)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheFixedCapacity(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 256, Capacity: 128, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	cache.Put("hot", 1)
+	for range 10 * governorWindow {
+		cache.Get("hot")
+	}
+	if got := cache.EffectiveCapacity(); got != 128 {
+		t.Errorf("EffectiveCapacity without a target = %d, want 128", got)
+	}
+}
+
+func TestConfigTargetHitRateSources(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 1000, "targetHitRate": 0.95}`))
+	if err != nil || cfg.TargetHitRate != 0.95 {
+		t.Errorf("ConfigFromJSON targetHitRate = %v, %v", cfg.TargetHitRate, err)
+	}
+	if _, err := ConfigFromYAML([]byte("capacity: 1000\ntargetHitRate: 1.5\n")); err == nil {
+		t.Error("Expected ConfigFromYAML to reject a target above 1")
+	}
+
+	t.Setenv("CLOX_CAPACITY", "1000")
+	t.Setenv("CLOX_TARGET_HIT_RATE", "0.9")
+	if cfg, err := ConfigFromEnv(""); err != nil || cfg.TargetHitRate != 0.9 {
+		t.Errorf("ConfigFromEnv targetHitRate = %v, %v", cfg.TargetHitRate, err)
+	}
+
+	cfg = ConfigFromCapacity(1000)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-cache-target-hit-rate", "0.8"}); err != nil || cfg.TargetHitRate != 0.8 {
+		t.Errorf("-cache-target-hit-rate = %v, %v", cfg.TargetHitRate, err)
+	}
+	if !strings.Contains(cfg.Describe(), "target hit rate:   80.0%") {
+		t.Errorf("Describe doesn't mention the target hit rate:\n%s", cfg.Describe())
+	}
+
+	if err := (Config{NumShards: 1, SlotsPerShard: 1, TargetHitRate: -0.1}).Validate(); err == nil {
+		t.Error("Expected Validate to reject a negative target")
+	}
+}
@@ -0,0 +1,101 @@
+package cache
+
+import "sync/atomic"
+
+// Slot table growth (Config.GrowChainLength > 0).
+//
+// A shard whose nodes outgrow its slots doubles its table under the shard lock.
+// The extra slot bit splits every chain in two: a node in slot s moves to s or
+// s+len(old). Both halves keep the chain's original order, so a reader walking a
+// chain while it is being relinked only ever moves forward, but it may be led
+// onto the other half and miss its key. Each shard therefore keeps a sequence
+// number that is odd while a growth runs: lock-free lookups wait out an odd
+// sequence and repeat a walk that missed if the sequence changed meanwhile.
+
+// slots returns the shard's current slot table
+func (s *shard[K, V]) slots() []atomic.Pointer[recordNode[K, V]] {
+	return *s.table.Load()
+}
+
+// stableSeq returns the shard's resize sequence once no growth is running.
+// Callers holding the shard lock never wait: growths finish under it.
+func (s *shard[K, V]) stableSeq() uint32 {
+	for {
+		seq := s.resizeSeq.Load()
+		if seq&1 == 0 {
+			return seq
+		}
+		// The growth holds the lock until it is done
+		s.mu.Lock()
+		s.mu.Unlock()
+	}
+}
+
+// findNode returns the first node in key's chain that matches key and satisfies
+// want (nil = any node), walking the chain without the lock. A walk that misses
+// is repeated if the shard grew meanwhile.
+func (c *CloxCache[K, V]) findNode(shard *shard[K, V], hash, hi uint64, key K,
+	want func(node *recordNode[K, V]) bool) *recordNode[K, V] {
+	for {
+		seq := shard.stableSeq()
+		for node := c.slotIn(shard, hash).Load(); node != nil; node = node.next.Load() {
+			if c.matches(node, hash, hi, key) && (want == nil || want(node)) {
+				return node
+			}
+		}
+		if shard.resizeSeq.Load() == seq {
+			return nil
+		}
+	}
+}
+
+// liveNode reports whether node holds a current, readable entry
+func (c *CloxCache[K, V]) liveNode(node *recordNode[K, V]) bool {
+	return node.freq.Load() > 0 && !c.stale(node)
+}
+
+// growLocked doubles the shard's slot table if its nodes average more than
+// Config.GrowChainLength per slot. Growth waits while a Snapshot runs, since the
+// snapshot tracks its progress by slot index. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) growLocked(shard *shard[K, V]) {
+	limit := c.config.GrowChainLength
+	if limit <= 0 || shard.cowSaved != nil {
+		return
+	}
+	slots := shard.slots()
+	nodes := shard.entryCount.Load() + shard.ghostCount.Load()
+	if float64(nodes) <= limit*float64(len(slots)) {
+		return
+	}
+
+	grown := make([]atomic.Pointer[recordNode[K, V]], 2*len(slots))
+	bit := uint64(len(slots))
+	shard.resizeSeq.Add(1)
+	for s := range slots {
+		var tails [2]*recordNode[K, V]
+		for node := slots[s].Load(); node != nil; {
+			next := node.next.Load()
+			half := 0
+			if (node.keyHash>>c.shardBits)&bit != 0 {
+				half = 1
+			}
+			if tails[half] == nil {
+				grown[s+half*len(slots)].Store(node)
+			} else {
+				tails[half].next.Store(node)
+			}
+			tails[half] = node
+			node = next
+		}
+		for _, tail := range tails {
+			if tail != nil {
+				tail.next.Store(nil)
+			}
+		}
+	}
+	shard.table.Store(&grown)
+	shard.resizeSeq.Add(1)
+	shard.growths.Add(1)
+
+	c.logDebug("grew shard slot table", "slots", len(grown), "nodes", nodes)
+}
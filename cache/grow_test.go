@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCloxCacheGrowSlots(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 4, Capacity: 256, GrowChainLength: 2}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	// 200 nodes at most 2 per slot: 4 -> 8 -> 16 -> 32 -> 64 -> 128 slots
+	stats := cache.ChainStats()
+	if stats.Slots != 128 || stats.Growths != 5 {
+		t.Errorf("Expected 128 slots after 5 growths, got %d after %d", stats.Slots, stats.Growths)
+	}
+	for i := range 200 {
+		if v, ok := cache.Get(fmt.Sprintf("key-%d", i)); !ok || v != i {
+			t.Errorf("key-%d = %d, %v after growth; want %d", i, v, ok, i)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after growth: %v", report.Issues)
+	}
+
+	// The clone keeps the grown table
+	clone := cache.Clone(false)
+	defer clone.Close()
+	if stats := clone.ChainStats(); stats.Slots != 128 {
+		t.Errorf("Clone has %d slots, want 128", stats.Slots)
+	}
+	if v, ok := clone.Get("key-199"); !ok || v != 199 {
+		t.Errorf("Clone key-199 = %d, %v; want 199", v, ok)
+	}
+	if report := clone.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Clone integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheGrowDisabled(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 4, Capacity: 256})
+	defer cache.Close()
+
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if stats := cache.ChainStats(); stats.Slots != 4 || stats.Growths != 0 {
+		t.Errorf("Expected a fixed 4-slot table, got %d slots after %d growths", stats.Slots, stats.Growths)
+	}
+}
+
+func TestCloxCacheGrowDuringSnapshot(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 4, Capacity: 256, GrowChainLength: 1}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 4 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	// Slot indexes must stay put while a snapshot tracks its progress by them
+	seen := 0
+	for range cache.Snapshot() {
+		if seen == 0 {
+			for i := 4; i < 40; i++ {
+				cache.Put(fmt.Sprintf("key-%d", i), i)
+			}
+			if n := cache.ChainStats().Growths; n != 0 {
+				t.Errorf("Shard grew %d times during a snapshot", n)
+			}
+		}
+		seen++
+	}
+	if seen != 4 {
+		t.Errorf("Snapshot yielded %d entries, want 4", seen)
+	}
+
+	cache.Put("after", 0)
+	if n := cache.ChainStats().Growths; n == 0 {
+		t.Error("Shard didn't grow after the snapshot finished")
+	}
+}
+
+func TestCloxCacheGrowConcurrentReads(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 2, Capacity: 1 << 14, GrowChainLength: 1}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	const resident = 64
+	for i := range resident {
+		cache.Put(fmt.Sprintf("resident-%d", i), i)
+	}
+
+	// Readers must never miss a resident key while writers grow the tables under them
+	var done atomic.Bool
+	var missed atomic.Int64
+	var wg sync.WaitGroup
+	for r := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := r; !done.Load(); i++ {
+				key := fmt.Sprintf("resident-%d", i%resident)
+				if v, ok := cache.Get(key); !ok || v != i%resident {
+					missed.Add(1)
+				}
+				if !cache.SetSoft(key, false) {
+					missed.Add(1)
+				}
+			}
+		}()
+	}
+	for i := range 4000 {
+		cache.Put(fmt.Sprintf("filler-%d", i), i)
+	}
+	done.Store(true)
+	wg.Wait()
+
+	if n := missed.Load(); n != 0 {
+		t.Errorf("Readers missed resident keys %d times during growth", n)
+	}
+	if cache.ChainStats().Growths == 0 {
+		t.Error("Expected the shards to grow")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after concurrent growth: %v", report.Issues)
+	}
+}
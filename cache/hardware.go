@@ -56,22 +56,66 @@ func ConfigFromCapacity(capacity int) Config {
 	}
 }
 
+// Per-entry overhead used to translate memory budgets into capacities
+const (
+	// bytesPerNodeOverhead covers atomic pointers, freq, timestamp, size and key hash
+	bytesPerNodeOverhead = 96
+	// bytesPerEntrySlots is the slot overhead per entry: 8 bytes per slot with 3x slots per capacity
+	bytesPerEntrySlots = 24
+	// defaultAvgEntryBytes is the assumed key+value payload when nothing was measured
+	defaultAvgEntryBytes = 100
+)
+
 // ConfigFromMemorySize creates a CloxCache config for a specific memory budget.
-// Estimates how many entries fit in the given memory and configures accordingly.
+// Estimates how many entries fit in the given memory and configures accordingly,
+// assuming a typical ~100 byte key+value payload. Use ConfigFromMemorySizeFor when
+// the real entry sizes are known.
 func ConfigFromMemorySize(targetBytes uint64) Config {
-	// Estimate bytes per entry:
-	// - Node overhead: ~96 bytes (atomic pointers, freq, timestamp, key hash)
-	// - Average value overhead: ~100 bytes (estimate for typical use)
-	// - Slot overhead: ~8 bytes per slot (atomic pointer)
-	// With 3x slots per capacity, slot overhead per entry ≈ 24 bytes
-	const bytesPerEntry = 220 // 96 + 100 + 24
+	cfg := ConfigFromMemorySizeFor(targetBytes, 0, defaultAvgEntryBytes)
+	// The default payload is a guess, not a hint
+	cfg.AvgKeySize, cfg.AvgValueSize = 0, 0
+	return cfg
+}
+
+// ConfigFromMemorySizeFor creates a CloxCache config for a memory budget given the
+// average key and value sizes in bytes, e.g. as reported by MeasureEntrySizes on a
+// running cache.
+func ConfigFromMemorySizeFor(targetBytes uint64, avgKeyBytes, avgValueBytes int) Config {
+	if avgKeyBytes < 0 {
+		avgKeyBytes = 0
+	}
+	if avgValueBytes < 0 {
+		avgValueBytes = 0
+	}
+	bytesPerEntry := uint64(bytesPerNodeOverhead + bytesPerEntrySlots + avgKeyBytes + avgValueBytes)
 
 	capacity := int(targetBytes / bytesPerEntry)
 	if capacity < 100 {
 		capacity = 100
 	}
 
-	return ConfigFromCapacity(capacity)
+	cfg := ConfigFromCapacity(capacity)
+	cfg.AvgKeySize = avgKeyBytes
+	cfg.AvgValueSize = avgValueBytes
+	return cfg
+}
+
+// WithMemoryBudget returns a copy of the config resized to fit targetBytes, using
+// the AvgKeySize/AvgValueSize hints (or the default estimate when unset).
+// Non-sizing settings such as CollectStats and SweepPercent are preserved.
+func (c Config) WithMemoryBudget(targetBytes uint64) Config {
+	keyBytes, valueBytes := c.AvgKeySize, c.AvgValueSize
+	if keyBytes <= 0 && valueBytes <= 0 {
+		valueBytes = defaultAvgEntryBytes
+	}
+	sized := ConfigFromMemorySizeFor(targetBytes, keyBytes, valueBytes)
+
+	c.NumShards = sized.NumShards
+	c.SlotsPerShard = sized.SlotsPerShard
+	c.Capacity = sized.Capacity
+	c.AvgKeySize = sized.AvgKeySize
+	c.AvgValueSize = sized.AvgValueSize
+	return c
 }
 
 // nextPowerOf2 returns the next power of 2 >= n
@@ -94,9 +138,10 @@ func nextPowerOf2(n int) int {
 	return power
 }
 
-// EstimateMemoryUsage estimates total memory usage for a given configuration
+// EstimateMemoryUsage estimates total memory usage for a given configuration.
+// When AvgKeySize/AvgValueSize hints are set, live entries and ghosts are sized from
+// them; otherwise only the cache structure (slots and nodes) is estimated.
 func (c Config) EstimateMemoryUsage() uint64 {
-	const bytesPerNode = 96
 	const bytesPerSlot = 8
 	const shardOverhead = 64 // approximate overhead per shard struct
 
@@ -105,14 +150,29 @@ func (c Config) EstimateMemoryUsage() uint64 {
 	// Slot array memory
 	slotArrayMemory := totalSlots * bytesPerSlot
 
-	// Estimate nodes (assume load factor 1.25)
-	estimatedNodes := uint64(float64(totalSlots) * 1.25)
-	nodeMemory := estimatedNodes * bytesPerNode
-
 	// Shard overhead
 	shardMemory := uint64(c.NumShards) * shardOverhead
 
-	return slotArrayMemory + nodeMemory + shardMemory
+	if c.AvgKeySize <= 0 && c.AvgValueSize <= 0 {
+		// Estimate nodes (assume load factor 1.25)
+		estimatedNodes := uint64(float64(totalSlots) * 1.25)
+		nodeMemory := estimatedNodes * bytesPerNodeOverhead
+		return slotArrayMemory + nodeMemory + shardMemory
+	}
+
+	capacity := uint64(c.Capacity)
+	if capacity == 0 {
+		capacity = totalSlots
+	}
+	// Ghosts keep their node and key but release the value, capped at live capacity
+	ghosts := min(totalSlots-min(capacity, totalSlots), capacity)
+
+	keyBytes := uint64(max(c.AvgKeySize, 0))
+	valueBytes := uint64(max(c.AvgValueSize, 0))
+	liveMemory := capacity * (bytesPerNodeOverhead + keyBytes + valueBytes)
+	ghostMemory := ghosts * (bytesPerNodeOverhead + keyBytes)
+
+	return slotArrayMemory + liveMemory + ghostMemory + shardMemory
 }
 
 // FormatMemory formats bytes as human-readable string
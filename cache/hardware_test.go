@@ -217,3 +217,35 @@ func BenchmarkConfigFromMemorySize(b *testing.B) {
 		_ = ConfigFromMemorySize(memorySize)
 	}
 }
+
+func TestConfigSizingHints(t *testing.T) {
+	const budget = 256 * 1024 * 1024
+
+	cfg := ConfigFromMemorySizeFor(budget, 32, 4096)
+	if cfg.AvgKeySize != 32 || cfg.AvgValueSize != 4096 {
+		t.Fatalf("Sizing hints not recorded: key=%d value=%d", cfg.AvgKeySize, cfg.AvgValueSize)
+	}
+
+	// With hints, the estimate accounts for the real payload and stays near the budget
+	estimated := cfg.EstimateMemoryUsage()
+	t.Logf("Target: %s → capacity=%d → Estimated: %s", FormatMemory(budget), cfg.Capacity, FormatMemory(estimated))
+	if estimated < budget/2 || estimated > budget*2 {
+		t.Errorf("Estimated memory %s too far from budget %s", FormatMemory(estimated), FormatMemory(budget))
+	}
+
+	// WithMemoryBudget resizes using the hints and keeps unrelated settings
+	manual := Config{CollectStats: true, SweepPercent: 30, AvgKeySize: 32, AvgValueSize: 4096}
+	resized := manual.WithMemoryBudget(budget)
+	if resized.Capacity != cfg.Capacity || resized.NumShards != cfg.NumShards {
+		t.Errorf("WithMemoryBudget capacity=%d shards=%d, want %d/%d",
+			resized.Capacity, resized.NumShards, cfg.Capacity, cfg.NumShards)
+	}
+	if !resized.CollectStats || resized.SweepPercent != 30 {
+		t.Errorf("WithMemoryBudget dropped settings: %+v", resized)
+	}
+
+	// Without hints it matches the default memory sizing
+	if got, want := (Config{}).WithMemoryBudget(budget).Capacity, ConfigFromMemorySize(budget).Capacity; got != want {
+		t.Errorf("WithMemoryBudget without hints capacity=%d, want %d", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import "github.com/zeebo/xxh3"
+
+// WithHash128 hashes keys with 128-bit xxh3 instead of 64-bit and keeps the high
+// 64 bits in each node, so a lookup recognises its key by the full 128-bit hash
+// and skips comparing the key bytes. Two distinct keys share a 128-bit hash with
+// probability about n²/2¹²⁹ for n keys (below 10⁻²⁰ for a billion keys), so this
+// trades a negligible chance of returning another key's value for cheaper
+// lookups of long keys. Keys are still stored, for iteration, hooks and dumps.
+//
+// HashedKeys from Prehash or HashKeys carry 64-bit hashes and are rehashed on
+// use; hash them with the cache's Prehash method instead.
+func WithHash128[K Key, V any]() Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.hash128 = true
+	}
+}
+
+// hashes returns the hash that places key in a shard and slot and, with
+// WithHash128, the high half of its 128-bit hash (0 otherwise)
+func (c *CloxCache[K, V]) hashes(key K) (hash, hi uint64) {
+	if c.hash128 {
+		h := xxh3.Hash128(keyToBytes(key))
+		return h.Lo, h.Hi
+	}
+	return hashKey(key), 0
+}
+
+// matches reports whether node holds key, whose hashes are hash and hi
+func (c *CloxCache[K, V]) matches(node *recordNode[K, V], hash, hi uint64, key K) bool {
+	if node.keyHash != hash {
+		return false
+	}
+	if c.hash128 || c.hashOnly {
+		return node.keyHashHi == hi
+	}
+	if !node.keyEquals(key) {
+		c.hashCollisions.Add(1)
+		return false
+	}
+	return true
+}
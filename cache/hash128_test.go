@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zeebo/xxh3"
+)
+
+func TestCloxCacheHash128(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 256}
+	cache := NewCloxCache(cfg, WithHash128[string, int]())
+	defer cache.Close()
+
+	long := strings.Repeat("x", 512)
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("%s-%d", long, i), i)
+	}
+	for i := range 100 {
+		key := fmt.Sprintf("%s-%d", long, i)
+		if v, ok := cache.Get(key); !ok || v != i {
+			t.Fatalf("Get(key %d) = %d, %v", i, v, ok)
+		}
+	}
+	if _, ok := cache.Get(long); ok {
+		t.Error("Get hit a key that was never stored")
+	}
+
+	// Nodes carry both halves of the 128-bit hash
+	key := long + "-7"
+	h := xxh3.Hash128([]byte(key))
+	cache.rangeNodes(func(_ int, node *recordNode[string, int]) bool {
+		if node.fullKey() == key && (node.keyHash != h.Lo || node.keyHashHi != h.Hi) {
+			t.Errorf("Node hashes %x/%x, want %x/%x", node.keyHash, node.keyHashHi, h.Lo, h.Hi)
+		}
+		return true
+	})
+
+	// HashedKeys of either width work
+	if v, ok := cache.GetHashed(Prehash(key)); !ok || v != 7 {
+		t.Errorf("GetHashed(Prehash) = %d, %v; want 7", v, ok)
+	}
+	if v, ok := cache.GetHashed(cache.Prehash(key)); !ok || v != 7 {
+		t.Errorf("GetHashed(cache.Prehash) = %d, %v; want 7", v, ok)
+	}
+	values, found := cache.GetMany([]string{key, "missing"})
+	if !found[0] || values[0] != 7 || found[1] {
+		t.Errorf("GetMany = %v, %v", values, found)
+	}
+
+	if !cache.Delete(key) {
+		t.Error("Delete missed a 128-bit hashed key")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues with 128-bit hashes: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheHash128TrustsHash(t *testing.T) {
+	cache := NewCloxCache(Config{NumShards: 1, SlotsPerShard: 16}, WithHash128[string, int]())
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	hash, hi := cache.hashes("a")
+	_, slot := cache.locate(hash)
+	// A key with the same 128-bit hash is taken to be the same key
+	if !cache.matches(slot.Load(), hash, hi, "b") {
+		t.Error("Expected a match on the 128-bit hash alone")
+	}
+	if cache.matches(slot.Load(), hash, hi^1, "a") {
+		t.Error("Expected a mismatch when the high half differs")
+	}
+}
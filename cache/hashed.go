@@ -0,0 +1,57 @@
+package cache
+
+// HashedKey is a key with its hash computed once, for callers that access the
+// same key several times (e.g. a Get, then a Put after a miss). Create it with
+// Prehash; the zero value is not valid.
+type HashedKey[K Key] struct {
+	key  K
+	hash uint64
+	hi   uint64 // high half of a 128-bit hash
+	wide bool   // hashed for WithHash128
+}
+
+// Prehash hashes key for use with GetHashed and PutHashed. The key is used as
+// is, so a []byte key must not be modified while the HashedKey is in use.
+// Caches using WithHash128 need the cache's Prehash method instead.
+func Prehash[K Key](key K) HashedKey[K] {
+	return HashedKey[K]{key: key, hash: hashKey(key)}
+}
+
+// Prehash is the package Prehash for this cache, hashing key the way the cache
+// does (128-bit with WithHash128)
+func (c *CloxCache[K, V]) Prehash(key K) HashedKey[K] {
+	hash, hi := c.hashes(key)
+	return HashedKey[K]{key: key, hash: hash, hi: hi, wide: c.hash128}
+}
+
+// Key returns the key
+func (h HashedKey[K]) Key() K {
+	return h.key
+}
+
+// Hash returns the key's hash, the one reported as KeyHash in SlowOp
+func (h HashedKey[K]) Hash() uint64 {
+	return h.hash
+}
+
+// GetHashed is Get for a key hashed with Prehash
+func (c *CloxCache[K, V]) GetHashed(h HashedKey[K]) (V, bool) {
+	hash, hi := c.rehash(h)
+	return c.getWithHash(h.key, hash, hi)
+}
+
+// PutHashed is Put for a key hashed with Prehash
+func (c *CloxCache[K, V]) PutHashed(h HashedKey[K], value V) bool {
+	hash, hi := c.rehash(h)
+	ok, _ := c.putReasonWithHash(h.key, hash, hi, value, nil)
+	return ok
+}
+
+// rehash returns h's hashes, recomputing them if h was hashed for a different
+// hash width than the cache uses
+func (c *CloxCache[K, V]) rehash(h HashedKey[K]) (hash, hi uint64) {
+	if h.wide != c.hash128 {
+		return c.hashes(h.key)
+	}
+	return h.hash, h.hi
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCachePrehash(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 256}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 100 {
+		h := Prehash(fmt.Sprintf("key-%d", i))
+		if h.Hash() != hashKey(h.Key()) {
+			t.Fatalf("Prehash(%q) = %x, want %x", h.Key(), h.Hash(), hashKey(h.Key()))
+		}
+		if _, ok := cache.GetHashed(h); ok {
+			t.Fatalf("GetHashed(%q) hit an empty cache", h.Key())
+		}
+		if !cache.PutHashed(h, i) {
+			t.Fatalf("PutHashed(%q) was rejected", h.Key())
+		}
+	}
+
+	// Interchangeable with the unhashed API
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		if v, ok := cache.Get(key); !ok || v != i {
+			t.Errorf("Get(%q) = %d, %v; want %d", key, v, ok, i)
+		}
+		cache.Put(key, i*2)
+		if v, ok := cache.GetHashed(Prehash(key)); !ok || v != i*2 {
+			t.Errorf("GetHashed(%q) = %d, %v; want %d", key, v, ok, i*2)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after PutHashed: %v", report.Issues)
+	}
+}
+
+func BenchmarkCloxCacheGetHashed(b *testing.B) {
+	cfg := Config{NumShards: 16, SlotsPerShard: 1024, Capacity: 8192}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	keys := make([]HashedKey[string], 1000)
+	for i := range keys {
+		keys[i] = Prehash(fmt.Sprintf("tenant-%d/object-%d", i%10, i))
+		cache.PutHashed(keys[i], i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.GetHashed(keys[i%len(keys)])
+	}
+}
@@ -0,0 +1,32 @@
+package cache
+
+import "errors"
+
+// ErrNoKeys is returned when exporting the keys of a cache that doesn't store
+// them (WithHashOnlyKeys)
+var ErrNoKeys = errors.New("cache: keys are not stored (WithHashOnlyKeys)")
+
+// WithHashOnlyKeys trusts the key hash alone and doesn't store keys at all,
+// which saves the key bytes of every entry in caches of many small values over
+// a controlled keyspace. Lookups match the 64-bit hash, or the 128-bit hash when
+// combined with WithHash128, and never compare key bytes.
+//
+// A collision returns another key's value. Among n keys, two share a 64-bit hash
+// with probability about n²/2⁶⁵: roughly 3% for a billion keys, and 1 in 37 million
+// for a million, so use WithHash128 (about n²/2¹²⁹) unless the keyspace is small or
+// an occasional wrong value is harmless.
+//
+// Without keys, everything that hands keys back sees the zero K: hooks,
+// Snapshot and other iteration, and Ghosts. WriteSnapshot, ExportGob and
+// DumpJSON return ErrNoKeys, Merge skips hash-only sources, and evicted values
+// aren't demoted to an overflow store, which couldn't find them again.
+func WithHashOnlyKeys[K Key, V any]() Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.hashOnly = true
+	}
+}
+
+// HashOnlyKeys reports whether the cache stores no keys (WithHashOnlyKeys)
+func (c *CloxCache[K, V]) HashOnlyKeys() bool {
+	return c.hashOnly
+}
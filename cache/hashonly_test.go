@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheHashOnlyKeys(t *testing.T) {
+	for _, wide := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hash128=%v", wide), func(t *testing.T) {
+			opts := []Option[string, int]{WithHashOnlyKeys[string, int](), WithCodec[string, int](GobCodec[int]{})}
+			if wide {
+				opts = append(opts, WithHash128[string, int]())
+			}
+			cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 256}
+			cache := NewCloxCache(cfg, opts...)
+			defer cache.Close()
+
+			for i := range 100 {
+				cache.Put(fmt.Sprintf("user:%d:profile-with-a-longer-key", i), i)
+			}
+			for i := range 100 {
+				key := fmt.Sprintf("user:%d:profile-with-a-longer-key", i)
+				if v, ok := cache.Get(key); !ok || v != i {
+					t.Fatalf("Get(%q) = %d, %v; want %d", key, v, ok, i)
+				}
+			}
+			if _, ok := cache.Get("user:100:profile-with-a-longer-key"); ok {
+				t.Error("Get hit a key that was never stored")
+			}
+
+			cache.rangeNodes(func(_ int, node *recordNode[string, int]) bool {
+				if node.key != "" || node.inlineLen != 0 {
+					t.Fatalf("Node stored key %q", node.fullKey())
+				}
+				return true
+			})
+
+			if !cache.Delete("user:7:profile-with-a-longer-key") {
+				t.Error("Delete missed a hash-only key")
+			}
+			if report := cache.VerifyIntegrity(); !report.OK() {
+				t.Errorf("Integrity issues without stored keys: %v", report.Issues)
+			}
+
+			var buf bytes.Buffer
+			if err := cache.WriteSnapshot(&buf); !errors.Is(err, ErrNoKeys) {
+				t.Errorf("WriteSnapshot = %v, want ErrNoKeys", err)
+			}
+			if err := cache.ExportGob(&buf); !errors.Is(err, ErrNoKeys) {
+				t.Errorf("ExportGob = %v, want ErrNoKeys", err)
+			}
+			if err := cache.DumpJSON(&buf, false); !errors.Is(err, ErrNoKeys) {
+				t.Errorf("DumpJSON = %v, want ErrNoKeys", err)
+			}
+
+			target := NewCloxCache[string, int](cfg)
+			defer target.Close()
+			if n := target.Merge(cache, nil); n != 0 {
+				t.Errorf("Merge took %d entries without keys", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+// EvictReason describes how an entry left the live set
+type EvictReason int
+
+const (
+	// EvictReasonGhosted - the entry became a ghost: its value was released but its frequency is remembered
+	EvictReasonGhosted EvictReason = iota
+	// EvictReasonRemoved - the entry was unlinked from the cache entirely
+	EvictReasonRemoved
+	// EvictReasonDeleted - the entry was removed explicitly by Delete or DeleteFunc
+	EvictReasonDeleted
+	// EvictReasonInvalidated - the entry was reclaimed after InvalidateAll
+	EvictReasonInvalidated
+	// EvictReasonReleased - the soft entry was dropped by ReleaseSoft
+	EvictReasonReleased
+	// EvictReasonExpired - the entry was reclaimed after its TTL passed (see PutWithTTL)
+	EvictReasonExpired
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictReasonGhosted:
+		return "ghosted"
+	case EvictReasonRemoved:
+		return "removed"
+	case EvictReasonDeleted:
+		return "deleted"
+	case EvictReasonInvalidated:
+		return "invalidated"
+	case EvictReasonReleased:
+		return "released"
+	case EvictReasonExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Hooks is a set of optional callbacks invoked on cache events.
+// Callbacks run synchronously on the goroutine performing the operation and must
+// be fast and safe for concurrent use. OnEvict runs while the shard lock is held,
+// so it must not call back into the same cache.
+type Hooks[K Key, V any] struct {
+	OnHit   func(key K, value V)
+	OnMiss  func(key K)
+	OnPut   func(key K, value V)
+	OnEvict func(key K, value V, reason EvictReason)
+
+	// SampleEvery limits OnHit and OnMiss to roughly one in every N Gets per shard
+	// (0 or 1 = every Get). OnPut and OnEvict are never sampled.
+	SampleEvery uint64
+}
+
+// sampled reports whether the Get with the given per-shard op number fires hooks
+func (h *Hooks[K, V]) sampled(op uint64) bool {
+	return h.SampleEvery <= 1 || op%h.SampleEvery == 0
+}
+
+// WithHooks registers event callbacks. Keys passed to hooks are the cache's own
+// copy for OnEvict and the caller's key otherwise; neither may be modified.
+func WithHooks[K Key, V any](hooks Hooks[K, V]) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.hooks = &hooks
+	}
+}
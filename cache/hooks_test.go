@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheHooks(t *testing.T) {
+	var mu sync.Mutex
+	var hits, misses, puts int
+	evictions := map[EvictReason]int{}
+
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache(cfg, WithHooks(Hooks[string, int]{
+		OnHit:  func(string, int) { mu.Lock(); hits++; mu.Unlock() },
+		OnMiss: func(string) { mu.Lock(); misses++; mu.Unlock() },
+		OnPut:  func(string, int) { mu.Lock(); puts++; mu.Unlock() },
+		OnEvict: func(key string, value int, reason EvictReason) {
+			mu.Lock()
+			evictions[reason]++
+			mu.Unlock()
+			if key != fmt.Sprintf("key-%d", value) {
+				t.Errorf("OnEvict got mismatched key %q and value %d", key, value)
+			}
+		},
+	}))
+	defer cache.Close()
+
+	// 4 fill the cache, the next 4 push the first 4 into ghosts
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := range 8 {
+		cache.Get(fmt.Sprintf("key-%d", i))
+	}
+
+	if puts != 8 {
+		t.Errorf("Expected 8 OnPut calls, got %d", puts)
+	}
+	if hits != 4 || misses != 4 {
+		t.Errorf("Expected 4 hits and 4 misses, got %d/%d", hits, misses)
+	}
+	if evictions[EvictReasonGhosted] != 4 {
+		t.Errorf("Expected 4 ghosted evictions, got %v", evictions)
+	}
+}
+
+func TestCloxCacheHooksSampling(t *testing.T) {
+	hits := 0
+	cache := NewCloxCache(Config{NumShards: 1, SlotsPerShard: 64}, WithHooks(Hooks[string, int]{
+		OnHit:       func(string, int) { hits++ },
+		SampleEvery: 10,
+	}))
+	defer cache.Close()
+
+	cache.Put("key", 1)
+	for range 100 {
+		cache.Get("key")
+	}
+	if hits != 10 {
+		t.Errorf("Expected 10 sampled hits, got %d", hits)
+	}
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"iter"
+	"sync"
+)
+
+// valueIndexer is maintained by the cache whenever the value stored for a key changes
+type valueIndexer[K Key, V any] interface {
+	update(key K, from, to *V)
+}
+
+// Index maps a value-derived attribute to the keys whose live values have it,
+// e.g. the IDs of the objects a cached page references. It is created with
+// AddIndex and kept up to date on Put, Delete, and eviction.
+type Index[K Key, V any, I comparable] struct {
+	cache   *CloxCache[K, V]
+	extract func(V) I
+
+	mu sync.RWMutex
+	// keys counts, per attribute, how many stored values of each key produced it.
+	// Updates are applied as +1/-1 so concurrent updates commute.
+	keys map[I]map[string]int32
+}
+
+// AddIndex registers an index on c that groups keys by extract(value) and fills it
+// from the current contents. extract must be fast, pure, and safe for concurrent use.
+// Indexes are not copied by Clone.
+func AddIndex[K Key, V any, I comparable](c *CloxCache[K, V], extract func(V) I) *Index[K, V, I] {
+	ix := &Index[K, V, I]{
+		cache:   c,
+		extract: extract,
+		keys:    make(map[I]map[string]int32),
+	}
+
+	// Register first so changes made during the backfill are tracked
+	for {
+		old := c.valueIndexes.Load()
+		var indexes []valueIndexer[K, V]
+		if old != nil {
+			indexes = append(indexes, *old...)
+		}
+		indexes = append(indexes, ix)
+		if c.valueIndexes.CompareAndSwap(old, &indexes) {
+			break
+		}
+	}
+
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		slots := shard.slots()
+		for s := range slots {
+			for node := slots[s].Load(); node != nil; node = node.next.Load() {
+				if vp := node.value.Load(); node.freq.Load() > 0 && vp != nil {
+					ix.update(node.fullKey(), nil, vp)
+				}
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return ix
+}
+
+func (ix *Index[K, V, I]) update(key K, from, to *V) {
+	var fromAttr, toAttr I
+	if from != nil {
+		fromAttr = ix.extract(*from)
+	}
+	if to != nil {
+		toAttr = ix.extract(*to)
+	}
+	if from != nil && to != nil && fromAttr == toAttr {
+		return
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if from != nil {
+		ix.add(fromAttr, string(key), -1)
+	}
+	if to != nil {
+		ix.add(toAttr, string(key), 1)
+	}
+}
+
+// add adjusts the count for key under attr. Caller must hold mu.
+func (ix *Index[K, V, I]) add(attr I, key string, delta int32) {
+	keys := ix.keys[attr]
+	if keys == nil {
+		keys = make(map[string]int32)
+		ix.keys[attr] = keys
+	}
+	if n := keys[key] + delta; n != 0 {
+		keys[key] = n
+	} else {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(ix.keys, attr)
+		}
+	}
+}
+
+// ByIndex returns an iterator over the live entries whose value has attribute
+// attr. Each candidate is re-checked against the cache, so entries changed
+// concurrently are never reported with a stale attribute. Entries are read
+// without recording an access, and no locks are held while yielding.
+func (ix *Index[K, V, I]) ByIndex(attr I) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ix.mu.RLock()
+		keys := make([]string, 0, len(ix.keys[attr]))
+		for key, n := range ix.keys[attr] {
+			if n > 0 {
+				keys = append(keys, key)
+			}
+		}
+		ix.mu.RUnlock()
+
+		for _, key := range keys {
+			k := keyFromString[K](key)
+			if v, ok := ix.cache.peek(k); ok && ix.extract(v) == attr && !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of distinct attributes currently indexed
+func (ix *Index[K, V, I]) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.keys)
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type indexedPage struct {
+	Author int
+	Body   string
+}
+
+func indexKeys[K Key, V any, I comparable](ix *Index[K, V, I], attr I) []string {
+	var keys []string
+	for k := range ix.ByIndex(attr) {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestCloxCacheIndex(t *testing.T) {
+	cache := NewCloxCache[string, indexedPage](Config{NumShards: 4, SlotsPerShard: 256, Capacity: 500})
+	defer cache.Close()
+
+	// Existing entries are backfilled
+	cache.Put("p1", indexedPage{Author: 1})
+	ix := AddIndex(cache, func(p indexedPage) int { return p.Author })
+
+	cache.Put("p2", indexedPage{Author: 1})
+	cache.Put("p3", indexedPage{Author: 2})
+
+	if got := indexKeys(ix, 1); fmt.Sprint(got) != "[p1 p2]" {
+		t.Errorf("Author 1 pages = %v, want [p1 p2]", got)
+	}
+
+	// Update moves the key between attributes
+	cache.Put("p2", indexedPage{Author: 2})
+	if got := indexKeys(ix, 1); fmt.Sprint(got) != "[p1]" {
+		t.Errorf("Author 1 pages after update = %v, want [p1]", got)
+	}
+	if got := indexKeys(ix, 2); fmt.Sprint(got) != "[p2 p3]" {
+		t.Errorf("Author 2 pages after update = %v, want [p2 p3]", got)
+	}
+
+	// Delete and DeleteFunc remove keys
+	cache.Delete("p1")
+	if got := indexKeys(ix, 1); len(got) != 0 {
+		t.Errorf("Expected no author 1 pages, got %v", got)
+	}
+	if ix.Len() != 1 {
+		t.Errorf("Expected 1 indexed attribute, got %d", ix.Len())
+	}
+
+	// Invalidate everything referencing author 2
+	var stale []string
+	for k := range ix.ByIndex(2) {
+		stale = append(stale, k)
+	}
+	for _, k := range stale {
+		cache.Delete(k)
+	}
+	if ix.Len() != 0 {
+		t.Errorf("Expected an empty index, got %d attributes", ix.Len())
+	}
+}
+
+func TestCloxCacheIndexEviction(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+	ix := AddIndex(cache, func(v int) int { return v % 4 })
+
+	for i := range 500 {
+		cache.Put(fmt.Sprintf("k%d", i), i)
+	}
+
+	total := 0
+	for attr := range 4 {
+		for k, v := range ix.ByIndex(attr) {
+			if v%4 != attr || k != fmt.Sprintf("k%d", v) {
+				t.Errorf("Entry %s=%d listed under %d", k, v, attr)
+			}
+			total++
+		}
+	}
+	if live := int(cache.shards[0].entryCount.Load()); total != live {
+		t.Errorf("Index lists %d entries, cache holds %d", total, live)
+	}
+
+	// Evicted and ghosted keys are dropped from the index
+	indexed := 0
+	ix.mu.RLock()
+	for _, keys := range ix.keys {
+		indexed += len(keys)
+	}
+	ix.mu.RUnlock()
+	if indexed != total {
+		t.Errorf("Index tracks %d keys, expected %d", indexed, total)
+	}
+}
+
+func TestCloxCacheIndexConcurrent(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 256})
+	defer cache.Close()
+	ix := AddIndex(cache, func(v int) int { return v % 3 })
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				key := fmt.Sprintf("k%d", i%50)
+				cache.Put(key, g*i)
+				if i%7 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for attr := range 3 {
+		for k, v := range ix.ByIndex(attr) {
+			if got, ok := cache.Get(k); !ok || got != v || v%3 != attr {
+				t.Errorf("Stale entry %s=%d under %d", k, v, attr)
+			}
+		}
+	}
+	// Every live key is findable through the index
+	for i := range 50 {
+		key := fmt.Sprintf("k%d", i)
+		v, ok := cache.Get(key)
+		if !ok {
+			continue
+		}
+		found := false
+		for k := range ix.ByIndex(v % 3) {
+			if k == key {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Live key %s=%d is missing from the index", key, v)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import "unsafe"
+
+// inlineKeySize is the longest key stored inside its node. Shorter keys (or key
+// suffixes, with WithKeyInterning) are copied into the node itself, saving the
+// separate allocation and the pointer chase on chain traversal; longer ones are
+// kept in the node's key field.
+const inlineKeySize = 24
+
+// storeInline copies key into the node if it fits, reporting whether it did
+func (n *recordNode[K, V]) storeInline(key K) bool {
+	if len(key) > inlineKeySize {
+		return false
+	}
+	n.inlineLen = uint8(len(key)) + 1
+	copy(n.inlineKey[:], key)
+	return true
+}
+
+// storedKey returns the key bytes the node holds (the suffix after an interned
+// prefix), viewing an inline key in place without copying it. The node's key is
+// never modified after creation, so the view stays valid for as long as it's used.
+func (n *recordNode[K, V]) storedKey() K {
+	if n.inlineLen == 0 {
+		return n.key
+	}
+	b := n.inlineKey[: n.inlineLen-1 : n.inlineLen-1]
+	// K's underlying type is string or []byte; their headers differ in size
+	var zero K
+	if unsafe.Sizeof(zero) == unsafe.Sizeof("") {
+		s := unsafe.String(unsafe.SliceData(b), len(b))
+		return *(*K)(unsafe.Pointer(&s))
+	}
+	return *(*K)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCloxCacheInlineKeys(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	long := strings.Repeat("k", inlineKeySize+1)
+	exact := strings.Repeat("e", inlineKeySize)
+	keys := []string{"", "a", "user:42", exact, long}
+	for i, key := range keys {
+		cache.Put(key, i)
+	}
+	for i, key := range keys {
+		if v, ok := cache.Get(key); !ok || v != i {
+			t.Errorf("Get(%q) = %d, %v; want %d", key, v, ok, i)
+		}
+	}
+	if _, ok := cache.Get(exact[:inlineKeySize-1]); ok {
+		t.Error("A prefix of an inline key matched")
+	}
+
+	cache.rangeNodes(func(_ int, node *recordNode[string, int]) bool {
+		key := node.fullKey()
+		if inline := node.inlineLen != 0; inline != (len(key) <= inlineKeySize) {
+			t.Errorf("Key of %d bytes stored inline = %v", len(key), inline)
+		}
+		if node.keyLen() != len(key) {
+			t.Errorf("keyLen = %d, want %d", node.keyLen(), len(key))
+		}
+		return true
+	})
+
+	var seen []string
+	for k := range cache.Snapshot() {
+		seen = append(seen, k)
+	}
+	if len(seen) != len(keys) {
+		t.Errorf("Snapshot yielded %d keys, want %d", len(seen), len(keys))
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues with inline keys: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheInlineByteKeys(t *testing.T) {
+	var evicted [][]byte
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64}
+	cache := NewCloxCache(cfg, WithHooks(Hooks[[]byte, int]{
+		OnEvict: func(key []byte, _ int, _ EvictReason) { evicted = append(evicted, key) },
+	}))
+	defer cache.Close()
+
+	buf := []byte("session-0")
+	for i := range 10 {
+		buf[len(buf)-1] = byte('0' + i)
+		cache.Put(buf, i) // the inline copy must not alias buf
+	}
+	for i := range 10 {
+		key := fmt.Appendf(nil, "session-%d", i)
+		if v, ok := cache.Get(key); !ok || v != i {
+			t.Errorf("Get(%s) = %d, %v; want %d", key, v, ok, i)
+		}
+	}
+
+	// Keys handed out by the cache stay intact after the node is removed
+	cache.Delete([]byte("session-3"))
+	if len(evicted) != 1 || string(evicted[0]) != "session-3" {
+		t.Errorf("OnEvict saw %q, want session-3", evicted)
+	}
+}
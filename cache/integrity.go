@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"fmt"
+	"maps"
+)
+
+// IntegrityIssue describes a structural problem found by VerifyIntegrity
+type IntegrityIssue struct {
+	Shard   int
+	Slot    int // -1 for shard-level issues
+	Kind    string
+	KeyHash uint64
+	Detail  string
+}
+
+func (i IntegrityIssue) String() string {
+	if i.Slot < 0 {
+		return fmt.Sprintf("shard %d: %s: %s", i.Shard, i.Kind, i.Detail)
+	}
+	return fmt.Sprintf("shard %d slot %d: %s (hash %016x): %s", i.Shard, i.Slot, i.Kind, i.KeyHash, i.Detail)
+}
+
+// IntegrityReport summarizes a full structural verification pass
+type IntegrityReport struct {
+	Shards     int
+	Slots      int
+	LiveNodes  int64
+	GhostNodes int64
+	Issues     []IntegrityIssue
+}
+
+// OK reports whether no issues were found
+func (r IntegrityReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// VerifyIntegrity walks every chain and validates the structure:
+//   - each node's keyHash matches its key and maps to the shard/slot holding it
+//   - chains contain no cycles and no duplicate keys
+//   - live nodes hold a value, ghosts have released theirs, frequencies are in range
+//   - shard counters (entries, ghosts, bytes, priority classes, TTL buckets) reconcile
+//     with the chains, and ghosts carry no TTL
+//   - every key is present in the prefix index, when enabled
+//
+// Each shard is locked while it is verified. Cost is O(entries) with a key hash per
+// node, so this is meant for tests, fuzzing and offline diagnosis, not hot paths.
+func (c *CloxCache[K, V]) VerifyIntegrity() IntegrityReport {
+	report := IntegrityReport{Shards: c.numShards}
+	for i := range c.shards {
+		c.verifyShard(i, &report)
+	}
+	return report
+}
+
+func (c *CloxCache[K, V]) verifyShard(shardID int, report *IntegrityReport) {
+	shard := &c.shards[shardID]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	issue := func(slot int, kind string, hash uint64, format string, args ...any) {
+		report.Issues = append(report.Issues, IntegrityIssue{
+			Shard: shardID, Slot: slot, Kind: kind, KeyHash: hash, Detail: fmt.Sprintf(format, args...),
+		})
+	}
+
+	var live, ghosts, liveBytes, protected, lir, ttlExpired int64
+	classLive := make([]int64, len(c.classFloors))
+	ttlBuckets := make(map[uint32]int64)
+	slots := shard.slots()
+	report.Slots += len(slots)
+	for s := range slots {
+		seen := make(map[*recordNode[K, V]]struct{})
+		keys := make(map[string]struct{})
+
+		for node := slots[s].Load(); node != nil; node = node.next.Load() {
+			if _, ok := seen[node]; ok {
+				issue(s, "cycle", node.keyHash, "chain loops back to an earlier node")
+				break
+			}
+			seen[node] = struct{}{}
+
+			// Without stored keys a node is identified by its hashes
+			id := string(node.fullKey())
+			if c.hashOnly {
+				id = fmt.Sprintf("%016x%016x", node.keyHash, node.keyHashHi)
+			} else if hash, hi := c.hashes(node.fullKey()); hash != node.keyHash || hi != node.keyHashHi {
+				issue(s, "hash-mismatch", node.keyHash, "key hashes to %016x", hash)
+			}
+			if shardFor, slotFor := c.locate(node.keyHash); shardFor != shard || slotFor != &slots[s] {
+				issue(s, "misplaced", node.keyHash, "node is not in the slot its hash maps to")
+			}
+			if c.prefixIndex != nil && !c.prefixIndex.contains(string(node.fullKey())) {
+				issue(s, "prefix-index", node.keyHash, "key is missing from the prefix index")
+			}
+			if _, dup := keys[id]; dup {
+				issue(s, "duplicate-key", node.keyHash, "key appears more than once in the chain")
+			}
+			keys[id] = struct{}{}
+
+			f := node.freq.Load()
+			if f > maxFrequency || f < -maxFrequency {
+				issue(s, "freq-range", node.keyHash, "freq=%d outside [-%d, %d]", f, maxFrequency, maxFrequency)
+			}
+			hasValue := node.value.Load() != nil
+			if f > 0 {
+				live++
+				liveBytes += node.size.Load()
+				if f > initialFreq {
+					protected++
+				}
+				if node.lir {
+					lir++
+				}
+				if int(node.class) < len(classLive) {
+					classLive[node.class]++
+				}
+				if bucket := node.expires.Load(); bucket != 0 && bucket <= shard.ttlSwept {
+					ttlExpired++
+				} else if bucket != 0 {
+					ttlBuckets[bucket]++
+				}
+				if !hasValue {
+					issue(s, "live-without-value", node.keyHash, "live node (freq=%d) has no value", f)
+				}
+			} else {
+				ghosts++
+				if hasValue {
+					issue(s, "ghost-retains-value", node.keyHash, "ghost (freq=%d) still holds a value", f)
+				}
+				if node.expires.Load() != 0 {
+					issue(s, "ghost-ttl", node.keyHash, "ghost (freq=%d) has an expiry bucket", f)
+				}
+			}
+		}
+	}
+
+	report.LiveNodes += live
+	report.GhostNodes += ghosts
+	if n := shard.entryCount.Load(); n != live {
+		issue(-1, "entry-count", 0, "entryCount=%d, chains hold %d live nodes", n, live)
+	}
+	if n := shard.ghostCount.Load(); n != ghosts {
+		issue(-1, "ghost-count", 0, "ghostCount=%d, chains hold %d ghosts", n, ghosts)
+	}
+	if n := shard.liveBytes.Load(); n != liveBytes {
+		issue(-1, "size-bytes", 0, "liveBytes=%d, live nodes weigh %d", n, liveBytes)
+	}
+	if n := shard.protected.Load(); c.segmented() && n != protected {
+		issue(-1, "protected-count", 0, "protected=%d, chains hold %d protected nodes", n, protected)
+	}
+	if n := shard.lirCount.Load(); n != lir {
+		issue(-1, "lir-count", 0, "lirCount=%d, chains hold %d LIR nodes", n, lir)
+	}
+	for class, want := range classLive {
+		if n := shard.classCounts[class].Load(); n != want {
+			issue(-1, "class-count", 0, "class %d count=%d, chains hold %d live nodes", class, n, want)
+		}
+	}
+	if n := shard.ttlExpired.Load(); n != ttlExpired {
+		issue(-1, "ttl-expired", 0, "ttlExpired=%d, chains hold %d expired nodes", n, ttlExpired)
+	}
+	if !maps.Equal(shard.ttlBuckets, ttlBuckets) {
+		issue(-1, "ttl-buckets", 0, "ttlBuckets=%v, chains hold %v", shard.ttlBuckets, ttlBuckets)
+	}
+	var ttlLive int64
+	var ttlLiveSum uint64
+	for bucket, n := range ttlBuckets {
+		ttlLive += n
+		ttlLiveSum += uint64(bucket) * uint64(n)
+	}
+	if n, sum := shard.ttlLive.Load(), shard.ttlLiveSum.Load(); n != ttlLive || sum != ttlLiveSum {
+		issue(-1, "ttl-live", 0, "ttlLive=%d (bucket sum %d), chains hold %d (sum %d)", n, sum, ttlLive, ttlLiveSum)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheVerifyIntegrity(t *testing.T) {
+	cfg := Config{
+		NumShards:     4,
+		SlotsPerShard: 32,
+		Capacity:      64,
+		SweepPercent:  50,
+	}
+	cache := NewCloxCache[[]byte, string](cfg)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				key := fmt.Appendf(nil, "key-%d", (i*7+w)%500)
+				cache.Put(key, string(key))
+				cache.Get(key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := cache.VerifyIntegrity()
+	if !report.OK() {
+		t.Fatalf("Integrity issues after concurrent load: %v", report.Issues)
+	}
+	if report.Slots != 4*32 || report.LiveNodes == 0 || report.GhostNodes == 0 {
+		t.Errorf("Unexpected report totals: %+v", report)
+	}
+}
+
+func TestCloxCacheVerifyIntegrityDetectsCorruption(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 1, Capacity: 4})
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	// Corrupt the chain: duplicate "a" at the head, break a hash, and drop a value
+	shard := &cache.shards[0]
+	head := shard.slots()[0].Load()
+	dup := &recordNode[string, int]{keyHash: hashKey("a"), key: "a"}
+	one := 1
+	dup.value.Store(&one)
+	dup.freq.Store(1)
+	dup.next.Store(head)
+	shard.slots()[0].Store(dup)
+	head.keyHash ^= 1
+	head.value.Store(nil)
+
+	report := cache.VerifyIntegrity()
+	kinds := map[string]bool{}
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = true
+	}
+	for _, want := range []string{"duplicate-key", "hash-mismatch", "live-without-value", "entry-count"} {
+		if !kinds[want] {
+			t.Errorf("Missing issue %q in %v", want, report.Issues)
+		}
+	}
+}
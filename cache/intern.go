@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+)
+
+// minInternedPrefix is the shortest prefix worth interning: shorter ones save
+// less than the pointer a node needs to reference them
+const minInternedPrefix = 16
+
+// keyInterner deduplicates key prefixes shared by many entries
+type keyInterner struct {
+	sep      byte
+	mu       sync.RWMutex
+	prefixes map[string]*string
+}
+
+// WithKeyInterning stores each key's prefix up to and including its last sep byte
+// (e.g. "tenant-42/route/" for sep '/') once for the whole cache instead of once
+// per entry, so caches of many keys with long shared prefixes don't spend their
+// memory on duplicated key bytes. Entries keep only the rest of the key and a
+// pointer to the shared prefix. Prefixes shorter than 16 bytes aren't interned.
+//
+// Distinct prefixes are kept for the cache's lifetime, so this suits keys built
+// from a bounded set of prefixes (tenants, routes), not unique ones. Reading keys
+// back (hooks, iteration, dumps) reassembles them, which allocates.
+func WithKeyInterning[K Key, V any](sep byte) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.interner = &keyInterner{sep: sep, prefixes: make(map[string]*string)}
+	}
+}
+
+// intern returns the shared copy of prefix
+func (in *keyInterner) intern(prefix string) *string {
+	in.mu.RLock()
+	p, ok := in.prefixes[prefix]
+	in.mu.RUnlock()
+	if ok {
+		return p
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if p, ok := in.prefixes[prefix]; ok {
+		return p
+	}
+	s := strings.Clone(prefix)
+	in.prefixes[s] = &s
+	return &s
+}
+
+// Len returns the number of distinct prefixes interned
+func (in *keyInterner) Len() int {
+	in.mu.RLock()
+	defer in.mu.RUnlock()
+	return len(in.prefixes)
+}
+
+// storeKey sets node's key from a caller's key, interning its prefix if enabled
+// and copying what the node keeps so caller mutations can't affect it
+func (c *CloxCache[K, V]) storeKey(node *recordNode[K, V], key K) {
+	if c.hashOnly {
+		return
+	}
+	if c.interner != nil {
+		s := string(key)
+		if i := strings.LastIndexByte(s, c.interner.sep); i+1 >= minInternedPrefix {
+			node.prefix = c.interner.intern(s[:i+1])
+			if !node.storeInline(key[i+1:]) {
+				node.key = K(strings.Clone(s[i+1:]))
+			}
+			return
+		}
+	}
+	if !node.storeInline(key) {
+		if c.config.NoCopyKeys {
+			node.key = key
+		} else {
+			node.key = copyKey(key)
+		}
+	}
+}
+
+// ownKey returns a key the cache read from its own buffers or a decoder, copied
+// if storeKey would keep it as is: NoCopyKeys only applies to keys callers pass in
+func (c *CloxCache[K, V]) ownKey(key K) K {
+	if c.config.NoCopyKeys {
+		return copyKey(key)
+	}
+	return key
+}
+
+// keyFromString converts a key kept as a string (by an index or the dependency
+// graph) back to K. For []byte keys the conversion allocates, so the result
+// never aliases the string and may be kept with NoCopyKeys.
+func keyFromString[K Key](s string) K {
+	return K(s)
+}
+
+// InternedPrefixes returns the number of distinct key prefixes shared through
+// WithKeyInterning (0 without it)
+func (c *CloxCache[K, V]) InternedPrefixes() int {
+	if c.interner == nil {
+		return 0
+	}
+	return c.interner.Len()
+}
+
+// fullKey returns the node's key, reassembling an interned prefix (which allocates)
+func (n *recordNode[K, V]) fullKey() K {
+	if n.prefix == nil {
+		return n.storedKey()
+	}
+	return K(*n.prefix + string(n.storedKey()))
+}
+
+// keyEquals reports whether the node holds key, without reassembling it
+func (n *recordNode[K, V]) keyEquals(key K) bool {
+	rest := n.storedKey()
+	if n.prefix == nil {
+		return keysEqual(rest, key)
+	}
+	p := *n.prefix
+	return len(key) == len(p)+len(rest) && string(key[:len(p)]) == p && keysEqual(rest, key[len(p):])
+}
+
+// keyLen returns the length of the node's key
+func (n *recordNode[K, V]) keyLen() int {
+	l := len(n.key)
+	if n.inlineLen != 0 {
+		l = int(n.inlineLen) - 1
+	}
+	if n.prefix != nil {
+		l += len(*n.prefix)
+	}
+	return l
+}
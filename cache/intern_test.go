@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheKeyInterning(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 128}
+	cache := NewCloxCache(cfg, WithKeyInterning[string, int]('/'), WithCodec[string, int](GobCodec[int]{}))
+	defer cache.Close()
+
+	for i := range 50 {
+		cache.Put(fmt.Sprintf("tenant-0042/products/%d", i), i)
+		cache.Put(fmt.Sprintf("tenant-0042/orders/%d", i), i)
+	}
+	cache.Put("a/short", 1) // prefix too short to intern
+	if n := cache.InternedPrefixes(); n != 2 {
+		t.Errorf("InternedPrefixes = %d, want 2", n)
+	}
+
+	for i := range 50 {
+		key := fmt.Sprintf("tenant-0042/products/%d", i)
+		if v, ok := cache.Get(key); !ok || v != i {
+			t.Fatalf("Get(%s) = %d, %v; want %d", key, v, ok, i)
+		}
+	}
+	if _, ok := cache.Get("tenant-0042/products/"); ok {
+		t.Error("The bare prefix matched an entry")
+	}
+	if _, ok := cache.Get("tenant-0042/orders/1x"); ok {
+		t.Error("A longer key matched an entry")
+	}
+
+	var prefix *string
+	cache.rangeNodes(func(_ int, node *recordNode[string, int]) bool {
+		if node.fullKey() == "tenant-0042/products/7" {
+			prefix = node.prefix
+			if node.storedKey() != "7" {
+				t.Errorf("Node kept %q, want only the suffix", node.storedKey())
+			}
+		}
+		return true
+	})
+	if prefix == nil || *prefix != "tenant-0042/products/" {
+		t.Fatalf("Expected the shared prefix on the node, got %v", prefix)
+	}
+
+	if !cache.Delete("tenant-0042/products/7") {
+		t.Error("Delete missed an interned key")
+	}
+
+	// Snapshots carry whole keys
+	var buf bytes.Buffer
+	if err := cache.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewCloxCache[string, int](cfg, WithCodec[string, int](GobCodec[int]{}))
+	defer restored.Close()
+	if n, err := restored.ReadSnapshot(&buf); err != nil || n != 100 {
+		t.Errorf("ReadSnapshot = %d, %v; want 100 entries", n, err)
+	}
+	if v, ok := restored.Get("tenant-0042/orders/3"); !ok || v != 3 {
+		t.Errorf("Restored Get = %d, %v; want 3", v, ok)
+	}
+
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues with interned keys: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheKeyInterningBytes(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache(cfg, WithKeyInterning[[]byte, int]('/'))
+	defer cache.Close()
+
+	key := []byte("region-eu-west/cache/item")
+	cache.Put(key, 1)
+	key[len(key)-1] = 'X' // caller reuses its buffer
+	if v, ok := cache.Get([]byte("region-eu-west/cache/item")); !ok || v != 1 {
+		t.Errorf("Get = %d, %v; want 1 (stored key must not alias the caller's)", v, ok)
+	}
+}
+
+func TestCloxCacheNoCopyKeys(t *testing.T) {
+	long := func(i int) []byte { return fmt.Appendf(nil, "a-key-too-long-to-be-inlined-%d", i) }
+	stored := func(cache *CloxCache[[]byte, int], key []byte) []byte {
+		hash, _ := cache.hashes(key)
+		_, slot := cache.locate(hash)
+		for node := slot.Load(); node != nil; node = node.next.Load() {
+			if bytes.Equal(node.storedKey(), key) {
+				return node.key
+			}
+		}
+		t.Fatalf("%s not found", key)
+		return nil
+	}
+
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 2048}
+	copied := NewCloxCache[[]byte, int](cfg)
+	defer copied.Close()
+	cfg.NoCopyKeys = true
+	shared := NewCloxCache[[]byte, int](cfg)
+	defer shared.Close()
+
+	key := long(0)
+	copied.Put(key, 0)
+	shared.Put(key, 0)
+	if &stored(copied, key)[0] == &key[0] {
+		t.Error("Key was stored without a copy by default")
+	}
+	if &stored(shared, key)[0] != &key[0] {
+		t.Error("NoCopyKeys copied the key")
+	}
+
+	// Skipping the copy saves one allocation per insert
+	keys := make([][]byte, 400)
+	for i := range keys {
+		keys[i] = long(i + 1)
+	}
+	allocs := func(cache *CloxCache[[]byte, int], keys [][]byte) float64 {
+		i := 0
+		return testing.AllocsPerRun(len(keys)-1, func() {
+			cache.Put(keys[i], i)
+			i++
+		})
+	}
+	withCopy, without := allocs(copied, keys[:200]), allocs(shared, keys[200:])
+	if withCopy-without != 1 {
+		t.Errorf("Inserts allocated %v times with key copies, %v without; want one fewer", withCopy, without)
+	}
+}
+
+func TestCloxCacheNoCopyKeysRestore(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 1024, Capacity: 8192}
+	src := NewCloxCache[[]byte, int](cfg, WithCodec[[]byte, int](GobCodec[int]{}))
+	defer src.Close()
+	const n = 5000
+	for i := range n {
+		src.Put(fmt.Appendf(nil, "a-restored-key-long-enough-to-skip-inlining-%d", i), i)
+	}
+	var buf bytes.Buffer
+	if err := src.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restored keys alias the snapshot reader's buffer, so they must be copied
+	cfg.NoCopyKeys = true
+	dst := NewCloxCache[[]byte, int](cfg, WithCodec[[]byte, int](GobCodec[int]{}))
+	defer dst.Close()
+	if loaded, err := dst.ReadSnapshot(&buf); err != nil || loaded != n {
+		t.Fatalf("ReadSnapshot = %d, %v; want %d", loaded, err, n)
+	}
+	missing := 0
+	for i := range n {
+		if v, ok := dst.Get(fmt.Appendf(nil, "a-restored-key-long-enough-to-skip-inlining-%d", i)); !ok || v != i {
+			missing++
+		}
+	}
+	if missing > 0 {
+		t.Errorf("%d of %d restored keys not found", missing, n)
+	}
+}
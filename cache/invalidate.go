@@ -0,0 +1,101 @@
+package cache
+
+import "time"
+
+// Generation-based invalidation.
+//
+// Every live node records the cache generation it was stored in, and the cache
+// keeps a floor: live nodes from generations below it are stale. InvalidateAll
+// starts a new generation and raises the floor to it, which makes all existing
+// entries stale in O(1): reads treat them as absent, and writers reclaim them
+// lazily instead of stalling on a sweep. ExpireAllAfter starts a new generation
+// too but only schedules the floor raise, so current entries keep serving until
+// the deadline while entries stored after the call survive it.
+//
+// A stale entry is reclaimed when a Put for its key reaches the shard lock, when an
+// eviction scan passes over it (stale entries are evicted before anything else,
+// without becoming ghosts), or during DeleteFunc and ExpireFunc. Ghosts carry no
+// value and are not affected: a key re-fetched after the invalidation keeps its
+// frequency history.
+
+// scheduledExpiry is a floor raise announced by ExpireAllAfter
+type scheduledExpiry struct {
+	at    int64  // unix nanoseconds at which it takes effect
+	floor uint64 // floor from then on
+}
+
+// InvalidateAll drops every entry currently in the cache without touching the
+// shards: Get misses for all existing keys from now on, and their memory is
+// reclaimed as the cache turns over. Entry counts (Stats, SizeBytes) include
+// invalidated entries until they are reclaimed. Values already demoted to an
+// overflow store stay there but are no longer promoted: a Get that loads one
+// discards it and misses. OnEvict reports reclaimed entries with
+// EvictReasonInvalidated. A no-op once the cache is closed.
+func (c *CloxCache[K, V]) InvalidateAll() {
+	if c.closed.Load() {
+		return
+	}
+	gen := c.generation.Add(1)
+	c.raiseFloor(gen)
+	c.logDebug("invalidated all entries", "generation", gen)
+}
+
+// ExpireAllAfter schedules the entries currently in the cache to expire once d
+// has passed, like an InvalidateAll that takes effect later: until then they keep
+// serving, so a scheduled data refresh doesn't start from a cold cache. Entries
+// stored after the call (including updates of existing keys) are not affected.
+// Only one expiry is pending at a time; a later call replaces one that hasn't
+// taken effect yet, whose entries then expire at the later deadline.
+// A d <= 0 invalidates immediately. A no-op once the cache is closed.
+func (c *CloxCache[K, V]) ExpireAllAfter(d time.Duration) {
+	if d <= 0 {
+		c.InvalidateAll()
+		return
+	}
+	if c.closed.Load() {
+		return
+	}
+	gen := c.generation.Add(1)
+	at := c.now().Add(d)
+	c.pendingExpiry.Store(&scheduledExpiry{at: at.UnixNano(), floor: gen})
+	c.logDebug("scheduled expiry of all entries", "generation", gen, "at", at)
+}
+
+// Generation returns the number of generations started by InvalidateAll and
+// ExpireAllAfter
+func (c *CloxCache[K, V]) Generation() uint64 {
+	return c.generation.Load()
+}
+
+// validFloor returns the oldest valid generation, applying a scheduled expiry
+// whose deadline has passed. The clock is only read while one is pending.
+func (c *CloxCache[K, V]) validFloor() uint64 {
+	if p := c.pendingExpiry.Load(); p != nil && c.now().UnixNano() >= p.at {
+		c.raiseFloor(p.floor)
+		c.pendingExpiry.CompareAndSwap(p, nil)
+	}
+	return c.floor.Load()
+}
+
+// raiseFloor moves the floor up to gen (never down)
+func (c *CloxCache[K, V]) raiseFloor(gen uint64) {
+	for floor := c.floor.Load(); floor < gen; floor = c.floor.Load() {
+		if c.floor.CompareAndSwap(floor, gen) {
+			return
+		}
+	}
+}
+
+// stale reports whether a live node was stored in a generation that has since
+// been invalidated or expired, or its own TTL has passed (see ttl.go)
+func (c *CloxCache[K, V]) stale(node *recordNode[K, V]) bool {
+	return node.gen.Load() < c.validFloor() || c.expired(node)
+}
+
+// staleReason returns the reason a stale node is reclaimed with
+func (c *CloxCache[K, V]) staleReason(node *recordNode[K, V]) EvictReason {
+	if node.gen.Load() >= c.validFloor() {
+		return EvictReasonExpired
+	}
+	return EvictReasonInvalidated
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheInvalidateAll(t *testing.T) {
+	// Full-shard scans, so every insert sees the invalidated entries
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16, SweepPercent: 100}
+	var reclaimed int
+	cache := NewCloxCache(cfg, WithHooks(Hooks[string, int]{
+		OnEvict: func(_ string, _ int, reason EvictReason) {
+			if reason == EvictReasonInvalidated {
+				reclaimed++
+			}
+		},
+	}))
+	defer cache.Close()
+
+	for i := range 16 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.InvalidateAll()
+	if g := cache.Generation(); g != 1 {
+		t.Errorf("Generation = %d, want 1", g)
+	}
+
+	for i := range 16 {
+		if _, ok := cache.Get(fmt.Sprintf("key-%d", i)); ok {
+			t.Fatalf("key-%d readable after InvalidateAll", i)
+		}
+	}
+	if _, ok := cache.Update("key-0", func(old int, found bool) int {
+		if found {
+			t.Error("Update saw an invalidated value")
+		}
+		return old + 100
+	}); !ok {
+		t.Error("Update after InvalidateAll should store")
+	}
+	if v, ok := cache.Get("key-0"); !ok || v != 100 {
+		t.Errorf("Get(key-0) = %d, %v; want 100, true", v, ok)
+	}
+
+	// New inserts reclaim the invalidated entries before evicting anything live
+	for i := range 15 {
+		cache.Put(fmt.Sprintf("new-%d", i), i)
+	}
+	if reclaimed != 16 {
+		t.Errorf("Reclaimed %d invalidated entries, want 16", reclaimed)
+	}
+	for i := range 15 {
+		if _, ok := cache.Get(fmt.Sprintf("new-%d", i)); !ok {
+			t.Errorf("new-%d was evicted while invalidated entries remained", i)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after InvalidateAll: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheInvalidateAllOverflow(t *testing.T) {
+	store := &mapOverflow{entries: make(map[string]int)}
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg, WithOverflow[string, int](store))
+	defer cache.Close()
+
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("old-%d", i), i)
+	}
+	if len(store.entries) == 0 {
+		t.Fatal("Nothing was demoted to the overflow store")
+	}
+	cache.InvalidateAll()
+
+	// Values demoted after the invalidation are promoted as usual
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("new-%d", i), i)
+	}
+	for i := range 200 {
+		if v, ok := cache.Get(fmt.Sprintf("old-%d", i)); ok {
+			t.Fatalf("Get(old-%d) = %d from the overflow store after InvalidateAll", i, v)
+		}
+	}
+	served := 0
+	for i := range 100 {
+		if v, ok := cache.Get(fmt.Sprintf("new-%d", i)); ok && v == i {
+			served++
+		}
+	}
+	if served != 100 {
+		t.Errorf("%d of 100 values stored after InvalidateAll were served", served)
+	}
+}
+
+func TestCloxCacheInvalidateAllSkipsInvalidated(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.InvalidateAll()
+	cache.Put("fresh", 1)
+
+	dst := NewCloxCache[string, int](cfg)
+	defer dst.Close()
+	if n := dst.Merge(cache, nil); n != 1 {
+		t.Errorf("Merge took %d entries, want only the one stored after InvalidateAll", n)
+	}
+	if n := cache.DeleteFunc(func(string, int) bool { return true }); n != 1 {
+		t.Errorf("DeleteFunc removed %d, want 1 (invalidated entries are reclaimed, not counted)", n)
+	}
+	if entries := cache.StatsSnapshot().Entries; entries != 0 {
+		t.Errorf("Entries after DeleteFunc = %d, want 0", entries)
+	}
+}
+
+func TestCloxCacheExpireAllAfter(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	cache.Put("old", 1)
+	cache.Put("updated", 1)
+	cache.ExpireAllAfter(50 * time.Millisecond)
+	cache.Put("new", 2)
+	cache.Put("updated", 2)
+
+	if _, ok := cache.Get("old"); !ok {
+		t.Error("Entry expired before the deadline")
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := cache.Get("old"); ok {
+		t.Error("Entry stored before ExpireAllAfter still readable after the deadline")
+	}
+	if v, ok := cache.Get("new"); !ok || v != 2 {
+		t.Errorf("Get(new) = %d, %v; want 2, true", v, ok)
+	}
+	if v, ok := cache.Get("updated"); !ok || v != 2 {
+		t.Errorf("Get(updated) = %d, %v; want 2, true", v, ok)
+	}
+	if cache.pendingExpiry.Load() != nil {
+		t.Error("Expected the pending expiry to be cleared once applied")
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+const cacheLineSize = 64
+
+func TestRecordNodeLayout(t *testing.T) {
+	checkNodeLayout[string](t)
+	checkNodeLayout[[]byte](t)
+}
+
+func checkNodeLayout[K Key](t *testing.T) {
+	var node recordNode[K, int]
+	name := func(field string) string {
+		var key K
+		return fmt.Sprintf("%s (%T keys)", field, key)
+	}
+
+	// Fields every lookup reads share the first line
+	for field, end := range map[string]uintptr{
+		"next":      unsafe.Offsetof(node.next) + unsafe.Sizeof(node.next),
+		"keyHash":   unsafe.Offsetof(node.keyHash) + unsafe.Sizeof(node.keyHash),
+		"keyHashHi": unsafe.Offsetof(node.keyHashHi) + unsafe.Sizeof(node.keyHashHi),
+		"freq":      unsafe.Offsetof(node.freq) + unsafe.Sizeof(node.freq),
+		"inlineLen": unsafe.Offsetof(node.inlineLen) + unsafe.Sizeof(node.inlineLen),
+		"gen":       unsafe.Offsetof(node.gen) + unsafe.Sizeof(node.gen),
+		"value":     unsafe.Offsetof(node.value) + unsafe.Sizeof(node.value),
+		"cost":      unsafe.Offsetof(node.cost) + unsafe.Sizeof(node.cost),
+	} {
+		if end > cacheLineSize {
+			t.Errorf("%s ends at byte %d, past the first cache line", name(field), end)
+		}
+	}
+
+	// Lookups read the TTL with the key on the second line
+	if end := unsafe.Offsetof(node.expires) + unsafe.Sizeof(node.expires); end > 2*cacheLineSize {
+		t.Errorf("%s ends at byte %d, past the second cache line", name("expires"), end)
+	}
+
+	// Fields written on access stay off the lines lookups read
+	for field, offset := range map[string]uintptr{
+		"lastAccess": unsafe.Offsetof(node.lastAccess),
+		"gdsfBase":   unsafe.Offsetof(node.gdsfBase),
+		"recent":     unsafe.Offsetof(node.recent),
+		"lirsLast":   unsafe.Offsetof(node.lirsLast),
+		"irr":        unsafe.Offsetof(node.irr),
+	} {
+		if offset < 2*cacheLineSize {
+			t.Errorf("%s starts at byte %d, within the lines read by lookups", name(field), offset)
+		}
+	}
+
+	if size := unsafe.Sizeof(node); size > 3*cacheLineSize {
+		t.Errorf("%s is %d bytes, more than %d", name("node"), size, 3*cacheLineSize)
+	}
+
+	// Nodes land in the allocator's 192-byte size class, whose objects start on cache lines
+	nodes := make([]*recordNode[K, int], 16)
+	for i := range nodes {
+		nodes[i] = new(recordNode[K, int])
+		if addr := uintptr(unsafe.Pointer(nodes[i])); addr%cacheLineSize != 0 {
+			t.Errorf("%s allocated at %#x, not on a cache line boundary", name("node"), addr)
+		}
+	}
+}
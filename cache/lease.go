@@ -0,0 +1,47 @@
+package cache
+
+import "sync/atomic"
+
+// Lease is a value read by GetLease and pinned until Release: it isn't finalized,
+// closed or otherwise reclaimed while held, even if the cache evicts, replaces or
+// deletes the entry meanwhile. Stored values are never modified in place, so the
+// value a lease holds doesn't change either.
+type Lease[K Key, V any] struct {
+	cache    *CloxCache[K, V]
+	shard    *shard[K, V]
+	key      K
+	vp       *V
+	released atomic.Bool
+}
+
+// GetLease returns a lease on the value for key, or false if key is missing.
+// Counts as an access like Get. The lease must be released: a value whose lease
+// is never released is never finalized.
+func (c *CloxCache[K, V]) GetLease(key K) (*Lease[K, V], bool) {
+	if _, ok := c.Get(key); !ok {
+		return nil, false
+	}
+	shard, vp, ok := c.acquire(key)
+	if !ok {
+		return nil, false // removed since the Get
+	}
+	return &Lease[K, V]{cache: c, shard: shard, key: copyKey(key), vp: vp}, true
+}
+
+// Value returns the leased value. It stays valid until Release.
+func (l *Lease[K, V]) Value() V {
+	return *l.vp
+}
+
+// Key returns the key the lease was taken for
+func (l *Lease[K, V]) Key() K {
+	return l.key
+}
+
+// Release ends the lease, running the finalizer if the value left the cache while
+// it was held. Safe to call more than once; only the first call counts.
+func (l *Lease[K, V]) Release() {
+	if !l.released.Swap(true) {
+		l.cache.release(l.shard, l.key, l.vp)
+	}
+}
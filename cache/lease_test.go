@@ -0,0 +1,72 @@
+package cache
+
+import "testing"
+
+func TestCloxCacheGetLease(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	var finalized []int
+	cache := NewCloxCache(cfg, WithFinalizer(func(_ string, v int) {
+		finalized = append(finalized, v)
+	}))
+	defer cache.Close()
+
+	if _, ok := cache.GetLease("missing"); ok {
+		t.Error("GetLease found a missing key")
+	}
+
+	cache.Put("a", 1)
+	lease, ok := cache.GetLease("a")
+	if !ok {
+		t.Fatal("GetLease missed a live key")
+	}
+	cache.Put("a", 2)
+	cache.Delete("a")
+	if lease.Value() != 1 || lease.Key() != "a" {
+		t.Errorf("Lease = %s=%d, want a=1", lease.Key(), lease.Value())
+	}
+	if len(finalized) != 1 || finalized[0] != 2 {
+		t.Errorf("Finalized %v while the lease was held, want only [2]", finalized)
+	}
+
+	lease.Release()
+	lease.Release() // idempotent
+	if len(finalized) != 2 || finalized[1] != 1 {
+		t.Errorf("Finalized %v after Release, want [2 1]", finalized)
+	}
+	if len(cache.shards[0].pins) != 0 {
+		t.Error("Release left a pin behind")
+	}
+}
+
+func TestCloxCacheGetLeaseHashOnly(t *testing.T) {
+	// Hash-only nodes don't keep their keys, so finalizing must find the pins by
+	// the node's hash, not by rehashing the (zero) key
+	finalized := 0
+	cache := NewCloxCache(Config{NumShards: 8, SlotsPerShard: 64, Capacity: 256},
+		WithHashOnlyKeys[string, int](),
+		WithFinalizer(func(string, int) { finalized++ }))
+	defer cache.Close()
+
+	zeroShard, _ := cache.locate(hashKey(""))
+	key := "a"
+	for i := 0; ; i++ {
+		if shard, _ := cache.locate(hashKey(key)); shard != zeroShard {
+			break
+		}
+		key = string(rune('b' + i))
+	}
+
+	cache.Put(key, 1)
+	lease, ok := cache.GetLease(key)
+	if !ok {
+		t.Fatal("GetLease missed")
+	}
+	cache.Delete(key)
+	if finalized != 0 {
+		t.Fatal("Leased value was finalized while in use")
+	}
+	lease.Release()
+	if finalized != 1 {
+		t.Errorf("Value finalized %d times after Release, want 1", finalized)
+	}
+}
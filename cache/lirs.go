@@ -0,0 +1,70 @@
+package cache
+
+// LIRS-style eviction (Config.Policy = PolicyLIRS).
+//
+// Every access stamps the node with the shard clock and records its inter-reference
+// recency (IRR), the gap since its previous access. Live entries are split into a
+// LIR set of entries with low IRR, sized to all but lirsHIRShare of the shard, and
+// resident HIR entries that churn through the rest. An entry joins the LIR set
+// while the set is still filling, or when it is re-accessed more recently than the
+// oldest LIR entry was last used (its previous access is "inside the LIRS stack"):
+// a promoted ghost is checked on re-insert and a live HIR entry when an eviction
+// scan passes over it. Victims are the least recently used HIR entries; when the
+// LIR set outgrows its share, the scan demotes its oldest LIR entry to HIR.
+//
+// Ghosts keep their access stamp, so a re-inserted key is judged by how long ago it
+// was really used. A loop over more keys than the cache holds keeps a stable LIR
+// subset resident instead of missing on every access as LRU does.
+
+// lirsHIRShare is the fraction (1/n) of each shard reserved for resident HIR entries
+const lirsHIRShare = 100
+
+// lirsTarget returns the shard's LIR set size
+func lirsTarget[K Key, V any](shard *shard[K, V]) int64 {
+	limit := shard.shardCapacity()
+	return max(limit-max(limit/lirsHIRShare, 1), 0)
+}
+
+// touchLIRS advances node's access clock and records its inter-reference gap.
+// A no-op for other policies.
+func (c *CloxCache[K, V]) touchLIRS(shard *shard[K, V], node *recordNode[K, V]) {
+	if c.config.Policy != PolicyLIRS {
+		return
+	}
+	now := shard.timestamp.Add(1)
+	if last := node.lirsLast.Swap(now); last != 0 {
+		node.irr.Store(now - last)
+	}
+}
+
+// lirsJoined decides whether a node that just became live joins the LIR set.
+// prevAccess is its access stamp before this insert (a ghost's last use, 0 if none).
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) lirsJoined(shard *shard[K, V], node *recordNode[K, V], prevAccess uint64) {
+	if c.config.Policy != PolicyLIRS {
+		return
+	}
+	if shard.lirCount.Load() < lirsTarget(shard) || (prevAccess != 0 && prevAccess > shard.lirsBottom.Load()) {
+		node.lir = true
+		shard.lirCount.Add(1)
+	}
+}
+
+// lirsLeft drops a node that is no longer live from the LIR set.
+// Caller must hold the shard lock.
+func (c *CloxCache[K, V]) lirsLeft(shard *shard[K, V], node *recordNode[K, V]) {
+	if node.lir {
+		node.lir = false
+		shard.lirCount.Add(-1)
+	}
+}
+
+// LIRCount returns the number of live entries in the LIR set across all shards
+// (0 unless the policy is PolicyLIRS)
+func (c *CloxCache[K, V]) LIRCount() int64 {
+	var n int64
+	for i := range c.shards {
+		n += c.shards[i].lirCount.Load()
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package cache
+
+import "testing"
+
+func TestCloxCacheLIRSPromotion(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, Policy: PolicyLIRS}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// The first three entries fill the LIR set; d is a resident HIR entry
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Put("d", 4)
+	if got := cache.LIRCount(); got != 3 {
+		t.Fatalf("LIRCount = %d, want 3", got)
+	}
+
+	// Reusing d while inside the stack promotes it and demotes the oldest LIR entry
+	cache.Get("d")
+	cache.Put("e", 5)
+	if _, ok := cache.peek("a"); ok {
+		t.Error("Expected the oldest LIR entry to be evicted")
+	}
+	if _, ok := cache.peek("d"); !ok {
+		t.Error("Reused HIR entry was evicted")
+	}
+	if got := cache.LIRCount(); got != 3 {
+		t.Errorf("LIRCount after promotion = %d, want 3", got)
+	}
+
+	// New HIR entries churn without touching the LIR set
+	cache.Put("f", 6)
+	if _, ok := cache.peek("e"); ok {
+		t.Error("Expected the HIR entry to be evicted")
+	}
+	for _, key := range []string{"b", "c", "d"} {
+		if _, ok := cache.peek(key); !ok {
+			t.Errorf("LIR entry %q was evicted", key)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
@@ -0,0 +1,87 @@
+package cache
+
+import "slices"
+
+// List helpers operate on caches whose values are slices, treating each entry as a
+// list that is modified atomically per key (see Update). Lists are copy-on-write:
+// every change stores a new slice, so slices returned by Get or ListRange are
+// never modified afterwards.
+//
+// Indices follow Redis conventions: start and stop are inclusive, and negative
+// values count from the end (-1 is the last element).
+
+// ListPushBack appends values to the list at key, creating it if absent, and
+// returns the new length (0 if the list could not be stored)
+func ListPushBack[K Key, E any](c *CloxCache[K, []E], key K, values ...E) int {
+	list, ok := c.Update(key, func(old []E, _ bool) []E {
+		return append(old[:len(old):len(old)], values...)
+	})
+	if !ok {
+		return 0
+	}
+	return len(list)
+}
+
+// ListPushFront prepends values (in order) to the list at key, creating it if
+// absent, and returns the new length (0 if the list could not be stored)
+func ListPushFront[K Key, E any](c *CloxCache[K, []E], key K, values ...E) int {
+	list, ok := c.Update(key, func(old []E, _ bool) []E {
+		return append(append(make([]E, 0, len(values)+len(old)), values...), old...)
+	})
+	if !ok {
+		return 0
+	}
+	return len(list)
+}
+
+// ListTrim keeps only the elements between start and stop (inclusive) of the list
+// at key and returns the new length. ListTrim(c, key, -100, -1) keeps the newest
+// 100 pushed with ListPushBack. Missing keys are not created.
+func ListTrim[K Key, E any](c *CloxCache[K, []E], key K, start, stop int) int {
+	list, ok := c.updateExisting(key, func(old []E) []E {
+		lo, hi := listBounds(len(old), start, stop)
+		if lo == 0 && hi == len(old) {
+			return old
+		}
+		// Copy so the trimmed-off elements can be collected
+		return slices.Clone(old[lo:hi])
+	})
+	if !ok {
+		return 0
+	}
+	return len(list)
+}
+
+// ListRange returns the elements between start and stop (inclusive) of the list
+// at key. The result shares memory with the cached list and must not be modified.
+// Counts as an access like Get.
+func ListRange[K Key, E any](c *CloxCache[K, []E], key K, start, stop int) []E {
+	list, ok := c.Get(key)
+	if !ok {
+		return nil
+	}
+	lo, hi := listBounds(len(list), start, stop)
+	return list[lo:hi:hi]
+}
+
+// ListLen returns the length of the list at key (0 if absent) without recording an access
+func ListLen[K Key, E any](c *CloxCache[K, []E], key K) int {
+	list, _ := c.peek(key)
+	return len(list)
+}
+
+// listBounds converts inclusive, possibly negative indices into a half-open range
+func listBounds(n, start, stop int) (lo, hi int) {
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	start = max(start, 0)
+	stop = min(stop, n-1)
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
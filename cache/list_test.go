@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheList(t *testing.T) {
+	cache := NewCloxCache[string, []int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	if n := ListPushBack(cache, "feed", 1, 2, 3); n != 3 {
+		t.Errorf("Expected length 3, got %d", n)
+	}
+	before, _ := cache.Get("feed")
+	if n := ListPushFront(cache, "feed", -1, 0); n != 5 {
+		t.Errorf("Expected length 5, got %d", n)
+	}
+	if got := ListRange(cache, "feed", 0, -1); !slices.Equal(got, []int{-1, 0, 1, 2, 3}) {
+		t.Errorf("ListRange = %v", got)
+	}
+	if !slices.Equal(before, []int{1, 2, 3}) {
+		t.Errorf("Previously read list was modified: %v", before)
+	}
+
+	tests := []struct {
+		start, stop int
+		want        []int
+	}{
+		{0, 1, []int{-1, 0}},
+		{-2, -1, []int{2, 3}},
+		{3, 100, []int{2, 3}},
+		{4, 2, []int{}},
+		{-100, 0, []int{-1}},
+	}
+	for _, tt := range tests {
+		if got := ListRange(cache, "feed", tt.start, tt.stop); !slices.Equal(got, tt.want) {
+			t.Errorf("ListRange(%d, %d) = %v, want %v", tt.start, tt.stop, got, tt.want)
+		}
+	}
+
+	if n := ListTrim(cache, "feed", -3, -1); n != 3 {
+		t.Errorf("Expected length 3 after trim, got %d", n)
+	}
+	if got := ListRange(cache, "feed", 0, -1); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("List after trim = %v", got)
+	}
+	if n := ListLen(cache, "feed"); n != 3 {
+		t.Errorf("ListLen = %d, want 3", n)
+	}
+
+	if n := ListTrim(cache, "missing", 0, 10); n != 0 {
+		t.Errorf("ListTrim on a missing key = %d", n)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("ListTrim created a missing key")
+	}
+	if got := ListRange(cache, "missing", 0, -1); got != nil {
+		t.Errorf("ListRange on a missing key = %v", got)
+	}
+}
+
+func TestCloxCacheListConcurrent(t *testing.T) {
+	cache := NewCloxCache[string, []string](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				ListPushBack(cache, "recent", fmt.Sprintf("%d-%d", g, i))
+				ListTrim(cache, "recent", -50, -1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := ListLen(cache, "recent"); n != 50 {
+		t.Errorf("Expected 50 recent items, got %d", n)
+	}
+
+	// No lost pushes without trimming
+	var wg2 sync.WaitGroup
+	for g := range 8 {
+		wg2.Add(1)
+		go func() {
+			defer wg2.Done()
+			for i := range 200 {
+				ListPushBack(cache, "all", fmt.Sprintf("%d-%d", g, i))
+			}
+		}()
+	}
+	wg2.Wait()
+	if n := ListLen(cache, "all"); n != 1600 {
+		t.Errorf("Expected 1600 items, got %d", n)
+	}
+}
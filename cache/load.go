@@ -0,0 +1,99 @@
+package cache
+
+import "io"
+
+// loadProgressEvery is how many snapshot entries LoadFrom reads between progress reports
+const loadProgressEvery = 1024
+
+// LoadProgress reports how far a LoadFrom has got
+type LoadProgress struct {
+	Loaded  int   // entries stored so far
+	Dropped int   // entries skipped because their TTL passed
+	Lost    int   // entries skipped because their snapshot block was corrupt
+	Bytes   int64 // snapshot bytes read so far
+	Done    bool  // the load finished, successfully if Err is nil
+	Err     error
+}
+
+// LoadFrom restores a snapshot written by WriteSnapshot in the background, so a
+// service can start serving before a large snapshot is read: entries become
+// visible as they load, and Gets for the rest simply miss until then. Snapshots
+// are written shard by shard, so shards fill one after another. Entries are
+// restored like ReadSnapshot does.
+//
+// progress, if set, is called from the loading goroutine every 1024 entries and
+// once more when the load finishes. The returned function waits for the load and
+// returns the number of entries loaded and its error. Closing the cache stops the
+// load with ErrClosed at its next read from r (Close waits for that read).
+func (c *CloxCache[K, V]) LoadFrom(r io.Reader, progress func(LoadProgress)) (wait func() (int, error)) {
+	return c.loadFrom(r, progress, func() {})
+}
+
+// loadFrom is LoadFrom that calls release once it is done with r
+func (c *CloxCache[K, V]) loadFrom(r io.Reader, progress func(LoadProgress), release func()) (wait func() (int, error)) {
+	var loaded int
+	var err error
+	done := make(chan struct{})
+	wait = func() (int, error) {
+		<-done
+		return loaded, err
+	}
+
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if c.closed.Load() {
+		err = ErrClosed
+		release()
+		close(done)
+		return wait
+	}
+	lr := &loadReader{r: r, stop: c.stop}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer close(done)
+		defer release()
+		var dropped, lost int
+		next := loadProgressEvery
+		report := func(p LoadProgress) {
+			if progress != nil {
+				p.Dropped, p.Lost, p.Bytes = dropped, lost, lr.read
+				progress(p)
+			}
+		}
+		loaded, err = c.readSnapshot(lr, c.restore, func(loaded, d, l int) {
+			dropped, lost = d, l
+			if loaded+dropped >= next {
+				next += loadProgressEvery
+				report(LoadProgress{Loaded: loaded})
+			}
+		})
+		if lr.stopped {
+			err = ErrClosed // not a corrupt snapshot
+		} else if err != nil {
+			c.logWarn("snapshot load failed", "loaded", loaded, "error", err)
+		}
+		report(LoadProgress{Loaded: loaded, Done: true, Err: err})
+	}()
+	return wait
+}
+
+// loadReader counts the bytes a LoadFrom read and fails once the cache closes
+type loadReader struct {
+	r       io.Reader
+	stop    chan struct{}
+	read    int64
+	stopped bool
+}
+
+func (l *loadReader) Read(p []byte) (int, error) {
+	select {
+	case <-l.stop:
+		l.stopped = true
+		return 0, ErrClosed
+	default:
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheLoadFrom(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 8192, Capacity: 32768}
+	src := NewCloxCache[string, int](cfg)
+	defer src.Close()
+	for i := range 20000 {
+		src.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	// The reader hands out the snapshot only when the test says so
+	gate := make(chan struct{})
+	r := &gatedReader{r: bytes.NewReader(data), gate: gate, after: len(data) / 2}
+	dst := NewCloxCache[string, int](cfg)
+	defer dst.Close()
+	var reports []LoadProgress
+	wait := dst.LoadFrom(r, func(p LoadProgress) { reports = append(reports, p) })
+
+	// The first half is served while the rest is still loading
+	deadline := time.Now().Add(2 * time.Second)
+	for dst.StatsSnapshot().Entries == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := dst.StatsSnapshot().Entries; n == 0 || n >= 20000 {
+		t.Errorf("%d entries visible mid-load, want some but not all", n)
+	}
+	close(gate)
+
+	if n, err := wait(); err != nil || n != 20000 {
+		t.Fatalf("LoadFrom = %d, %v; want 20000 entries", n, err)
+	}
+	if v, ok := dst.Get("key-19999"); !ok || v != 19999 {
+		t.Errorf("Get(key-19999) = %d, %v after the load", v, ok)
+	}
+	if len(reports) != 20000/loadProgressEvery+1 {
+		t.Fatalf("%d progress reports, want %d", len(reports), 20000/loadProgressEvery+1)
+	}
+	last := reports[len(reports)-1]
+	if !last.Done || last.Err != nil || last.Loaded != 20000 || last.Bytes != int64(len(data)) {
+		t.Errorf("Final progress = %+v, want done with 20000 entries and %d bytes", last, len(data))
+	}
+	if first := reports[0]; first.Done || first.Loaded != loadProgressEvery {
+		t.Errorf("First progress = %+v, want %d entries", first, loadProgressEvery)
+	}
+}
+
+func TestCloxCacheLoadFromClose(t *testing.T) {
+	src := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128})
+	defer src.Close()
+	src.Put("a", 1)
+	data, _ := src.MarshalBinary()
+
+	// Closing stops a load that is waiting on its reader
+	gate := make(chan struct{})
+	dst := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128})
+	wait := dst.LoadFrom(&gatedReader{r: bytes.NewReader(data), gate: gate}, nil)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(gate)
+	}()
+	dst.Close()
+	if _, err := wait(); !errors.Is(err, ErrClosed) {
+		t.Errorf("LoadFrom after Close = %v, want ErrClosed", err)
+	}
+	if _, err := dst.LoadFrom(bytes.NewReader(data), nil)(); !errors.Is(err, ErrClosed) {
+		t.Errorf("LoadFrom on a closed cache = %v, want ErrClosed", err)
+	}
+}
+
+// gatedReader reads up to after bytes, then blocks until gate is closed
+type gatedReader struct {
+	r     io.Reader
+	gate  chan struct{}
+	after int
+	read  int
+}
+
+func (g *gatedReader) Read(p []byte) (int, error) {
+	if g.read >= g.after {
+		<-g.gate
+	} else {
+		p = p[:min(len(p), g.after-g.read)]
+	}
+	n, err := g.r.Read(p)
+	g.read += n
+	return n, err
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logDebug emits a debug event if a logger is configured and debug is enabled.
+// Callers on hot paths pay only a nil check when logging is off.
+func (c *CloxCache[K, V]) logDebug(msg string, args ...any) {
+	if c.logger != nil && c.logger.Enabled(context.Background(), slog.LevelDebug) {
+		c.logger.Debug(msg, args...)
+	}
+}
+
+// logWarn emits a warning if a logger is configured
+func (c *CloxCache[K, V]) logWarn(msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Warn(msg, args...)
+	}
+}
+
+// warnMisconfiguration reports configurations that build but behave poorly
+func (c *CloxCache[K, V]) warnMisconfiguration(cfg Config, perShardCapacity, ghostCapacity int64) {
+	if c.logger == nil {
+		return
+	}
+	if ghostCapacity == 0 {
+		c.logWarn("no ghost capacity: SlotsPerShard must exceed per-shard capacity to remember evicted keys",
+			"slots_per_shard", cfg.SlotsPerShard, "per_shard_capacity", perShardCapacity)
+	}
+	if cfg.Capacity > 0 && cfg.Capacity < cfg.NumShards {
+		c.logWarn("capacity is smaller than the shard count; each shard holds at least one entry",
+			"capacity", cfg.Capacity, "num_shards", cfg.NumShards)
+	}
+	if cfg.SweepPercent > 100 {
+		c.logWarn("SweepPercent above 100 clamped to 100", "sweep_percent", cfg.SweepPercent)
+	}
+	if c.hashOnly && c.overflow != nil {
+		c.logWarn("WithHashOnlyKeys: evicted values can't be demoted to the overflow store without their keys")
+	}
+	if chains := float64(perShardCapacity+ghostCapacity) / float64(cfg.SlotsPerShard); chains > 2 {
+		c.logWarn("slots are undersized for the capacity; expect long collision chains",
+			"expected_chain_length", chains, "slots_per_shard", cfg.SlotsPerShard)
+	}
+}
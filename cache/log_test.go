@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestCloxCacheLoggerMisconfiguration(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{
+		NumShards:     4,
+		SlotsPerShard: 16,
+		Capacity:      64, // every slot is needed for live entries
+		Logger:        newTestLogger(&buf),
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	if !strings.Contains(buf.String(), "no ghost capacity") {
+		t.Errorf("Expected a ghost capacity warning, got:\n%s", buf.String())
+	}
+}
+
+func TestCloxCacheLoggerEvents(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+		Logger:        newTestLogger(&buf),
+	}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// Make every live entry protected, then force an eviction
+	for i := range 4 {
+		key := fmt.Sprintf("hot-%d", i)
+		cache.Put(key, i)
+		for range 5 {
+			cache.Get(key)
+		}
+	}
+	cache.Put("cold", 0)
+
+	if !strings.Contains(buf.String(), "evicting protected entry") {
+		t.Errorf("Expected a protected eviction event, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := cache.ReadSnapshot(strings.NewReader("garbage")); err == nil {
+		t.Fatal("Expected ReadSnapshot to fail")
+	}
+	if !strings.Contains(buf.String(), "level=WARN msg=\"snapshot load failed\"") {
+		t.Errorf("Expected a snapshot failure warning, got:\n%s", buf.String())
+	}
+}
+
+func TestCloxCacheNoLogger(t *testing.T) {
+	// Without a logger, misconfigured caches still build and run silently
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 16, Capacity: 64})
+	defer cache.Close()
+	cache.Put("key", 1)
+	if _, err := cache.ReadSnapshot(strings.NewReader("garbage")); err == nil {
+		t.Fatal("Expected ReadSnapshot to fail")
+	}
+}
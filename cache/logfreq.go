@@ -0,0 +1,34 @@
+package cache
+
+// Probabilistic frequency increments (Config.FrequencyLogFactor > 0).
+//
+// Like Redis's LFU counter, a hit raises a frequency f only with probability
+// 1/((f-1)*factor+1), so each step up takes more hits than the last: the 15
+// levels cover hundreds or thousands of hits instead of 15, and keys that are
+// already hot mostly skip the CAS and timestamp store a counted hit costs.
+
+// frequencyIncrements returns how many of hits on a node at frequency f raise it
+func (c *CloxCache[K, V]) frequencyIncrements(f, hits int32) int32 {
+	factor := c.config.FrequencyLogFactor
+	if factor <= 0 {
+		return hits
+	}
+	var n int32
+	for range hits {
+		base := max(f+n-initialFreq, 0)
+		if c.randN(uint32(base)*uint32(factor)+1) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// saturationHits returns the expected number of hits that take a new entry to
+// the maximum frequency with the given log factor
+func saturationHits(factor int) int {
+	hits := 0
+	for base := range maxFrequency - initialFreq {
+		hits += base*factor + 1
+	}
+	return hits
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloxCacheFrequencyLogFactor(t *testing.T) {
+	freqAfter := func(factor, gets int) int32 {
+		cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64, FrequencyLogFactor: factor})
+		defer cache.Close()
+		cache.Put("hot", 1)
+		for range gets {
+			cache.Get("hot")
+		}
+		hash, hi := cache.hashes("hot")
+		shard, _ := cache.locate(hash)
+		return cache.findNode(shard, hash, hi, "hot", nil).freq.Load()
+	}
+
+	if f := freqAfter(0, 20); f != maxFrequency {
+		t.Errorf("Without a log factor freq = %d after 20 hits, want %d", f, maxFrequency)
+	}
+	// The expected frequency after 200 hits at factor 10 is about 6; the bounds are
+	// far enough out that chance won't cross them
+	if f := freqAfter(10, 200); f <= initialFreq+1 || f >= maxFrequency {
+		t.Errorf("With log factor 10 freq = %d after 200 hits, want between %d and %d",
+			f, initialFreq+1, maxFrequency)
+	}
+}
+
+func TestFrequencyIncrements(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, FrequencyLogFactor: 1000})
+	defer cache.Close()
+
+	// A new entry's first hit always counts
+	if n := cache.frequencyIncrements(initialFreq, 1); n != 1 {
+		t.Errorf("First hit gave %d increments, want 1", n)
+	}
+	var total int32
+	for range 1000 {
+		total += cache.frequencyIncrements(maxFrequency-1, 1)
+	}
+	if total > 5 {
+		t.Errorf("%d of 1000 hits raised frequency %d, expected about 0.07", total, maxFrequency-1)
+	}
+}
+
+func TestSaturationHits(t *testing.T) {
+	if n := saturationHits(0); n != maxFrequency-initialFreq {
+		t.Errorf("saturationHits(0) = %d, want %d", n, maxFrequency-initialFreq)
+	}
+	if n := saturationHits(10); n != 924 {
+		t.Errorf("saturationHits(10) = %d, want 924", n)
+	}
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100, FrequencyLogFactor: 10}
+	if desc := cfg.Describe(); !strings.Contains(desc, "logarithmic (factor 10, about 924 hits") {
+		t.Errorf("Describe doesn't mention the log factor:\n%s", desc)
+	}
+}
@@ -13,8 +13,8 @@ func (c *CloxCache[K, V]) countEntries() int {
 	count := 0
 	for shardID := range c.shards {
 		shard := &c.shards[shardID]
-		for slotID := range shard.slots {
-			node := shard.slots[slotID].Load()
+		for slotID := range shard.slots() {
+			node := shard.slots()[slotID].Load()
 			for node != nil {
 				count++
 				node = node.next.Load()
@@ -26,11 +26,11 @@ func (c *CloxCache[K, V]) countEntries() int {
 
 // getSlotStats returns the total number of slots and occupied slots
 func (c *CloxCache[K, V]) getSlotStats() (totalSlots, occupiedSlots int) {
-	totalSlots = c.numShards * len(c.shards[0].slots)
+	totalSlots = c.numShards * len(c.shards[0].slots())
 	for shardID := range c.shards {
 		shard := &c.shards[shardID]
-		for slotID := range shard.slots {
-			if shard.slots[slotID].Load() != nil {
+		for slotID := range shard.slots() {
+			if shard.slots()[slotID].Load() != nil {
 				occupiedSlots++
 			}
 		}
@@ -200,8 +200,8 @@ func TestEvictionRateVsInsertionRate(t *testing.T) {
 	insertionRates := []int{100, 1000, 5000, 10000} // keys per second
 
 	for _, rate := range insertionRates {
-		// Reset counters
-		cache.evictions.Store(0)
+		// Count evictions from here
+		evictionsBefore := cache.evictions.Load()
 
 		entriesBefore := cache.countEntries()
 		duration := 2 * time.Second
@@ -229,7 +229,7 @@ func TestEvictionRateVsInsertionRate(t *testing.T) {
 		}
 
 		entriesAfter := cache.countEntries()
-		evictions := cache.evictions.Load()
+		evictions := cache.evictions.Load() - evictionsBefore
 
 		t.Logf("  Rate %d keys/sec:", rate)
 		t.Logf("    Inserted: %d, Rejected: %d", insertedCount, rejectedCount)
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"io"
+	"time"
+)
+
+// MergeEntry is one side of a key conflict during Merge
+type MergeEntry[V any] struct {
+	Value V
+	Freq  int32 // access frequency (1-15)
+}
+
+// MergeResolver decides a key conflict during Merge: it returns true to replace
+// the existing entry with the incoming one. Values that carry a version or
+// timestamp can be compared here.
+type MergeResolver[K Key, V any] func(key K, existing, incoming MergeEntry[V]) bool
+
+// PreferIncoming resolves conflicts in favor of the merged-in entry
+func PreferIncoming[K Key, V any](K, MergeEntry[V], MergeEntry[V]) bool { return true }
+
+// PreferExisting resolves conflicts in favor of the entry already in the cache,
+// e.g. when the receiving cache has been serving newer writes
+func PreferExisting[K Key, V any](K, MergeEntry[V], MergeEntry[V]) bool { return false }
+
+// PreferFrequent resolves conflicts in favor of the more frequently accessed
+// entry, keeping the existing one on ties
+func PreferFrequent[K Key, V any](_ K, existing, incoming MergeEntry[V]) bool {
+	return incoming.Freq > existing.Freq
+}
+
+// Merge imports the live entries of other, keeping their frequencies. Keys that
+// are live in both caches are resolved with resolve (nil = PreferIncoming); keys
+// only tracked as ghosts here are promoted as by Put.
+// Returns the number of entries taken from other.
+//
+// Merge is intended for handing warm contents from an old instance to a new one:
+// it reads other lock-free, so concurrent writes to either cache may or may not be
+// reflected. Hooks are not fired for merged entries.
+func (c *CloxCache[K, V]) Merge(other *CloxCache[K, V], resolve MergeResolver[K, V]) int {
+	if other == c || other.hashOnly || c.closed.Load() {
+		return 0
+	}
+	if resolve == nil {
+		resolve = PreferIncoming[K, V]
+	}
+
+	merged := 0
+	other.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		f := node.freq.Load()
+		if f <= 0 || other.stale(node) {
+			return true
+		}
+		vp := node.value.Load()
+		if vp == nil {
+			return true
+		}
+		if c.merge(node.fullKey(), *vp, f, resolve) {
+			merged++
+		}
+		return true
+	})
+	return merged
+}
+
+// MergeSnapshot imports a snapshot written by WriteSnapshot, resolving conflicts
+// with live entries like Merge. Entries taken keep the rest of their TTL, and
+// entries whose TTL passed are skipped. Returns the number of entries taken from
+// the snapshot.
+func (c *CloxCache[K, V]) MergeSnapshot(r io.Reader, resolve MergeResolver[K, V]) (int, error) {
+	if resolve == nil {
+		resolve = PreferIncoming[K, V]
+	}
+	merged, err := c.readSnapshot(r, func(key K, value V, freq int32, ttl time.Duration) bool {
+		if !c.merge(key, value, freq, resolve) {
+			return false
+		}
+		if ttl > 0 {
+			c.SetTTL(key, ttl)
+		}
+		return true
+	}, nil)
+	if err != nil {
+		c.logWarn("snapshot merge failed", "merged", merged, "error", err)
+	}
+	return merged, err
+}
+
+// merge stores an incoming entry, consulting resolve if key is already live.
+// Returns true if the incoming entry was stored.
+func (c *CloxCache[K, V]) merge(key K, value V, freq int32, resolve MergeResolver[K, V]) bool {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if c.oversized(key, value) {
+		shard.rejected(RejectOverWeight)
+		return false
+	}
+
+	shard.mu.Lock()
+	for node := c.slotIn(shard, hash).Load(); node != nil; node = node.next.Load() {
+		if !c.matches(node, hash, hi, key) {
+			continue
+		}
+		f := node.freq.Load()
+		vp := node.value.Load()
+		if f <= 0 || vp == nil || c.stale(node) {
+			break // ghost or invalidated: replaced through put
+		}
+
+		incoming := MergeEntry[V]{Value: value, Freq: freq}
+		existing := MergeEntry[V]{Value: *vp, Freq: f}
+		if !resolve(copyKey(key), existing, incoming) {
+			shard.mu.Unlock()
+			return false
+		}
+		size := c.weigh(key, value)
+		c.preserve(shard, hash)
+		from := node.value.Swap(&value)
+		c.valueChanged(key, from, &value)
+		c.finalize(shard, key, from)
+		shard.liveBytes.Add(size - node.size.Swap(size))
+		if node.freq.CompareAndSwap(f, freq) {
+			c.freqChanged(shard, f, freq)
+		}
+		node.lastAccess.Store(shard.timestamp.Add(1))
+		shard.mu.Unlock()
+		return true
+	}
+	shard.mu.Unlock()
+
+	return c.put(key, value, freq, nil)
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheMerge(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100}
+
+	old := NewCloxCache[string, int](cfg)
+	defer old.Close()
+	for i := range 20 {
+		old.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	old.Get("key-1")
+	old.Get("key-1")
+
+	tests := []struct {
+		name    string
+		resolve MergeResolver[string, int]
+		merged  int
+		key0    int // expected value of key-0
+		key1    int // expected value of key-1
+	}{
+		{"incoming", nil, 20, 0, 1},
+		{"existing", PreferExisting[string, int], 18, 100, 101},
+		{"frequent", PreferFrequent[string, int], 19, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCloxCache[string, int](cfg)
+			defer c.Close()
+			// key-0 and key-1 are live in both caches (freq 1 here)
+			c.Put("key-0", 100)
+			c.Put("key-1", 101)
+
+			if merged := c.Merge(old, tt.resolve); merged != tt.merged {
+				t.Errorf("Expected %d merged entries, got %d", tt.merged, merged)
+			}
+			if v, _ := c.Get("key-0"); v != tt.key0 {
+				t.Errorf("key-0 = %d, want %d", v, tt.key0)
+			}
+			if v, _ := c.Get("key-1"); v != tt.key1 {
+				t.Errorf("key-1 = %d, want %d", v, tt.key1)
+			}
+			if v, ok := c.Get("key-19"); !ok || v != 19 {
+				t.Errorf("key-19 = %d, %v; want 19", v, ok)
+			}
+			if report := c.VerifyIntegrity(); !report.OK() {
+				t.Errorf("Integrity issues after merge: %v", report.Issues)
+			}
+		})
+	}
+
+	if merged := old.Merge(old, nil); merged != 0 {
+		t.Errorf("Merging a cache into itself should be a no-op, got %d", merged)
+	}
+}
+
+func TestCloxCacheMergeKeepsFrequency(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 32}
+	old := NewCloxCache[string, int](cfg)
+	defer old.Close()
+	old.Put("hot", 1)
+	for range 5 {
+		old.Get("hot")
+	}
+
+	c := NewCloxCache[string, int](cfg)
+	defer c.Close()
+	c.Put("hot", 0)
+	c.Merge(old, func(key string, existing, incoming MergeEntry[int]) bool {
+		if key != "hot" || existing.Freq != 1 || incoming.Freq != 6 {
+			t.Errorf("Unexpected conflict %q: existing=%+v incoming=%+v", key, existing, incoming)
+		}
+		return true
+	})
+
+	if f := liveFreq(c, "hot"); f != 6 {
+		t.Errorf("Expected merged frequency 6, got %d", f)
+	}
+}
+
+func TestCloxCacheMergeSnapshot(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100}
+	src := NewCloxCache[string, string](cfg)
+	defer src.Close()
+	src.Put("a", "old-a")
+	src.Put("b", "old-b")
+
+	var buf bytes.Buffer
+	if err := src.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+
+	dst := NewCloxCache[string, string](cfg)
+	defer dst.Close()
+	dst.Put("a", "new-a")
+
+	merged, err := dst.MergeSnapshot(&buf, PreferExisting[string, string])
+	if err != nil {
+		t.Fatalf("MergeSnapshot failed: %v", err)
+	}
+	if merged != 1 {
+		t.Errorf("Expected 1 merged entry, got %d", merged)
+	}
+	if v, _ := dst.Get("a"); v != "new-a" {
+		t.Errorf("a = %q, want new-a", v)
+	}
+	if v, _ := dst.Get("b"); v != "old-b" {
+		t.Errorf("b = %q, want old-b", v)
+	}
+
+	if _, err := dst.MergeSnapshot(bytes.NewReader([]byte("junk")), nil); err == nil {
+		t.Error("Expected an error for an invalid snapshot")
+	}
+}
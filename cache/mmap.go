@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"sync"
+)
+
+// ErrMmapUnsupported is returned by NewMmapStore on platforms without mmap
+var ErrMmapUnsupported = errors.New("cache: memory-mapped files are not supported on this platform")
+
+// Memory-mapped store layout (little-endian):
+//
+//	header: "CLXM" version(4 bytes) ringSize(8) head(8) tail(8), padded to 64 bytes
+//	record: keyLen(4) valueLen(4) crc(4) flags(4) key value
+//
+// The rest of the file is a ring of records addressed by logical offsets that
+// only grow (the position in the ring is the offset modulo its size); records
+// from head to tail are in use. crc is the CRC-32C of key and value, and flags
+// marks records whose value was loaded, deleted or stored again (mmapRecordDead),
+// which keeps them from coming back when a shared segment is attached.
+const (
+	mmapMagic      = "CLXM"
+	mmapVersion    = 1
+	mmapHeaderSize = 64
+
+	mmapRecordHeader = 16
+	mmapRecordDead   = 1 << 0
+)
+
+// MmapStore is an OverflowStore that keeps values in a memory-mapped file, so
+// the OS page cache holds cold data and the Go heap only holds an index of
+// offsets by key. Used with WithOverflow, the cache keeps its hot working set
+// decoded in memory under the usual eviction policy, and the values it evicts
+// stay reachable at the cost of a decode, which allows caches far larger than
+// RAM comfortably allows.
+//
+// The file is a ring of records written in order: once it is full, the oldest
+// records are overwritten and their keys dropped from the index, so the store
+// itself evicts FIFO. Values are encoded with a Codec. A store from NewMmapStore
+// is scratch space, truncated when it is created; one from OpenSharedStore
+// outlives the process.
+type MmapStore[K Key, V any] struct {
+	mu     sync.Mutex
+	codec  Codec[V]
+	file   *os.File
+	data   []byte            // the mapping
+	ring   []byte            // data past the header
+	index  map[string]uint64 // key -> logical offset of its record
+	head   uint64            // logical offset of the oldest record
+	tail   uint64            // logical offset the next record is written at
+	buf    []byte            // encoding scratch
+	shared bool
+	attach MmapAttachReport
+}
+
+// NewMmapStore creates (or truncates) the file at path, size bytes long, and maps
+// it. codec encodes the values; nil picks the cache's default (raw bytes for
+// []byte and string values, gob for everything else). Call Close once the cache
+// using it is closed.
+func NewMmapStore[K Key, V any](path string, size int64, codec Codec[V]) (*MmapStore[K, V], error) {
+	return openMmapStore[K](path, size, codec, false)
+}
+
+// openMmapStore maps the file at path, attaching to the records it holds if
+// shared, and starting empty otherwise
+func openMmapStore[K Key, V any](path string, size int64, codec Codec[V], shared bool) (*MmapStore[K, V], error) {
+	if size <= mmapHeaderSize+mmapRecordHeader || int64(int(size)) != size {
+		return nil, fmt.Errorf("cache: invalid mmap store size %d", size)
+	}
+	if codec == nil {
+		codec = defaultCodec[V]()
+	}
+	flag := os.O_RDWR | os.O_CREATE
+	if !shared {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flag, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*MmapStore[K, V], error) {
+		f.Close()
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		return fail(fmt.Errorf("cache: %s is in use by another process: %w", path, err))
+	}
+	if err := f.Truncate(size); err != nil {
+		return fail(err)
+	}
+	data, err := mapFile(f, int(size))
+	if err != nil {
+		return fail(err)
+	}
+
+	m := &MmapStore[K, V]{
+		codec:  codec,
+		file:   f,
+		data:   data,
+		ring:   data[mmapHeaderSize:],
+		index:  make(map[string]uint64),
+		shared: shared,
+	}
+	if shared {
+		m.attachRecords()
+	} else {
+		m.reset()
+	}
+	return m, nil
+}
+
+// Store writes value at the end of the ring, overwriting the oldest records if
+// there is no room. Values that don't encode or don't fit in the file are
+// dropped.
+func (m *MmapStore[K, V]) Store(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		return
+	}
+	m.kill(string(key)) // don't serve the older value, even if this one is dropped
+	var err error
+	if m.buf, err = m.codec.Encode(m.buf[:0], value); err != nil {
+		return
+	}
+	n := uint64(mmapRecordHeader + len(key) + len(m.buf))
+	size := uint64(len(m.ring))
+	if n > size || uint64(len(key)) > math.MaxUint32 || uint64(len(m.buf)) > math.MaxUint32 {
+		return
+	}
+	for size-(m.tail-m.head) < n {
+		m.dropOldest()
+	}
+
+	crc := crc32.Update(crc32.Checksum([]byte(key), snapshotCRC), snapshotCRC, m.buf)
+	var header [mmapRecordHeader]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(m.buf)))
+	binary.LittleEndian.PutUint32(header[8:], crc)
+	at := m.write(m.tail, header[:])
+	at = m.write(at, []byte(key))
+	m.write(at, m.buf)
+	// The record is complete before the header says it is in use
+	m.index[string(key)] = m.tail
+	m.setTail(m.tail + n)
+}
+
+// Load returns the value stored for key and drops it from the store
+func (m *MmapStore[K, V]) Load(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var zero V
+	at, ok := m.index[string(key)]
+	if !ok {
+		return zero, false
+	}
+	m.kill(string(key))
+
+	keyLen, valueLen, _, _ := m.recordHeader(at)
+	// Decode from a copy: codecs may keep the bytes, and the ring reuses them
+	encoded := make([]byte, valueLen)
+	m.read(at+mmapRecordHeader+keyLen, encoded)
+	value, err := m.codec.Decode(encoded)
+	if err != nil {
+		return zero, false
+	}
+	return value, true
+}
+
+// Delete drops key from the store
+func (m *MmapStore[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.kill(string(key))
+}
+
+// Len returns the number of values the store holds
+func (m *MmapStore[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.index)
+}
+
+// Close unmaps and closes the file. The store holds nothing afterwards.
+func (m *MmapStore[K, V]) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		return nil
+	}
+	err := errors.Join(unmapFile(m.data), m.file.Close())
+	m.data, m.ring, m.index = nil, nil, make(map[string]uint64)
+	return err
+}
+
+// kill drops key from the index and marks its record dead
+func (m *MmapStore[K, V]) kill(key string) {
+	at, ok := m.index[key]
+	if !ok {
+		return
+	}
+	delete(m.index, key)
+	var flags [4]byte
+	binary.LittleEndian.PutUint32(flags[:], mmapRecordDead)
+	m.write(at+12, flags[:])
+}
+
+// dropOldest frees the oldest record, removing its key from the index unless the
+// key was stored again since
+func (m *MmapStore[K, V]) dropOldest() {
+	keyLen, valueLen, _, _ := m.recordHeader(m.head)
+	key := make([]byte, keyLen)
+	m.read(m.head+mmapRecordHeader, key)
+	if at, ok := m.index[string(key)]; ok && at == m.head {
+		delete(m.index, string(key))
+	}
+	// The record is released before it can be overwritten
+	m.setHead(m.head + mmapRecordHeader + keyLen + valueLen)
+}
+
+// recordHeader reads the header of the record at logical offset at
+func (m *MmapStore[K, V]) recordHeader(at uint64) (keyLen, valueLen uint64, crc, flags uint32) {
+	var header [mmapRecordHeader]byte
+	m.read(at, header[:])
+	keyLen = uint64(binary.LittleEndian.Uint32(header[:]))
+	valueLen = uint64(binary.LittleEndian.Uint32(header[4:]))
+	return keyLen, valueLen, binary.LittleEndian.Uint32(header[8:]), binary.LittleEndian.Uint32(header[12:])
+}
+
+// write copies b into the ring at logical offset at, wrapping at the end of the
+// file, and returns the offset after it
+func (m *MmapStore[K, V]) write(at uint64, b []byte) uint64 {
+	pos := at % uint64(len(m.ring))
+	n := copy(m.ring[pos:], b)
+	copy(m.ring, b[n:])
+	return at + uint64(len(b))
+}
+
+// read copies len(b) bytes of the ring at logical offset at into b
+func (m *MmapStore[K, V]) read(at uint64, b []byte) {
+	pos := at % uint64(len(m.ring))
+	n := copy(b, m.ring[pos:])
+	copy(b[n:], m.ring)
+}
+
+// reset writes a header for an empty ring
+func (m *MmapStore[K, V]) reset() {
+	clear(m.data[:mmapHeaderSize])
+	copy(m.data, mmapMagic)
+	binary.LittleEndian.PutUint32(m.data[4:], mmapVersion)
+	binary.LittleEndian.PutUint64(m.data[8:], uint64(len(m.ring)))
+	m.setHead(0)
+	m.setTail(0)
+}
+
+func (m *MmapStore[K, V]) setHead(head uint64) {
+	m.head = head
+	binary.LittleEndian.PutUint64(m.data[16:], head)
+}
+
+func (m *MmapStore[K, V]) setTail(tail uint64) {
+	m.tail = tail
+	binary.LittleEndian.PutUint64(m.data[24:], tail)
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package cache
+
+import "os"
+
+// mapFile reports that mmap is unavailable
+func mapFile(*os.File, int) ([]byte, error) {
+	return nil, ErrMmapUnsupported
+}
+
+// unmapFile is never reached: nothing can be mapped
+func unmapFile([]byte) error {
+	return nil
+}
+
+// lockFile is a no-op: the file is never mapped
+func lockFile(*os.File) error {
+	return nil
+}
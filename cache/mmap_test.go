@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestMmapStore(t *testing.T, size int64) *MmapStore[string, string] {
+	store, err := NewMmapStore[string, string](filepath.Join(t.TempDir(), "values"), size, nil)
+	if errors.Is(err, ErrMmapUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("NewMmapStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestMmapStore(t *testing.T) {
+	store := newTestMmapStore(t, mmapHeaderSize+100)
+
+	store.Store("a", "alpha")
+	store.Store("b", "beta")
+	store.Store("a", "again") // the newer record wins
+	if v, ok := store.Load("a"); !ok || v != "again" {
+		t.Errorf("Load(a) = %q, %v; want again", v, ok)
+	}
+	if _, ok := store.Load("a"); ok {
+		t.Error("Load didn't remove the value")
+	}
+	store.Delete("b")
+	if _, ok := store.Load("b"); ok {
+		t.Error("Deleted value was loaded")
+	}
+
+	// Records wrap around the end of the file, overwriting the oldest ones
+	for i := range 20 {
+		store.Store(fmt.Sprintf("k%d", i), fmt.Sprintf("value-%d", i))
+	}
+	if _, ok := store.Load("k0"); ok {
+		t.Error("The oldest value survived the ring wrapping")
+	}
+	for i := 17; i < 20; i++ {
+		key := fmt.Sprintf("k%d", i)
+		if v, ok := store.Load(key); !ok || v != fmt.Sprintf("value-%d", i) {
+			t.Errorf("Load(%s) = %q, %v", key, v, ok)
+		}
+	}
+
+	// A value larger than the file is dropped along with the older one
+	store.Store("big", "small")
+	store.Store("big", strings.Repeat("x", 200))
+	if _, ok := store.Load("big"); ok {
+		t.Error("An oversized value was stored")
+	}
+
+	store.Close()
+	store.Store("late", "value")
+	if n := store.Len(); n != 0 {
+		t.Errorf("Len() = %d after Close, want 0", n)
+	}
+}
+
+func TestCloxCacheMmapOverflow(t *testing.T) {
+	store := newTestMmapStore(t, 1<<20)
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+	cache := NewCloxCache(cfg, WithOverflow[string, string](store))
+	defer cache.Close()
+
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("key-%d", i), strings.Repeat("v", i))
+	}
+	if store.Len() == 0 {
+		t.Fatal("Nothing was demoted to the mmap store")
+	}
+	// Evicted values are served from the file
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		if v, ok := cache.Get(key); !ok || v != strings.Repeat("v", i) {
+			t.Fatalf("Get(%s) = %q, %v", key, v, ok)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package cache
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the first size bytes of f for reading and writing, shared with
+// the file
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// unmapFile releases a mapping made by mapFile
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+// lockFile takes an exclusive advisory lock on f, failing if another process
+// holds it. The lock is released when f is closed.
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
@@ -0,0 +1,13 @@
+package cache
+
+// Option configures a CloxCache at construction time.
+// Options carry settings that depend on the key or value type, or hold callbacks,
+// and so can't live in the plain-data Config.
+type Option[K Key, V any] func(*CloxCache[K, V])
+
+// WithCodec sets the codec used to serialize values in snapshots
+func WithCodec[K Key, V any](codec Codec[V]) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.codec = codec
+	}
+}
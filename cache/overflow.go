@@ -0,0 +1,118 @@
+package cache
+
+// OverflowStore is a secondary tier for values evicted from the cache, such as a
+// disk, mmap or remote store. With WithOverflow, evictions become demotions: the
+// evicted value is handed to Store, and a Get that misses the cache checks Load
+// before reporting a miss, promoting what it finds back into the cache.
+//
+// Stores that queue writes may also implement Flush(ctx context.Context) error:
+// Close calls it so queued demotions are persisted before it returns.
+type OverflowStore[K Key, V any] interface {
+	// Store receives an entry evicted to make room. It runs while the shard lock
+	// is held, so it must be fast (queue slow writes) and must not call back
+	// into the cache.
+	Store(key K, value V)
+	// Load returns and removes the value stored for key; a found value moves back
+	// into the cache, so the store need not keep it
+	Load(key K) (V, bool)
+	// Delete drops key after it was deleted or expired in the cache, so a stale
+	// value can't come back. Runs under the shard lock like Store.
+	Delete(key K)
+}
+
+// WithOverflow demotes evicted values to store and checks it on Get misses.
+// Entries removed by Delete, DeleteFunc or ExpireFunc are dropped from the store
+// rather than demoted.
+func WithOverflow[K Key, V any](store OverflowStore[K, V]) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.overflow = store
+	}
+}
+
+// A shard records at most maxOverflowGensPerEntry demotion generations per entry
+// of its capacity, and at least minOverflowGens. Overflow stores usually hold many
+// times what the cache does; forgetting a record only costs a miss.
+const (
+	maxOverflowGensPerEntry = 64
+	minOverflowGens         = 4096
+)
+
+// demote hands an evicted value stored in generation gen to the overflow store,
+// if any, and finalizes it otherwise. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) demote(shard *shard[K, V], key K, hash, gen uint64, vp *V) {
+	if c.overflow != nil && vp != nil && !c.hashOnly {
+		c.overflow.Store(key, *vp)
+		c.demotions.Add(1)
+		c.demotedGen(shard, hash, gen)
+		return
+	}
+	c.finalize(shard, key, vp)
+}
+
+// demotedGen records the generation of a value demoted to the overflow store, so
+// promote can tell whether it was invalidated since. Values from generation 0 (no
+// invalidation yet, or demoted by an earlier process into a retaining store) need
+// no record: an unrecorded value counts as generation 0. Once the map outgrows
+// its bound, records below the floor are dropped, then all of them; the values
+// they covered miss. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) demotedGen(shard *shard[K, V], hash, gen uint64) {
+	if gen == 0 {
+		if shard.overflowGens != nil {
+			delete(shard.overflowGens, hash)
+		}
+		return
+	}
+	if shard.overflowGens == nil {
+		shard.overflowGens = make(map[uint64]uint64)
+	}
+	if limit := max(shard.capacity*maxOverflowGensPerEntry, minOverflowGens); int64(len(shard.overflowGens)) >= limit {
+		floor := c.validFloor()
+		for h, g := range shard.overflowGens {
+			if g < floor {
+				delete(shard.overflowGens, h)
+			}
+		}
+		if int64(len(shard.overflowGens)) >= limit {
+			clear(shard.overflowGens)
+		}
+	}
+	shard.overflowGens[hash] = gen
+}
+
+// dropOverflow removes key from the overflow store, if any
+func (c *CloxCache[K, V]) dropOverflow(key K) {
+	if c.overflow != nil {
+		c.overflow.Delete(key)
+	}
+}
+
+// promote looks key up in the overflow store after a cache miss and moves a found
+// value back into the cache. A value demoted before the generation floor was
+// invalidated with the rest of its generation: it is finalized and reported as a
+// miss, like the live entries InvalidateAll dropped.
+func (c *CloxCache[K, V]) promote(key K, hash uint64) (V, bool) {
+	v, ok := c.overflow.Load(key)
+	if !ok {
+		return v, false
+	}
+	shard, _ := c.locate(hash)
+	shard.mu.Lock()
+	gen := shard.overflowGens[hash]
+	delete(shard.overflowGens, hash)
+	shard.mu.Unlock()
+	if gen < c.validFloor() {
+		c.finalize(shard, key, &v)
+		var zero V
+		return zero, false
+	}
+	c.promotions.Add(1)
+	c.put(key, v, initialFreq, nil)
+	return v, true
+}
+
+// OverflowStats returns how many values were demoted to the overflow store and
+// how many Get misses were served from it (both 0 without WithOverflow).
+// Misses served from the overflow store still count as misses in Stats.
+func (c *CloxCache[K, V]) OverflowStats() (demoted, promoted uint64) {
+	return c.demotions.Load(), c.promotions.Load()
+}
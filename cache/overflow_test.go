@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+// mapOverflow is an in-memory OverflowStore for tests
+type mapOverflow struct {
+	mu      sync.Mutex
+	entries map[string]int
+}
+
+func (m *mapOverflow) Store(key string, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = value
+}
+
+func (m *mapOverflow) Load(key string) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.entries[key]
+	delete(m.entries, key)
+	return v, ok
+}
+
+func (m *mapOverflow) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+func TestCloxCacheOverflow(t *testing.T) {
+	store := &mapOverflow{entries: make(map[string]int)}
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 2, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg, WithOverflow[string, int](store))
+	defer cache.Close()
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3) // demotes a
+
+	if _, ok := store.entries["a"]; !ok {
+		t.Fatal("Expected the evicted entry to be demoted to the overflow store")
+	}
+
+	// A miss is served from the overflow store and promoted back into the cache
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %d, %v, want 1 from the overflow store", v, ok)
+	}
+	if _, ok := cache.peek("a"); !ok {
+		t.Error("Expected the overflow hit to be promoted into the cache")
+	}
+	demoted, promoted := cache.OverflowStats()
+	if demoted < 1 || promoted != 1 {
+		t.Errorf("OverflowStats = %d demoted, %d promoted", demoted, promoted)
+	}
+
+	// Deleted keys are dropped from the overflow store, not resurrected
+	cache.Put("d", 4)
+	for key := range store.entries {
+		cache.Delete(key)
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("Deleted key %q came back from the overflow store", key)
+		}
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected a miss for a key in neither tier")
+	}
+}
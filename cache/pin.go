@@ -0,0 +1,106 @@
+package cache
+
+// Reader pins.
+//
+// A finalizer (WithFinalizer, WithAutoClose) must not release a value a reader is
+// still using. Readers that need that guarantee (GetFunc, GetLease) pin the value: finalizing a pinned
+// value is deferred until its last pin is dropped, and the reader that drops it
+// runs the finalizer. A reader pins the value it loaded and then checks the node
+// still holds it; the value is swapped out of its node before it is finalized, so
+// either the check fails and the reader retries, or the pin is in place before
+// the finalizer looks. Pins are tracked per shard; GetFunc only pins when
+// finalization is enabled.
+
+// GetFunc calls fn with the value for key and returns true, or returns false if
+// key is missing. Unlike a value returned by Get, the value passed to fn is not
+// finalized or closed (see WithFinalizer, WithAutoClose) until fn returns, even if
+// it is evicted, replaced or deleted meanwhile. Counts as an access like Get.
+// fn may use the cache.
+func (c *CloxCache[K, V]) GetFunc(key K, fn func(value V)) bool {
+	v, ok := c.Get(key)
+	if !ok {
+		return false
+	}
+	if !c.finalizes() {
+		fn(v)
+		return true
+	}
+	shard, vp, ok := c.acquire(key)
+	if !ok {
+		return false // removed since the Get
+	}
+	defer c.release(shard, key, vp)
+	fn(*vp)
+	return true
+}
+
+// finalizes reports whether values leaving the cache are finalized
+func (c *CloxCache[K, V]) finalizes() bool {
+	return c.finalizer != nil || c.autoClose
+}
+
+// acquire pins the live value of key without recording an access.
+// The caller must release it.
+func (c *CloxCache[K, V]) acquire(key K) (*shard[K, V], *V, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	for {
+		var vp *V
+		node := c.findNode(shard, hash, hi, key, c.liveNode)
+		if node != nil {
+			vp = node.value.Load()
+		}
+		if vp == nil {
+			return nil, nil, false
+		}
+
+		shard.pin(vp)
+		if node.value.Load() == vp {
+			return shard, vp, true
+		}
+		// Replaced or removed before the pin took: it may already be finalized
+		c.release(shard, key, vp)
+	}
+}
+
+// release drops a pin taken by acquire, running a finalizer deferred by it
+func (c *CloxCache[K, V]) release(shard *shard[K, V], key K, vp *V) {
+	shard.pinMu.Lock()
+	n := shard.pins[vp] - 1
+	if n > 0 {
+		shard.pins[vp] = n
+		shard.pinMu.Unlock()
+		return
+	}
+	delete(shard.pins, vp)
+	_, deferred := shard.deferred[vp]
+	delete(shard.deferred, vp)
+	shard.pinMu.Unlock()
+
+	if deferred {
+		c.runFinalizer(key, vp)
+	}
+}
+
+// pin adds a reader pin to vp
+func (s *shard[K, V]) pin(vp *V) {
+	s.pinMu.Lock()
+	if s.pins == nil {
+		s.pins = make(map[*V]int32)
+		s.deferred = make(map[*V]struct{})
+	}
+	s.pins[vp]++
+	s.pinMu.Unlock()
+}
+
+// deferFinalizer reports whether vp is pinned, in which case its finalizer runs
+// when the last pin is released
+func (s *shard[K, V]) deferFinalizer(vp *V) bool {
+	s.pinMu.Lock()
+	defer s.pinMu.Unlock()
+	if s.pins[vp] == 0 {
+		return false
+	}
+	s.deferred[vp] = struct{}{}
+	return true
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// resource fails the test if it is used after being closed
+type resource struct {
+	closed atomic.Bool
+}
+
+func TestCloxCacheGetFuncPinsConcurrently(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 8}
+	var closes atomic.Int64
+	cache := NewCloxCache(cfg, WithFinalizer(func(_ string, r *resource) {
+		if r.closed.Swap(true) {
+			t.Error("Value finalized twice")
+		}
+		closes.Add(1)
+	}))
+
+	var wg sync.WaitGroup
+	var puts atomic.Int64
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				key := fmt.Sprintf("key-%d", (i+w)%16)
+				if cache.Put(key, &resource{}) {
+					puts.Add(1)
+				}
+				cache.GetFunc(key, func(r *resource) {
+					if r.closed.Load() {
+						t.Error("GetFunc passed a finalized value")
+					}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	cache.Close()
+
+	if n := closes.Load(); n != puts.Load() {
+		t.Errorf("Finalized %d values, want one per stored value (%d)", n, puts.Load())
+	}
+	for i := range cache.shards {
+		if len(cache.shards[i].pins) != 0 || len(cache.shards[i].deferred) != 0 {
+			t.Errorf("Shard %d kept pins after all readers finished", i)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Policy selects how a full shard chooses its eviction victims
+type Policy uint8
+
+const (
+	// PolicyProtectedFreq is the default: entries whose frequency exceeds the shard's
+	// adaptive threshold k are protected, and the least recently used of the rest is
+	// evicted (cheapest miss cost first, see PutWithCost)
+	PolicyProtectedFreq Policy = iota
+
+	// PolicyGDSF is GreedyDual-Size-Frequency: each entry is valued at
+	// L + freq*cost/size, where L is a per-shard clock that rises to the value of
+	// every evicted entry, and the lowest-valued entry in the scan window is evicted.
+	// Many small objects are kept over a few large ones unless the large ones are
+	// accessed or cost proportionally more. Sizes come from the weigher (see
+	// WithWeigher) and costs from PutWithCost (uncosted entries count as 1µs).
+	PolicyGDSF
+
+	// PolicyLIRS evicts by inter-reference recency (IRR), the gap between an entry's
+	// last two accesses, in the spirit of LIRS: entries with low IRR form a protected
+	// LIR set holding most of the shard, and the least recently used of the remaining
+	// HIR entries is evicted (see lirs.go). Frequency, priority and cost are ignored.
+	// A loop over more keys than the cache holds keeps a stable subset resident where
+	// LRU-like policies would miss on every access.
+	PolicyLIRS
+)
+
+// policyNames are the text forms of each Policy, indexed by value
+var policyNames = [...]string{
+	PolicyProtectedFreq: "protected-freq",
+	PolicyGDSF:          "gdsf",
+	PolicyLIRS:          "lirs",
+}
+
+// String returns the policy's name as accepted by ParsePolicy
+func (p Policy) String() string {
+	if int(p) < len(policyNames) {
+		return policyNames[p]
+	}
+	return fmt.Sprintf("Policy(%d)", p)
+}
+
+// ParsePolicy parses a policy name ("protected-freq", "gdsf" or "lirs", case-insensitive)
+func ParsePolicy(s string) (Policy, error) {
+	for p, name := range policyNames {
+		if strings.EqualFold(s, name) {
+			return Policy(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown eviction policy %q (want one of %s)", s, strings.Join(policyNames[:], ", "))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (p Policy) MarshalText() ([]byte, error) {
+	if int(p) >= len(policyNames) {
+		return nil, fmt.Errorf("unknown eviction policy %d", p)
+	}
+	return []byte(policyNames[p]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParsePolicy
+func (p *Policy) UnmarshalText(text []byte) error {
+	parsed, err := ParsePolicy(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// touchGDSF records the shard's GDSF clock as the base of node's value.
+// Called whenever an entry is inserted or accessed; a no-op for other policies.
+func (c *CloxCache[K, V]) touchGDSF(shard *shard[K, V], node *recordNode[K, V]) {
+	if c.config.Policy == PolicyGDSF {
+		node.gdsfBase.Store(shard.gdsfClock.Load())
+	}
+}
+
+// gdsfValue returns a live node's GreedyDual-Size-Frequency value for the
+// effective frequency freq (frequency plus priority)
+func gdsfValue[K Key, V any](node *recordNode[K, V], freq int32) float64 {
+	cost := float64(max(node.cost.Load()/1000, 1)) // microseconds
+	size := float64(max(node.size.Load(), 1))
+	return math.Float64frombits(node.gdsfBase.Load()) + float64(max(freq, 1))*cost/size
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloxCachePolicyGDSF(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, Policy: PolicyGDSF}
+	cache := NewCloxCache[string, string](cfg)
+	defer cache.Close()
+
+	cache.Put("a", "1")
+	cache.Put("b", "2")
+	cache.Put("c", "3")
+	cache.Put("big", strings.Repeat("x", 1000)) // most recent, but large
+	cache.Put("d", "4")
+
+	if _, ok := cache.Get("big"); ok {
+		t.Error("Expected the large entry to be evicted under GDSF")
+	}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Small entry %q was evicted", key)
+		}
+	}
+	if clock := math.Float64frombits(cache.shards[0].gdsfClock.Load()); clock <= 0 {
+		t.Errorf("Expected the GDSF clock to advance, got %v", clock)
+	}
+}
+
+func TestCloxCachePolicyGDSFCost(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100, Policy: PolicyGDSF}
+	cache := NewCloxCache[string, string](cfg)
+	defer cache.Close()
+
+	cache.Put("a", "1")
+	cache.Put("b", "2")
+	cache.Put("c", "3")
+	// An expensive large entry is worth more than a cheap small one
+	cache.PutWithCost("big", strings.Repeat("x", 1000), 10*time.Millisecond)
+	cache.Put("d", "4")
+
+	if _, ok := cache.Get("big"); !ok {
+		t.Error("Expensive large entry was evicted")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the oldest cheap entry to be evicted")
+	}
+}
+
+func TestCloxCachePolicyDefault(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, string](cfg)
+	defer cache.Close()
+
+	cache.Put("a", "1")
+	cache.Put("b", "2")
+	cache.Put("c", "3")
+	cache.Put("big", strings.Repeat("x", 1000))
+	cache.Put("d", "4")
+
+	// Size is ignored: the least recently used entry goes
+	if _, ok := cache.Get("big"); !ok {
+		t.Error("Large entry was evicted under the default policy")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the LRU entry to be evicted")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, p := range []Policy{PolicyProtectedFreq, PolicyGDSF, PolicyLIRS} {
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%d) failed: %v", p, err)
+		}
+		var parsed Policy
+		if err := parsed.UnmarshalText(text); err != nil || parsed != p {
+			t.Errorf("Round trip of %s gave %v, %v", p, parsed, err)
+		}
+	}
+	if p, err := ParsePolicy("GDSF"); err != nil || p != PolicyGDSF {
+		t.Errorf("ParsePolicy(GDSF) = %v, %v", p, err)
+	}
+	if _, err := ParsePolicy("lru"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+	if err := (Config{NumShards: 1, SlotsPerShard: 1, Policy: 99}).Validate(); err == nil {
+		t.Error("Expected Validate to reject an unknown policy")
+	}
+}
+
+func TestConfigPolicySources(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 1000, "policy": "gdsf"}`))
+	if err != nil || cfg.Policy != PolicyGDSF {
+		t.Errorf("ConfigFromJSON policy = %v, %v", cfg.Policy, err)
+	}
+	if _, err := ConfigFromJSON([]byte(`{"capacity": 1000, "policy": "lru"}`)); err == nil {
+		t.Error("Expected ConfigFromJSON to reject an unknown policy")
+	}
+
+	t.Setenv("CLOX_CAPACITY", "1000")
+	t.Setenv("CLOX_POLICY", "gdsf")
+	if cfg, err := ConfigFromEnv(""); err != nil || cfg.Policy != PolicyGDSF {
+		t.Errorf("ConfigFromEnv policy = %v, %v", cfg.Policy, err)
+	}
+
+	cfg = ConfigFromCapacity(1000)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-cache-policy", "gdsf"}); err != nil || cfg.Policy != PolicyGDSF {
+		t.Errorf("-cache-policy = %v, %v", cfg.Policy, err)
+	}
+}
+
+// loopHits replays a cyclic access pattern over n keys and returns the hit count
+func loopHits(policy Policy, n, passes int) int {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16, SweepPercent: 100, Policy: policy}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	hits := 0
+	for range passes {
+		for i := range n {
+			key := fmt.Sprintf("key-%d", i)
+			if _, ok := cache.Get(key); ok {
+				hits++
+			} else {
+				cache.Put(key, i)
+			}
+		}
+	}
+	return hits
+}
+
+func TestCloxCachePolicyLIRSLoop(t *testing.T) {
+	const n, passes = 40, 20
+	lru := loopHits(PolicyProtectedFreq, n, passes)
+	lirs := loopHits(PolicyLIRS, n, passes)
+	t.Logf("loop of %d keys over capacity 16: protected-freq %d hits, lirs %d hits", n, lru, lirs)
+	if lirs < (passes-1)*8 {
+		t.Errorf("Expected LIRS to keep part of the loop resident, got %d hits (protected-freq: %d)", lirs, lru)
+	}
+	if lirs <= lru {
+		t.Errorf("Expected LIRS (%d hits) to beat protected-freq (%d hits) on a loop", lirs, lru)
+	}
+}
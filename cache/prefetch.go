@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+)
+
+// Prefetch warms the cache for keys a request is about to read: it hashes them
+// in one pass and walks their chains shard by shard like GetMany, pulling the
+// slots and nodes into the CPU caches so the Gets that follow don't stall on
+// memory. No access is recorded. Returns the keys that are not live, in their
+// original order, so the caller can start loading them (see PrefetchLoad).
+// Returns nil once the cache is closed.
+func (c *CloxCache[K, V]) Prefetch(keys []K) (missing []K) {
+	if c.closed.Load() {
+		return nil
+	}
+	hashed := c.hashKeys(keys)
+	live := make([]bool, len(keys))
+	for _, i := range c.shardOrder(hashed) {
+		h := hashed[i]
+		shard, _ := c.locate(h.hash)
+		if node := c.findNode(shard, h.hash, h.hi, h.key, c.liveNode); node != nil {
+			live[i] = node.value.Load() != nil
+		}
+	}
+	for i, key := range keys {
+		if !live[i] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// PrefetchLoad is Prefetch that also fills the misses in the background, hiding
+// backend latency behind the work a request does before it needs the values.
+// load runs for each distinct missing key in its own goroutine and its result is
+// Put. The returned function waits for the loads and returns their errors joined
+// (failed keys are not cached, so their Gets just miss).
+func (c *CloxCache[K, V]) PrefetchLoad(keys []K, load func(key K) (V, error)) (wait func() error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	started := make(map[string]struct{})
+	for _, key := range c.Prefetch(keys) {
+		if _, dup := started[string(key)]; dup {
+			continue
+		}
+		started[string(key)] = struct{}{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := load(key)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				return
+			}
+			c.Put(key, value)
+		}()
+	}
+
+	return func() error {
+		wg.Wait()
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCloxCachePrefetch(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128})
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.Put("deleted", 0)
+	cache.Delete("deleted")
+
+	keys := []string{"key-3", "absent-1", "key-7", "deleted", "absent-2"}
+	missing := cache.Prefetch(keys)
+	if want := []string{"absent-1", "deleted", "absent-2"}; !slices.Equal(missing, want) {
+		t.Errorf("Prefetch missing = %v, want %v", missing, want)
+	}
+
+	// No access is recorded
+	hash, hi := cache.hashes("key-3")
+	shard, _ := cache.locate(hash)
+	if f := cache.findNode(shard, hash, hi, "key-3", nil).freq.Load(); f != initialFreq {
+		t.Errorf("Prefetch bumped freq to %d", f)
+	}
+
+	cache.Close()
+	if missing := cache.Prefetch(keys); missing != nil {
+		t.Errorf("Prefetch on a closed cache = %v, want nil", missing)
+	}
+}
+
+func TestCloxCachePrefetchLoad(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128})
+	defer cache.Close()
+
+	cache.Put("cached", 1)
+	errBackend := errors.New("backend down")
+	var loads atomic.Int32
+	wait := cache.PrefetchLoad([]string{"cached", "a", "b", "a", "broken"}, func(key string) (int, error) {
+		loads.Add(1)
+		if key == "broken" {
+			return 0, errBackend
+		}
+		return len(key) * 10, nil
+	})
+
+	if err := wait(); !errors.Is(err, errBackend) {
+		t.Errorf("wait() = %v, want the load error", err)
+	}
+	if n := loads.Load(); n != 3 {
+		t.Errorf("load ran %d times, want 3 (misses only, once per key)", n)
+	}
+	for key, want := range map[string]int{"cached": 1, "a": 10, "b": 10} {
+		if v, ok := cache.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %d, %v; want %d", key, v, ok, want)
+		}
+	}
+	if _, ok := cache.Get("broken"); ok {
+		t.Error("A failed load was cached")
+	}
+
+	if err := cache.PrefetchLoad([]string{"cached"}, nil)(); err != nil {
+		t.Errorf("Prefetching only cached keys returned %v", err)
+	}
+}
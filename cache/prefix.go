@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"iter"
+	"strings"
+	"sync"
+)
+
+// keyIndexMaxLevel bounds the skip list height (enough for ~2^24 keys at p=1/2)
+const keyIndexMaxLevel = 24
+
+// scanBatchSize is how many keys ScanPrefix copies out of the index per lock hold
+const scanBatchSize = 64
+
+// keyIndex is an ordered set of keys (a skip list) guarded by a single RWMutex
+type keyIndex struct {
+	mu    sync.RWMutex
+	head  keyIndexNode
+	level int
+	len   int
+	rng   uint64 // xorshift state for level selection, guarded by mu
+}
+
+type keyIndexNode struct {
+	key  string
+	next []*keyIndexNode
+}
+
+func newKeyIndex() *keyIndex {
+	return &keyIndex{
+		head:  keyIndexNode{next: make([]*keyIndexNode, keyIndexMaxLevel)},
+		level: 1,
+		rng:   0x9e3779b97f4a7c15,
+	}
+}
+
+// randomLevel picks a node height with P(level > n) = 2^-n. Caller must hold mu.
+func (ix *keyIndex) randomLevel() int {
+	ix.rng ^= ix.rng << 13
+	ix.rng ^= ix.rng >> 7
+	ix.rng ^= ix.rng << 17
+	level := 1
+	for r := ix.rng; r&1 == 1 && level < keyIndexMaxLevel; r >>= 1 {
+		level++
+	}
+	return level
+}
+
+// findPredecessors fills update with the last node before key on every level.
+// Caller must hold mu.
+func (ix *keyIndex) findPredecessors(key string, update *[keyIndexMaxLevel]*keyIndexNode) *keyIndexNode {
+	node := &ix.head
+	for l := ix.level - 1; l >= 0; l-- {
+		for next := node.next[l]; next != nil && next.key < key; next = node.next[l] {
+			node = next
+		}
+		update[l] = node
+	}
+	return node.next[0]
+}
+
+// insert adds key if it isn't present
+func (ix *keyIndex) insert(key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var update [keyIndexMaxLevel]*keyIndexNode
+	if next := ix.findPredecessors(key, &update); next != nil && next.key == key {
+		return
+	}
+
+	level := ix.randomLevel()
+	for l := ix.level; l < level; l++ {
+		update[l] = &ix.head
+	}
+	ix.level = max(ix.level, level)
+
+	node := &keyIndexNode{key: key, next: make([]*keyIndexNode, level)}
+	for l := range level {
+		node.next[l] = update[l].next[l]
+		update[l].next[l] = node
+	}
+	ix.len++
+}
+
+// remove deletes key if present
+func (ix *keyIndex) remove(key string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	var update [keyIndexMaxLevel]*keyIndexNode
+	node := ix.findPredecessors(key, &update)
+	if node == nil || node.key != key {
+		return
+	}
+	for l := range node.next {
+		update[l].next[l] = node.next[l]
+	}
+	for ix.level > 1 && ix.head.next[ix.level-1] == nil {
+		ix.level--
+	}
+	ix.len--
+}
+
+// contains reports whether key is in the index
+func (ix *keyIndex) contains(key string) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var update [keyIndexMaxLevel]*keyIndexNode
+	node := ix.findPredecessors(key, &update)
+	return node != nil && node.key == key
+}
+
+// after appends up to limit keys that start with prefix and sort after from
+// (or at from when inclusive), in order
+func (ix *keyIndex) after(dst []string, from string, inclusive bool, prefix string, limit int) []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var update [keyIndexMaxLevel]*keyIndexNode
+	node := ix.findPredecessors(from, &update)
+	if node != nil && node.key == from && !inclusive {
+		node = node.next[0]
+	}
+	for ; node != nil && len(dst) < limit; node = node.next[0] {
+		if !strings.HasPrefix(node.key, prefix) {
+			break
+		}
+		dst = append(dst, node.key)
+	}
+	return dst
+}
+
+// WithPrefixIndex maintains an ordered index of keys so ScanPrefix can enumerate
+// them. The index is updated under a single lock on every insert and removal of a
+// key, which adds contention to Puts that create new entries.
+func WithPrefixIndex[K Key, V any]() Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.prefixIndex = newKeyIndex()
+	}
+}
+
+// ScanPrefix returns an iterator over live entries whose key starts with prefix,
+// in ascending key order. Requires WithPrefixIndex; otherwise the sequence is empty.
+//
+// Entries are read without recording an access. The scan holds no locks while
+// yielding, so the loop body may use the cache; entries inserted or removed during
+// the scan may or may not be seen.
+func (c *CloxCache[K, V]) ScanPrefix(prefix K) iter.Seq2[K, V] {
+	p := string(prefix)
+	return func(yield func(K, V) bool) {
+		if c.prefixIndex == nil {
+			return
+		}
+		keys := make([]string, 0, scanBatchSize)
+		from, inclusive := p, true
+		for {
+			keys = c.prefixIndex.after(keys[:0], from, inclusive, p, scanBatchSize)
+			for _, key := range keys {
+				k := keyFromString[K](key)
+				if v, ok := c.peek(k); ok && !yield(k, v) {
+					return
+				}
+			}
+			if len(keys) < scanBatchSize {
+				return
+			}
+			from, inclusive = keys[len(keys)-1], false
+		}
+	}
+}
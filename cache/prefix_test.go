@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestKeyIndex(t *testing.T) {
+	ix := newKeyIndex()
+	want := make(map[string]bool)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for range 5000 {
+		key := fmt.Sprintf("k%04d", rng.IntN(1000))
+		if rng.IntN(3) == 0 {
+			ix.remove(key)
+			delete(want, key)
+		} else {
+			ix.insert(key)
+			want[key] = true
+		}
+	}
+
+	if ix.len != len(want) {
+		t.Fatalf("Expected %d keys, index has %d", len(want), ix.len)
+	}
+	sorted := make([]string, 0, len(want))
+	for k := range want {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	got := ix.after(nil, "", true, "", len(want)+1)
+	if !slices.Equal(got, sorted) {
+		t.Fatalf("Index order mismatch: got %d keys, want %d", len(got), len(sorted))
+	}
+	for _, k := range sorted {
+		if !ix.contains(k) {
+			t.Errorf("Index is missing %q", k)
+		}
+	}
+}
+
+func TestCloxCacheScanPrefix(t *testing.T) {
+	cache := NewCloxCache(Config{NumShards: 4, SlotsPerShard: 256, Capacity: 1000},
+		WithPrefixIndex[string, int]())
+	defer cache.Close()
+
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("user:%03d", i), i)
+		cache.Put(fmt.Sprintf("order:%03d", i), -i)
+	}
+	cache.Delete("user:100")
+
+	var keys []string
+	for k, v := range cache.ScanPrefix("user:1") {
+		if k != fmt.Sprintf("user:%03d", v) {
+			t.Errorf("Key %q has value %d", k, v)
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) != 99 {
+		t.Errorf("Expected 99 keys with prefix user:1, got %d", len(keys))
+	}
+	if !slices.IsSorted(keys) {
+		t.Error("ScanPrefix keys are not in order")
+	}
+	if slices.Contains(keys, "user:100") {
+		t.Error("Deleted key was returned")
+	}
+
+	// Early termination, with a Put from inside the loop
+	n := 0
+	for range cache.ScanPrefix("order:") {
+		cache.Put("order:new", 0)
+		if n++; n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected to stop after 10 entries, got %d", n)
+	}
+
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheScanPrefixEviction(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16, SweepPercent: 100}
+	cache := NewCloxCache(cfg, WithPrefixIndex[[]byte, int]())
+	defer cache.Close()
+
+	for i := range 500 {
+		cache.Put([]byte(fmt.Sprintf("k%03d", i)), i)
+	}
+
+	count := 0
+	for k, v := range cache.ScanPrefix([]byte("k")) {
+		if string(k) != fmt.Sprintf("k%03d", v) {
+			t.Errorf("Key %q has value %d", k, v)
+		}
+		count++
+	}
+	if live := int(cache.shards[0].entryCount.Load()); count != live {
+		t.Errorf("Expected %d live entries from scan, got %d", live, count)
+	}
+	// The index tracks exactly the nodes in the chains (live and ghost)
+	ghosts, _ := cache.GhostCount()
+	if want := cache.shards[0].entryCount.Load() + ghosts; int64(cache.prefixIndex.len) != want {
+		t.Errorf("Index holds %d keys, chains hold %d", cache.prefixIndex.len, want)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheScanPrefixDisabled(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+	cache.Put("a", 1)
+	for range cache.ScanPrefix("") {
+		t.Fatal("ScanPrefix should be empty without WithPrefixIndex")
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// Memory use as the Go memory limit counts it: everything the runtime has mapped,
+// minus heap pages it returned to the OS
+const (
+	memoryTotalMetric    = "/memory/classes/total:bytes"
+	memoryReleasedMetric = "/memory/classes/heap/released:bytes"
+)
+
+// WithMemoryPressure drops the soft entries (see PutSoft) whenever the process
+// is under memory pressure: every interval, a background goroutine compares the
+// memory the runtime holds with the Go memory limit (GOMEMLIMIT or
+// debug.SetMemoryLimit) and calls ReleaseSoft once it reaches ratio of the limit
+// (e.g. 0.9). Each release scans every shard, so while pressure lasts the cost
+// recurs every interval. Without a memory limit nothing is released.
+func WithMemoryPressure[K Key, V any](interval time.Duration, ratio float64) Option[K, V] {
+	if interval <= 0 {
+		panic("cache: memory pressure interval must be positive")
+	}
+	if ratio <= 0 || ratio > 1 {
+		panic("cache: memory pressure ratio must be in (0, 1]")
+	}
+	return func(c *CloxCache[K, V]) {
+		c.pressureEvery = interval
+		c.pressureRatio = ratio
+	}
+}
+
+// runPressureWatch starts the goroutine that releases soft entries under memory
+// pressure until Close. Caller must hold the lifecycle lock (or be New).
+func (c *CloxCache[K, V]) runPressureWatch() {
+	stop := c.stop
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.pressureEvery)
+		defer ticker.Stop()
+		samples := []metrics.Sample{{Name: memoryTotalMetric}, {Name: memoryReleasedMetric}}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			// Read the limit every time: it can be changed at run time
+			limit := debug.SetMemoryLimit(-1)
+			if limit == math.MaxInt64 {
+				continue
+			}
+			metrics.Read(samples)
+			used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+			if float64(used) >= c.pressureRatio*float64(limit) {
+				c.ReleaseSoft()
+			}
+		}
+	}()
+}
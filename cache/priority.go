@@ -0,0 +1,43 @@
+package cache
+
+// PutWithPriority stores value like Put and sets the entry's eviction priority.
+// Priority biases victim selection independently of observed frequency: the entry
+// is judged as if its frequency were freq+prio, so a positive priority keeps
+// business-critical entries protected even when they are accessed less often,
+// and a negative one makes bulk entries preferred victims. When every candidate is
+// protected, lower priorities are still evicted first. prio is clamped to
+// [-15, 15]; 0 is the default for entries stored with Put.
+//
+// The priority sticks to the key until it is removed from the cache (it survives
+// plain Puts and ghosting).
+func (c *CloxCache[K, V]) PutWithPriority(key K, value V, prio int) bool {
+	if !c.Put(key, value) {
+		return false
+	}
+	c.SetPriority(key, prio)
+	return true
+}
+
+// SetPriority changes the eviction priority of a live or ghost key (see
+// PutWithPriority). Returns false if the key is not in the cache.
+func (c *CloxCache[K, V]) SetPriority(key K, prio int) bool {
+	prio = max(min(prio, maxFrequency), -maxFrequency)
+
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		node.priority.Store(int32(prio))
+		return true
+	}
+	return false
+}
+
+// Priority returns the eviction priority of a live or ghost key
+func (c *CloxCache[K, V]) Priority(key K) (int, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		return int(node.priority.Load()), true
+	}
+	return 0, false
+}
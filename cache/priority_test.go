@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCachePutWithPriority(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	cache.PutWithPriority("paid-tenant", 1, 5)
+	cache.Put("free-tenant", 2)
+
+	// Flood with one-hit entries; without priority both would be evicted
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("bulk-%d", i), i)
+	}
+
+	if _, ok := cache.Get("paid-tenant"); !ok {
+		t.Error("High-priority entry was evicted by one-hit traffic")
+	}
+	if _, ok := cache.Get("free-tenant"); ok {
+		t.Error("Expected the normal entry to be evicted")
+	}
+	if p, ok := cache.Priority("paid-tenant"); !ok || p != 5 {
+		t.Errorf("Priority = %d, %v; want 5", p, ok)
+	}
+}
+
+func TestCloxCacheNegativePriority(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	// A frequently used but deprioritized entry goes before fresh normal entries
+	cache.PutWithPriority("crawl", 0, -15)
+	for range 10 {
+		cache.Get("crawl")
+	}
+	for i := range 7 {
+		cache.Put(fmt.Sprintf("normal-%d", i), i)
+	}
+	cache.Put("one-more", 0)
+
+	if _, ok := cache.Get("crawl"); ok {
+		t.Error("Expected the negative-priority entry to be evicted first")
+	}
+	for i := range 7 {
+		if _, ok := cache.Get(fmt.Sprintf("normal-%d", i)); !ok {
+			t.Errorf("normal-%d was evicted instead", i)
+		}
+	}
+}
+
+func TestCloxCacheSetPriority(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+
+	if cache.SetPriority("missing", 1) {
+		t.Error("SetPriority on a missing key should fail")
+	}
+	cache.Put("a", 1)
+	if !cache.SetPriority("a", 100) {
+		t.Fatal("SetPriority failed")
+	}
+	if p, _ := cache.Priority("a"); p != maxFrequency {
+		t.Errorf("Expected priority clamped to %d, got %d", maxFrequency, p)
+	}
+	cache.Put("a", 2)
+	if p, _ := cache.Priority("a"); p != maxFrequency {
+		t.Errorf("Plain Put reset the priority to %d", p)
+	}
+}
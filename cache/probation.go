@@ -0,0 +1,47 @@
+package cache
+
+// Probationary segment (Config.ProbationPercent > 0).
+//
+// Entries start out probationary and join the protected segment on their second
+// access (any frequency above initialFreq, including ghosts promoted on re-insert).
+// While the protected segment is within its share of the shard, inserts only evict
+// probationary entries, so a scan over one-time keys churns through probation and
+// never reaches the working set. Once promotions push the protected segment past
+// its share, eviction falls back to the normal policy until it shrinks again.
+
+// segmented reports whether shards count their protected entries
+// (needed by ProbationPercent and AdaptiveBalance)
+func (c *CloxCache[K, V]) segmented() bool {
+	return c.config.ProbationPercent > 0 || c.config.AdaptiveBalance
+}
+
+// freqChanged keeps the shard's protected count in step with a node's frequency
+// moving from one value to another (either may be <= 0 for ghosts and removed nodes)
+func (c *CloxCache[K, V]) freqChanged(shard *shard[K, V], from, to int32) {
+	if !c.segmented() {
+		return
+	}
+	switch {
+	case from <= initialFreq && to > initialFreq:
+		shard.protected.Add(1)
+	case from > initialFreq && to <= initialFreq:
+		shard.protected.Add(-1)
+	}
+}
+
+// probationOnly reports whether an insert into shard may only evict probationary entries
+func (c *CloxCache[K, V]) probationOnly(shard *shard[K, V]) bool {
+	return c.config.ProbationPercent > 0 && shard.protected.Load() <= shard.protectedCapacity
+}
+
+// SegmentCounts returns the number of probationary and protected live entries
+// across all shards. Without a ProbationPercent or AdaptiveBalance every entry
+// counts as probationary.
+func (c *CloxCache[K, V]) SegmentCounts() (probation, protected int64) {
+	for i := range c.shards {
+		p := c.shards[i].protected.Load()
+		protected += p
+		probation += c.shards[i].entryCount.Load() - p
+	}
+	return probation, protected
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheProbationScanResistance(t *testing.T) {
+	for _, percent := range []int{0, 20} {
+		cfg := Config{NumShards: 1, SlotsPerShard: 256, Capacity: 100, SweepPercent: 100, ProbationPercent: percent}
+		cache := NewCloxCache[string, int](cfg)
+
+		// Working set: accessed once after insert (freq 2, not above the default k)
+		for i := range 50 {
+			key := fmt.Sprintf("hot-%d", i)
+			cache.Put(key, i)
+			cache.Get(key)
+		}
+		// A large scan over one-time keys
+		for i := range 10000 {
+			cache.Put(fmt.Sprintf("scan-%d", i), i)
+		}
+
+		kept := 0
+		for i := range 50 {
+			if _, ok := cache.Get(fmt.Sprintf("hot-%d", i)); ok {
+				kept++
+			}
+		}
+		if percent == 0 && kept == 50 {
+			t.Error("Expected the scan to displace part of the working set without probation")
+		}
+		if percent > 0 && kept != 50 {
+			t.Errorf("Scan displaced %d protected entries", 50-kept)
+		}
+		if report := cache.VerifyIntegrity(); !report.OK() {
+			t.Errorf("Integrity issues: %v", report.Issues)
+		}
+		cache.Close()
+	}
+}
+
+func TestCloxCacheProbationOverflow(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 10, SweepPercent: 100, ProbationPercent: 50}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 10 {
+		key := fmt.Sprintf("key-%d", i)
+		cache.Put(key, i)
+		cache.Get(key)
+	}
+	if probation, protected := cache.SegmentCounts(); probation != 0 || protected != 10 {
+		t.Fatalf("SegmentCounts = %d, %d; want 0, 10", probation, protected)
+	}
+
+	// The protected segment is over its share, so it can be evicted again
+	if !cache.Put("new", 0) {
+		t.Fatal("Put failed with an oversized protected segment")
+	}
+	if probation, protected := cache.SegmentCounts(); probation != 1 || protected != 9 {
+		t.Errorf("SegmentCounts = %d, %d; want 1, 9", probation, protected)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheProbationConcurrent(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128, ProbationPercent: 25}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 2000 {
+				key := fmt.Sprintf("key-%d", (g*7+i)%300)
+				switch i % 4 {
+				case 0:
+					cache.Put(key, i)
+				case 1:
+					cache.Delete(key)
+				default:
+					cache.Get(key)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestConfigProbationPercent(t *testing.T) {
+	if err := (Config{NumShards: 1, SlotsPerShard: 1, ProbationPercent: 100}).Validate(); err == nil {
+		t.Error("Expected Validate to reject ProbationPercent 100")
+	}
+	cfg, err := ConfigFromJSON([]byte(`{"capacity": 1000, "probationPercent": 20}`))
+	if err != nil || cfg.ProbationPercent != 20 {
+		t.Errorf("ConfigFromJSON probationPercent = %d, %v", cfg.ProbationPercent, err)
+	}
+	if d := (Config{NumShards: 1, SlotsPerShard: 128, Capacity: 100, ProbationPercent: 20}).derive(); d.protectedCapacity != 80 {
+		t.Errorf("protectedCapacity = %d, want 80", d.protectedCapacity)
+	}
+}
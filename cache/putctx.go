@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// lockSpins is how many times a bounded lock acquisition yields before it starts
+// sleeping between attempts
+const lockSpins = 8
+
+// maxLockBackoff caps the sleep between bounded lock attempts, and with it how
+// late a waiter notices its deadline
+const maxLockBackoff = 100 * time.Microsecond
+
+// PutCtx is PutE bounded by ctx: it gives up once ctx is done while it waits for
+// the shard lock or evicts to make room, so a slow eviction scan during a burst
+// costs the caller a miss instead of its deadline. The error then wraps both
+// ErrRejected and ctx.Err() (match it with errors.Is), and the rejection is
+// counted as RejectDeadline. Updates of a live key normally take no lock and
+// complete regardless. An eviction scan that has started runs to the end, so a
+// put can overrun its deadline by one scan.
+func (c *CloxCache[K, V]) PutCtx(ctx context.Context, key K, value V) error {
+	hash, hi := c.hashes(key)
+	if err := ctx.Err(); err != nil {
+		c.shards[hash&uint64(c.numShards-1)].rejected(RejectDeadline)
+		return fmt.Errorf("%w (%s): %w", ErrRejected, RejectDeadline, err)
+	}
+	_, reason := c.putReasonWithHash(key, hash, hi, value, &opTrace{ctx: ctx})
+	if reason == RejectDeadline {
+		return fmt.Errorf("%w (%s): %w", ErrRejected, reason, ctx.Err())
+	}
+	return reason.Err()
+}
+
+// lock acquires mu, or returns why the operation gave up on it: RejectShardLocked
+// for a TryPut that found it held, RejectDeadline once a PutCtx's context is done
+func (t *opTrace) lock(mu *sync.Mutex) RejectReason {
+	switch {
+	case t == nil:
+	case t.try:
+		if !mu.TryLock() {
+			return RejectShardLocked
+		}
+		return RejectNone
+	case t.ctx != nil:
+		if !lockContext(t.ctx, mu) {
+			return RejectDeadline
+		}
+		return RejectNone
+	}
+	mu.Lock()
+	return RejectNone
+}
+
+// expired reports whether the operation's context is done
+func (t *opTrace) expired() bool {
+	return t != nil && t.ctx != nil && t.ctx.Err() != nil
+}
+
+// skipsEviction reports whether the operation drops its write rather than
+// evict to make room (TryPut)
+func (t *opTrace) skipsEviction() bool {
+	return t != nil && t.try
+}
+
+// lockContext acquires mu unless ctx is done first. sync.Mutex can't wait on a
+// channel, so a contended lock is polled: a few yields for short critical
+// sections, then sleeps that double up to maxLockBackoff. Pollers get no place in
+// the mutex's queue, so they can lose out to blocking Lock calls under heavy
+// contention; they then fail at their deadline rather than wait.
+func lockContext(ctx context.Context, mu *sync.Mutex) bool {
+	backoff := time.Microsecond
+	for i := 0; ; i++ {
+		if mu.TryLock() {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		if i < lockSpins {
+			runtime.Gosched()
+			continue
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxLockBackoff)
+	}
+}
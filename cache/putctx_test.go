@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCloxCachePutCtx(t *testing.T) {
+	cache := NewCloxCache[string, int](ConfigFromCapacity(100))
+	defer cache.Close()
+
+	if err := cache.PutCtx(context.Background(), "a", 1); err != nil {
+		t.Fatalf("PutCtx = %v", err)
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %d, %v; want 1, true", v, ok)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := cache.PutCtx(ctx, "b", 2)
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrRejected) {
+		t.Errorf("PutCtx with a canceled context = %v, want ErrRejected and context.Canceled", err)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Canceled PutCtx stored its value")
+	}
+	if n := cache.Rejections(RejectDeadline); n != 1 {
+		t.Errorf("Rejections(%s) = %d, want 1", RejectDeadline, n)
+	}
+}
+
+func TestCloxCachePutCtxLockedShard(t *testing.T) {
+	cache := NewCloxCache[string, int](ConfigFromCapacity(100))
+	defer cache.Close()
+
+	hash, _ := cache.hashes("k")
+	shard, _ := cache.locate(hash)
+	shard.mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := cache.PutCtx(ctx, "k", 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PutCtx on a locked shard = %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("PutCtx took %v past a 20ms deadline", d)
+	}
+
+	// Released before the deadline, the lock is taken
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	time.AfterFunc(10*time.Millisecond, shard.mu.Unlock)
+	if err := cache.PutCtx(ctx, "k", 2); err != nil {
+		t.Errorf("PutCtx after unlock = %v", err)
+	}
+	if v, ok := cache.Get("k"); !ok || v != 2 {
+		t.Errorf("Get(k) = %d, %v; want 2, true", v, ok)
+	}
+}
+
+func TestCloxCachePutCtxEviction(t *testing.T) {
+	cache := NewCloxCache[string, int](ConfigFromCapacity(100))
+	defer cache.Close()
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+
+	// A context that ends before eviction starts stores nothing new in a full cache
+	ctx, cancel := context.WithCancel(context.Background())
+	trace := &opTrace{ctx: ctx}
+	cancel()
+	hash, hi := cache.hashes("late")
+	if _, reason := cache.putReasonWithHash("late", hash, hi, 1, trace); reason != RejectDeadline {
+		t.Errorf("put with a done context in a full shard = %s, want %s", reason, RejectDeadline)
+	}
+}
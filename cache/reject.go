@@ -0,0 +1,67 @@
+package cache
+
+// RejectReason explains why a Put didn't store its value
+type RejectReason uint8
+
+const (
+	// RejectNone - the value was stored
+	RejectNone RejectReason = iota
+	// RejectNoVictim - the shard was full and its eviction scan found nothing it may
+	// evict (every candidate reserved by a priority class floor or protected
+	// segment), or a TryPut found it full and skipped the scan
+	RejectNoVictim
+	// RejectAdmission - the entry that would have been evicted is requested more often
+	// than the incoming key (Config.Admission)
+	RejectAdmission
+	// RejectOverWeight - the entry weighs more than the cache accepts
+	RejectOverWeight
+	// RejectShardLocked - a non-blocking put found the shard lock held
+	RejectShardLocked
+	// RejectClosed - the cache was closed
+	RejectClosed
+	// RejectFrozen - the put would have added an entry while the cache was frozen
+	RejectFrozen
+	// RejectDeadline - a PutCtx's context ended while it waited for the shard lock
+	// or made room
+	RejectDeadline
+
+	numRejectReasons
+)
+
+// rejectReasonNames are the text forms of each RejectReason, indexed by value
+var rejectReasonNames = [numRejectReasons]string{
+	RejectNone:        "none",
+	RejectNoVictim:    "victim-not-found",
+	RejectAdmission:   "admission",
+	RejectOverWeight:  "over-weight",
+	RejectShardLocked: "shard-locked",
+	RejectClosed:      "closed",
+	RejectFrozen:      "frozen",
+	RejectDeadline:    "deadline",
+}
+
+func (r RejectReason) String() string {
+	if r < numRejectReasons {
+		return rejectReasonNames[r]
+	}
+	return "unknown"
+}
+
+// rejected counts a Put that didn't store its value and returns reason
+func (s *shard[K, V]) rejected(reason RejectReason) RejectReason {
+	s.rejects[reason].Add(1)
+	return reason
+}
+
+// Rejections returns how many Puts were rejected for reason across all shards
+// (always collected, independent of CollectStats)
+func (c *CloxCache[K, V]) Rejections(reason RejectReason) uint64 {
+	if reason == RejectNone || reason >= numRejectReasons {
+		return 0
+	}
+	var total uint64
+	for i := range c.shards {
+		total += c.shards[i].rejects[reason].Load()
+	}
+	return total
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCachePutReason(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, SweepPercent: 100}
+	// Class 1 reserves the whole cache, so class-0 inserts find no victim
+	cache := NewCloxCache(cfg, WithPriorityClasses[string, int](0, 1))
+	defer cache.Close()
+
+	for i := range 4 {
+		cache.PutWithClass(fmt.Sprintf("vip-%d", i), i, 1)
+	}
+	if ok, reason := cache.PutReason("crawl", 0); ok || reason != RejectNoVictim {
+		t.Errorf("PutReason = %v, %s; want false, %s", ok, reason, RejectNoVictim)
+	}
+	if ok, reason := cache.PutReason("vip-0", 10); !ok || reason != RejectNone {
+		t.Errorf("PutReason update = %v, %s; want true, %s", ok, reason, RejectNone)
+	}
+	if n := cache.Rejections(RejectNoVictim); n != 1 {
+		t.Errorf("Rejections(%s) = %d, want 1", RejectNoVictim, n)
+	}
+	if n := cache.StatsSnapshot().Rejected; n != 1 {
+		t.Errorf("StatsSnapshot.Rejected = %d, want 1", n)
+	}
+}
+
+func TestCloxCacheAdmissionRejectReason(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 2, SweepPercent: 100, Admission: true}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for _, key := range []string{"a", "b"} {
+		cache.Put(key, 0)
+		for range 5 {
+			cache.Get(key)
+		}
+	}
+	if ok, reason := cache.PutReason("one-hit", 0); ok || reason != RejectAdmission {
+		t.Errorf("PutReason = %v, %s; want false, %s", ok, reason, RejectAdmission)
+	}
+	if n, m := cache.Rejections(RejectAdmission), cache.AdmissionRejections(); n != 1 || m != 1 {
+		t.Errorf("Rejections(%s) = %d, AdmissionRejections = %d, want 1", RejectAdmission, n, m)
+	}
+}
+
+func TestRejectReasonString(t *testing.T) {
+	for reason, want := range map[RejectReason]string{
+		RejectNone:        "none",
+		RejectNoVictim:    "victim-not-found",
+		RejectOverWeight:  "over-weight",
+		RejectShardLocked: "shard-locked",
+		RejectClosed:      "closed",
+		RejectDeadline:    "deadline",
+		99:                "unknown",
+	} {
+		if got := reason.String(); got != want {
+			t.Errorf("RejectReason(%d).String() = %q, want %q", reason, got, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package cache
+
+// Sampled eviction (Config.EvictionSamples > 0).
+//
+// The default eviction scan visits a fixed window of slots after the shard's
+// CLOCK hand, so its cost and the number of candidates it weighs depend on how
+// the entries are spread: a sparse table yields few candidates per scan, and
+// long chains make some windows far more expensive than others. Sampling walks
+// the chains of uniformly random slots instead and stops once it has weighed
+// EvictionSamples live entries (ghosts aren't counted), choosing among them by
+// the same rules as the scan. A shard without enough live entries ends after
+// as many probes as it has slots.
+
+// sampleSlot returns a random slot to sample for eviction
+func (c *CloxCache[K, V]) sampleSlot(slotsPerShard int) int {
+	return int(c.randN(uint32(slotsPerShard)))
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// hotSurvivors fills a sparse single-shard cache with accessed keys, streams
+// one-shot keys through it and returns how many of the accessed keys are left
+func hotSurvivors(t *testing.T, cfg Config) int {
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	const hot = 48
+	for i := range hot {
+		cache.Put(fmt.Sprintf("hot-%d", i), i)
+	}
+	for range 3 {
+		for i := range hot {
+			cache.Get(fmt.Sprintf("hot-%d", i))
+		}
+	}
+	for i := range 2000 {
+		cache.Put(fmt.Sprintf("cold-%d", i), i)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+
+	survivors := 0
+	for i := range hot {
+		if _, ok := cache.Get(fmt.Sprintf("hot-%d", i)); ok {
+			survivors++
+		}
+	}
+	return survivors
+}
+
+func TestCloxCacheEvictionSamples(t *testing.T) {
+	// 4096 slots for 64 entries: a 1% window holds under one entry on average
+	cfg := Config{NumShards: 1, SlotsPerShard: 4096, Capacity: 64, SweepPercent: 1}
+	window := hotSurvivors(t, cfg)
+
+	cfg.EvictionSamples = 8
+	sampled := hotSurvivors(t, cfg)
+	// A batch of 8 samples is all accessed keys about 10% of the time, so some go
+	if sampled < 16 || sampled <= window {
+		t.Errorf("Sampling kept %d of 48 accessed keys, window scan %d", sampled, window)
+	}
+	t.Logf("accessed keys kept: window scan %d, sampling %d", window, sampled)
+}
+
+func TestCloxCacheEvictionSamplesFewEntries(t *testing.T) {
+	// Sampling ends after one probe per slot when there aren't enough live entries
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 4, EvictionSamples: 100})
+	defer cache.Close()
+	for i := range 50 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if n, limit := cache.shards[0].entryCount.Load(), cache.shards[0].shardCapacity(); n > limit {
+		t.Errorf("%d entries, want at most %d", n, limit)
+	}
+}
+
+func TestConfigDescribeEvictionSamples(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100, EvictionSamples: 5}
+	if desc := cfg.Describe(); !strings.Contains(desc, "sampling 5 live entries") {
+		t.Errorf("Describe doesn't mention sampling:\n%s", desc)
+	}
+}
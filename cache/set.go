@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Set is an immutable set stored compactly as a sorted slice without duplicates.
+// It is the value type for the set helpers (SetAdd, SetRemove, ...), which
+// modify sets atomically per key. Being a plain slice, it works with the
+// default snapshot codec and JSONCodec.
+type Set[E cmp.Ordered] []E
+
+// Contains reports whether member is in the set (binary search)
+func (s Set[E]) Contains(member E) bool {
+	_, found := slices.BinarySearch(s, member)
+	return found
+}
+
+// Len returns the number of members
+func (s Set[E]) Len() int {
+	return len(s)
+}
+
+// All returns an iterator over the members in ascending order
+func (s Set[E]) All() iter.Seq[E] {
+	return slices.Values(s)
+}
+
+// with returns a new set containing s and members, and how many were added
+func (s Set[E]) with(members []E) (Set[E], int) {
+	add := slices.Clone(members)
+	slices.Sort(add)
+	add = slices.Compact(add)
+
+	out := make(Set[E], 0, len(s)+len(add))
+	i, j := 0, 0
+	for i < len(s) && j < len(add) {
+		switch c := cmp.Compare(s[i], add[j]); {
+		case c < 0:
+			out = append(out, s[i])
+			i++
+		case c > 0:
+			out = append(out, add[j])
+			j++
+		default:
+			out = append(out, s[i])
+			i++
+			j++
+		}
+	}
+	out = append(append(out, s[i:]...), add[j:]...)
+	return out, len(out) - len(s)
+}
+
+// without returns a new set with members removed, and how many were removed
+func (s Set[E]) without(members []E) (Set[E], int) {
+	remove := Set[E](slices.Clone(members))
+	slices.Sort(remove)
+
+	out := make(Set[E], 0, len(s))
+	for _, m := range s {
+		if !remove.Contains(m) {
+			out = append(out, m)
+		}
+	}
+	return out, len(s) - len(out)
+}
+
+// SetAdd adds members to the set at key, creating it if absent, and returns how
+// many were not already present
+func SetAdd[K Key, E cmp.Ordered](c *CloxCache[K, Set[E]], key K, members ...E) int {
+	var added int
+	if _, ok := c.Update(key, func(old Set[E], _ bool) Set[E] {
+		var set Set[E]
+		set, added = old.with(members)
+		return set
+	}); !ok {
+		return 0
+	}
+	return added
+}
+
+// SetRemove removes members from the set at key and returns how many were present.
+// Missing keys are not created.
+func SetRemove[K Key, E cmp.Ordered](c *CloxCache[K, Set[E]], key K, members ...E) int {
+	var removed int
+	if _, ok := c.updateExisting(key, func(old Set[E]) Set[E] {
+		var set Set[E]
+		set, removed = old.without(members)
+		return set
+	}); !ok {
+		return 0
+	}
+	return removed
+}
+
+// SetIsMember reports whether member is in the set at key. Counts as an access like Get.
+func SetIsMember[K Key, E cmp.Ordered](c *CloxCache[K, Set[E]], key K, member E) bool {
+	set, _ := c.Get(key)
+	return set.Contains(member)
+}
+
+// SetLen returns the number of members in the set at key (0 if absent) without
+// recording an access
+func SetLen[K Key, E cmp.Ordered](c *CloxCache[K, Set[E]], key K) int {
+	set, _ := c.peek(key)
+	return len(set)
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheSet(t *testing.T) {
+	cache := NewCloxCache[string, Set[int]](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	if added := SetAdd(cache, "segment:vip", 5, 3, 9, 3); added != 3 {
+		t.Errorf("Expected 3 added, got %d", added)
+	}
+	if added := SetAdd(cache, "segment:vip", 1, 5); added != 1 {
+		t.Errorf("Expected 1 added, got %d", added)
+	}
+	set, _ := cache.Get("segment:vip")
+	if !slices.Equal(set, Set[int]{1, 3, 5, 9}) {
+		t.Errorf("Set = %v, want sorted [1 3 5 9]", set)
+	}
+
+	if !SetIsMember(cache, "segment:vip", 5) || SetIsMember(cache, "segment:vip", 4) {
+		t.Error("SetIsMember reported wrong membership")
+	}
+	if SetIsMember(cache, "missing", 1) {
+		t.Error("SetIsMember on a missing key should be false")
+	}
+
+	if removed := SetRemove(cache, "segment:vip", 3, 4, 9); removed != 2 {
+		t.Errorf("Expected 2 removed, got %d", removed)
+	}
+	if n := SetLen(cache, "segment:vip"); n != 2 {
+		t.Errorf("SetLen = %d, want 2", n)
+	}
+	if !slices.Equal(set, Set[int]{1, 3, 5, 9}) {
+		t.Errorf("Previously read set was modified: %v", set)
+	}
+	if got := slices.Collect(set.All()); !slices.Equal(got, []int{1, 3, 5, 9}) {
+		t.Errorf("All() = %v", got)
+	}
+
+	if removed := SetRemove(cache, "missing", 1); removed != 0 {
+		t.Errorf("SetRemove on a missing key = %d", removed)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("SetRemove created a missing key")
+	}
+}
+
+func TestCloxCacheSetConcurrent(t *testing.T) {
+	cache := NewCloxCache[string, Set[string]](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				SetAdd(cache, "members", fmt.Sprintf("%d-%d", g, i))
+			}
+		}()
+	}
+	wg.Wait()
+	if n := SetLen(cache, "members"); n != 800 {
+		t.Errorf("Expected 800 members, got %d", n)
+	}
+}
+
+func TestCloxCacheSetSnapshot(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100}
+	cache := NewCloxCache[string, Set[string]](cfg)
+	defer cache.Close()
+	SetAdd(cache, "s", "b", "a")
+
+	var buf bytes.Buffer
+	if err := cache.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	restored := NewCloxCache[string, Set[string]](cfg)
+	defer restored.Close()
+	if _, err := restored.ReadSnapshot(&buf); err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	if !SetIsMember(restored, "s", "a") || SetLen(restored, "s") != 2 {
+		t.Error("Set did not survive a snapshot round trip")
+	}
+}
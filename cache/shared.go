@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// MmapAttachReport describes what OpenSharedStore found in an existing segment
+type MmapAttachReport struct {
+	Recovered int    // values indexed from the segment
+	Dead      int    // records skipped because their value was loaded, deleted or replaced
+	Discarded uint64 // bytes dropped from the end of the ring by the repair pass
+	Reset     bool   // the segment was new, or its header was invalid, so it started empty
+}
+
+// OpenSharedStore opens the named shared-memory segment (a file in /dev/shm on
+// Linux, in the temporary directory elsewhere), creating it size bytes long if it
+// doesn't exist, and returns an MmapStore on it. The segment outlives the process:
+// a cache using the store with WithOverflow moves the values it still holds into
+// it on Close, so a restarted process that opens the same segment reattaches to a
+// warm cache in milliseconds, promoting values back as they are read.
+//
+// Attaching runs a validation and repair pass over the records in use: a record
+// with an impossible length or a checksum mismatch, as left by a process that
+// died mid-write, ends the ring there, and an invalid header or a different size
+// starts it empty (see AttachReport). Only one process can have a segment open at
+// a time. Segments do not survive a reboot; remove one with RemoveSharedSegment.
+func OpenSharedStore[K Key, V any](name string, size int64, codec Codec[V]) (*MmapStore[K, V], error) {
+	path, err := sharedSegmentPath(name)
+	if err != nil {
+		return nil, err
+	}
+	return openMmapStore[K](path, size, codec, true)
+}
+
+// RemoveSharedSegment deletes the named shared-memory segment
+func RemoveSharedSegment(name string) error {
+	path, err := sharedSegmentPath(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// sharedSegmentPath returns the file backing the named segment
+func sharedSegmentPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("cache: invalid shared segment name %q", name)
+	}
+	dir := os.TempDir()
+	if runtime.GOOS == "linux" {
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			dir = "/dev/shm"
+		}
+	}
+	return filepath.Join(dir, "cloxcache-"+name), nil
+}
+
+// AttachReport returns what attaching to the segment found (zero for a store
+// from NewMmapStore)
+func (m *MmapStore[K, V]) AttachReport() MmapAttachReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.attach
+}
+
+// Retains reports whether the store outlives the process (see OpenSharedStore)
+func (m *MmapStore[K, V]) Retains() bool {
+	return m.shared
+}
+
+// attachRecords validates the segment and rebuilds the index from the records in
+// use, cutting the ring short at the first damaged one
+func (m *MmapStore[K, V]) attachRecords() {
+	size := uint64(len(m.ring))
+	head := binary.LittleEndian.Uint64(m.data[16:])
+	tail := binary.LittleEndian.Uint64(m.data[24:])
+	if string(m.data[:len(mmapMagic)]) != mmapMagic ||
+		binary.LittleEndian.Uint32(m.data[4:]) != mmapVersion ||
+		binary.LittleEndian.Uint64(m.data[8:]) != size ||
+		tail < head || tail-head > size {
+		m.reset()
+		m.attach.Reset = true
+		return
+	}
+	m.head, m.tail = head, tail
+
+	var key, value []byte
+	at := head
+	for at < tail {
+		keyLen, valueLen, crc, flags := m.recordHeader(at)
+		if tail-at < mmapRecordHeader || keyLen+valueLen > tail-at-mmapRecordHeader {
+			break
+		}
+		key, value = resize(key, keyLen), resize(value, valueLen)
+		m.read(at+mmapRecordHeader, key)
+		m.read(at+mmapRecordHeader+keyLen, value)
+		if crc32.Update(crc32.Checksum(key, snapshotCRC), snapshotCRC, value) != crc {
+			break
+		}
+		if flags&mmapRecordDead != 0 {
+			m.attach.Dead++
+		} else {
+			// A live record written after one for the same key replaces it
+			m.kill(string(key))
+			m.index[string(key)] = at
+		}
+		at += mmapRecordHeader + keyLen + valueLen
+	}
+	if at != tail {
+		m.attach.Discarded = tail - at
+		m.setTail(at)
+	}
+	m.attach.Recovered = len(m.index)
+}
+
+// resize returns buf with length n, reallocating if it is too small
+func resize(buf []byte, n uint64) []byte {
+	if uint64(cap(buf)) < n {
+		return make([]byte, n)
+	}
+	return buf[:n]
+}
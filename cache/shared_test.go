@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func openTestSegment(t *testing.T, name string) *MmapStore[string, string] {
+	store, err := OpenSharedStore[string, string](name, 1<<16, nil)
+	if errors.Is(err, ErrMmapUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("OpenSharedStore: %v", err)
+	}
+	return store
+}
+
+func TestCloxCacheSharedStore(t *testing.T) {
+	name := fmt.Sprintf("test-%d", os.Getpid())
+	t.Cleanup(func() { RemoveSharedSegment(name) })
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+
+	store := openTestSegment(t, name)
+	if report := store.AttachReport(); !report.Reset {
+		t.Errorf("AttachReport() = %+v for a new segment, want Reset", report)
+	}
+	if _, err := OpenSharedStore[string, string](name, 1<<16, nil); err == nil {
+		t.Error("A segment in use was opened again")
+	}
+	cache := NewCloxCache(cfg, WithOverflow[string, string](store))
+	for i := range 20 {
+		cache.Put(fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i))
+	}
+	cache.Delete("key-19")
+	cache.Close() // moves the cached values into the segment
+	store.Close()
+
+	// A restart reattaches to every value
+	store = openTestSegment(t, name)
+	if report := store.AttachReport(); report.Recovered != 19 || report.Reset || report.Discarded != 0 {
+		t.Errorf("AttachReport() = %+v, want 19 values recovered", report)
+	}
+	cache = NewCloxCache(cfg, WithOverflow[string, string](store))
+	for i := range 19 {
+		key := fmt.Sprintf("key-%d", i)
+		if v, ok := cache.Get(key); !ok || v != fmt.Sprintf("value-%d", i) {
+			t.Errorf("Get(%s) = %q, %v after reattaching", key, v, ok)
+		}
+	}
+	if _, ok := cache.Get("key-19"); ok {
+		t.Error("A deleted value came back")
+	}
+	cache.Close()
+	store.Close()
+}
+
+func TestCloxCacheSharedStoreFinalizer(t *testing.T) {
+	name := fmt.Sprintf("finalized-%d", os.Getpid())
+	t.Cleanup(func() { RemoveSharedSegment(name) })
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64}
+
+	// Close finalizes every cached value, which must not drop the copies it just
+	// saved to the segment
+	store := openTestSegment(t, name)
+	finalized := 0
+	cache := NewCloxCache(cfg, WithOverflow[string, string](store),
+		WithFinalizer(func(string, string) { finalized++ }))
+	for i := range 50 {
+		cache.Put(fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i))
+	}
+	cache.Close()
+	store.Close()
+	if finalized != 50 {
+		t.Errorf("Close finalized %d values, want 50", finalized)
+	}
+
+	store = openTestSegment(t, name)
+	defer store.Close()
+	if report := store.AttachReport(); report.Recovered != 50 {
+		t.Errorf("AttachReport() = %+v, want 50 values recovered", report)
+	}
+}
+
+func TestSharedStoreRepair(t *testing.T) {
+	name := fmt.Sprintf("repair-%d", os.Getpid())
+	t.Cleanup(func() { RemoveSharedSegment(name) })
+
+	store := openTestSegment(t, name)
+	store.Store("a", "alpha")
+	store.Store("b", "beta")
+	store.Store("c", "gamma")
+	// A process that died while writing "c" leaves it damaged
+	at := store.index["c"]
+	store.ring[at+mmapRecordHeader+1] ^= 0xff
+	store.Close()
+
+	store = openTestSegment(t, name)
+	report := store.AttachReport()
+	if report.Recovered != 2 || report.Discarded != mmapRecordHeader+1+5 {
+		t.Errorf("AttachReport() = %+v, want 2 values and the damaged record discarded", report)
+	}
+	if v, ok := store.Load("b"); !ok || v != "beta" {
+		t.Errorf("Load(b) = %q, %v", v, ok)
+	}
+	if _, ok := store.Load("c"); ok {
+		t.Error("The damaged value was loaded")
+	}
+	// The ring continues where the repair cut it
+	store.Store("d", "delta")
+	store.Close()
+
+	store = openTestSegment(t, name)
+	if report := store.AttachReport(); report.Recovered != 2 || report.Dead != 1 || report.Discarded != 0 {
+		t.Errorf("AttachReport() = %+v, want a and d recovered, b dead", report)
+	}
+	store.Close()
+
+	// A segment of a different size starts over
+	store, err := OpenSharedStore[string, string](name, 1<<17, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if report := store.AttachReport(); !report.Reset || store.Len() != 0 {
+		t.Errorf("AttachReport() = %+v after resizing, want Reset", report)
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import "unsafe"
+
+// WithWeigher sets the function used to measure an entry's size in bytes.
+// It is called once per Put; the result is reported by EntrySize and SizeBytes.
+// Without a weigher, entries are weighed as len(key) plus len(value) for
+// string/[]byte values or the in-memory size of V otherwise.
+func WithWeigher[K Key, V any](weigher func(key K, value V) int64) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.weigher = weigher
+	}
+}
+
+// weigh returns the size recorded for an entry
+func (c *CloxCache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher != nil {
+		return c.weigher(key, value)
+	}
+	return int64(len(key)) + valueSize(value)
+}
+
+// oversized reports whether an entry exceeds MaxKeyBytes or MaxValueBytes
+func (c *CloxCache[K, V]) oversized(key K, value V) bool {
+	if c.config.MaxKeyBytes > 0 && len(key) > c.config.MaxKeyBytes {
+		return true
+	}
+	return c.config.MaxValueBytes > 0 && c.weigh(key, value)-int64(len(key)) > c.config.MaxValueBytes
+}
+
+// valueSize estimates the payload size of a value without a weigher
+func valueSize[V any](value V) int64 {
+	switch v := any(value).(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return int64(unsafe.Sizeof(value))
+	}
+}
+
+// EntrySize returns the weighed size of a live entry as captured at Put time
+func (c *CloxCache[K, V]) EntrySize(key K) (int64, bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+
+	if node := c.findNode(shard, hash, hi, key, c.liveNode); node != nil {
+		return node.size.Load(), true
+	}
+	return 0, false
+}
+
+// SizeBytes returns the total weighed size of all live entries.
+// Under concurrent updates to the same key the total may be briefly approximate.
+func (c *CloxCache[K, V]) SizeBytes() int64 {
+	var total int64
+	for i := range c.shards {
+		total += c.shards[i].liveBytes.Load()
+	}
+	return total
+}
+
+// ShardSizeBytes returns the weighed size of live entries in each shard
+func (c *CloxCache[K, V]) ShardSizeBytes() []int64 {
+	sizes := make([]int64, c.numShards)
+	for i := range c.shards {
+		sizes[i] = c.shards[i].liveBytes.Load()
+	}
+	return sizes
+}
+
+// measureSampleSize bounds how many live entries MeasureEntrySizes inspects
+const measureSampleSize = 4096
+
+// MeasureEntrySizes samples live entries and returns their average key and value
+// sizes in bytes (value size is the weighed size minus the key). Feed the result to
+// ConfigFromMemorySizeFor to size a cache from its real footprint.
+// Returns zeros if the cache is empty.
+func (c *CloxCache[K, V]) MeasureEntrySizes() (avgKeyBytes, avgValueBytes int) {
+	var keyBytes, valueBytes, sampled int64
+	c.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		if node.freq.Load() <= 0 || c.stale(node) {
+			return true
+		}
+		keyLen := int64(node.keyLen())
+		keyBytes += keyLen
+		valueBytes += max(node.size.Load()-keyLen, 0)
+		sampled++
+		return sampled < measureSampleSize
+	})
+	if sampled == 0 {
+		return 0, 0
+	}
+	return int(keyBytes / sampled), int(valueBytes / sampled)
+}
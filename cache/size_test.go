@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheEntrySize(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	cache.Put("key", make([]byte, 100))
+	if size, ok := cache.EntrySize("key"); !ok || size != 103 {
+		t.Errorf("EntrySize(key) = %d, %v; want 103, true", size, ok)
+	}
+	if _, ok := cache.EntrySize("missing"); ok {
+		t.Error("EntrySize reported a size for a missing key")
+	}
+
+	cache.Put("other", make([]byte, 50))
+	if total := cache.SizeBytes(); total != 103+55 {
+		t.Errorf("SizeBytes() = %d, want %d", total, 103+55)
+	}
+
+	// Updates replace the recorded size
+	cache.Put("key", make([]byte, 10))
+	if total := cache.SizeBytes(); total != 13+55 {
+		t.Errorf("SizeBytes() after update = %d, want %d", total, 13+55)
+	}
+}
+
+func TestCloxCacheWeigher(t *testing.T) {
+	type Record struct {
+		Payload []string
+	}
+
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	cache := NewCloxCache(cfg, WithWeigher(func(key string, r Record) int64 {
+		n := int64(len(key))
+		for _, p := range r.Payload {
+			n += int64(len(p))
+		}
+		return n
+	}))
+	defer cache.Close()
+
+	cache.Put("tenant:1", Record{Payload: []string{"aaaa", "bb"}})
+	if size, ok := cache.EntrySize("tenant:1"); !ok || size != 14 {
+		t.Errorf("EntrySize = %d, %v; want 14, true", size, ok)
+	}
+}
+
+func TestCloxCacheSizeBytesWithEviction(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	for i := range 20 {
+		cache.Put(fmt.Sprintf("k%02d", i), make([]byte, 97))
+	}
+
+	// Only live entries are counted; ghosts and evicted entries weigh nothing
+	live := cache.shards[0].entryCount.Load()
+	if got := cache.SizeBytes(); got != live*100 {
+		t.Errorf("SizeBytes() = %d with %d live entries, want %d", got, live, live*100)
+	}
+}
+
+func TestCloxCacheMeasureEntrySizes(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 256}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	if k, v := cache.MeasureEntrySizes(); k != 0 || v != 0 {
+		t.Errorf("MeasureEntrySizes on empty cache = %d, %d; want 0, 0", k, v)
+	}
+
+	for i := range 100 {
+		cache.Put(fmt.Sprintf("key-%04d", i), make([]byte, 2000))
+	}
+	avgKey, avgValue := cache.MeasureEntrySizes()
+	if avgKey != 8 || avgValue != 2000 {
+		t.Fatalf("MeasureEntrySizes = %d, %d; want 8, 2000", avgKey, avgValue)
+	}
+
+	// Large measured values yield a much smaller capacity than the default guess
+	const budget = 64 * 1024 * 1024
+	measured := ConfigFromMemorySizeFor(budget, avgKey, avgValue)
+	guessed := ConfigFromMemorySize(budget)
+	if measured.Capacity*5 > guessed.Capacity {
+		t.Errorf("Measured capacity %d not much smaller than default %d", measured.Capacity, guessed.Capacity)
+	}
+}
+
+func TestCloxCacheMaxEntrySize(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 32, MaxKeyBytes: 8, MaxValueBytes: 16}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	if err := cache.PutE("ok", make([]byte, 16)); err != nil {
+		t.Fatalf("PutE at the limits failed: %v", err)
+	}
+	if err := cache.PutE("too-long-key", []byte("v")); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("PutE with a long key = %v, want ErrTooLarge", err)
+	}
+	if ok, reason := cache.PutReason("big", make([]byte, 17)); ok || reason != RejectOverWeight {
+		t.Errorf("PutReason with a large value = %v, %v; want RejectOverWeight", ok, reason)
+	}
+	if _, ok := cache.Get("big"); ok {
+		t.Error("An oversized value was stored")
+	}
+
+	// Updates can't grow a value past the limit either; the old value stays
+	if _, ok := cache.Update("ok", func(old []byte, _ bool) []byte { return append(old, 'x') }); ok {
+		t.Error("Update stored an oversized value")
+	}
+	if v, _ := cache.Get("ok"); len(v) != 16 {
+		t.Errorf("Expected the old 16 byte value to remain, got %d bytes", len(v))
+	}
+	if _, ok := cache.Update("new", func([]byte, bool) []byte { return make([]byte, 32) }); ok {
+		t.Error("Update inserted an oversized value")
+	}
+
+	if n := cache.Rejections(RejectOverWeight); n != 4 {
+		t.Errorf("Expected 4 over-weight rejections, got %d", n)
+	}
+	if size := cache.SizeBytes(); size != 2+16 {
+		t.Errorf("SizeBytes = %d, want only the accepted entry", size)
+	}
+}
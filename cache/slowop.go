@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// SlowOp describes a Get or Put that took longer than the configured threshold
+type SlowOp struct {
+	Op            string        // "get" or "put"
+	Shard         int           // shard the key maps to
+	KeyHash       uint64        // hash of the key (keys themselves are not retained)
+	Duration      time.Duration // wall time of the operation
+	EvictionScans int           // eviction scans performed inline (put only)
+	SlotsScanned  int           // total slots visited by those scans
+}
+
+// opTrace accumulates the work done by a single operation, and carries how
+// long it may wait for the shard lock (see PutCtx and TryPut)
+type opTrace struct {
+	evictionScans int
+	slotsScanned  int
+	ctx           context.Context // nil = unbounded
+	try           bool            // give up if the lock is held
+}
+
+// WithSlowOpCallback reports every Get or Put slower than threshold to fn.
+// fn runs synchronously on the calling goroutine and should return quickly.
+// Enabling this adds a clock read to every operation.
+func WithSlowOpCallback[K Key, V any](threshold time.Duration, fn func(SlowOp)) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.onSlowOp = fn
+		c.slowOpThreshold = threshold
+	}
+}
+
+// getTimed is Get with slow operation reporting
+func (c *CloxCache[K, V]) getTimed(key K, hash, hi uint64) (V, bool) {
+	start := time.Now()
+	value, ok := c.get(key, hash, hi)
+	if d := time.Since(start); d >= c.slowOpThreshold {
+		c.onSlowOp(SlowOp{
+			Op:       "get",
+			Shard:    int(hash & uint64(c.numShards-1)),
+			KeyHash:  hash,
+			Duration: d,
+		})
+	}
+	return value, ok
+}
+
+// putTimed is Put with slow operation reporting; trace may be nil
+func (c *CloxCache[K, V]) putTimed(key K, hash, hi uint64, value V, trace *opTrace) RejectReason {
+	if trace == nil {
+		trace = new(opTrace)
+	}
+	start := time.Now()
+	reason := c.putWithHash(key, hash, hi, value, initialFreq, classUnchanged, trace)
+	if d := time.Since(start); d >= c.slowOpThreshold {
+		c.onSlowOp(SlowOp{
+			Op:            "put",
+			Shard:         int(hash & uint64(c.numShards-1)),
+			KeyHash:       hash,
+			Duration:      d,
+			EvictionScans: trace.evictionScans,
+			SlotsScanned:  trace.slotsScanned,
+		})
+	}
+	return reason
+}
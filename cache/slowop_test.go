@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheSlowOpCallback(t *testing.T) {
+	cfg := Config{
+		NumShards:     1,
+		SlotsPerShard: 16,
+		Capacity:      4,
+		SweepPercent:  100,
+	}
+
+	var mu sync.Mutex
+	var ops []SlowOp
+	// A zero threshold reports every operation
+	cache := NewCloxCache(cfg, WithSlowOpCallback[string, int](0, func(op SlowOp) {
+		mu.Lock()
+		ops = append(ops, op)
+		mu.Unlock()
+	}))
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	cache.Get("key-7")
+
+	if len(ops) != 9 {
+		t.Fatalf("Expected 9 reported operations, got %d", len(ops))
+	}
+
+	evictingPuts := 0
+	for _, op := range ops[:8] {
+		if op.Op != "put" {
+			t.Errorf("Expected put op, got %q", op.Op)
+		}
+		if op.EvictionScans > 0 {
+			evictingPuts++
+			if op.SlotsScanned != op.EvictionScans*16 {
+				t.Errorf("Expected %d slots scanned, got %d", op.EvictionScans*16, op.SlotsScanned)
+			}
+		}
+	}
+	if evictingPuts != 4 {
+		t.Errorf("Expected 4 puts to report eviction scans, got %d", evictingPuts)
+	}
+
+	get := ops[8]
+	if get.Op != "get" || get.KeyHash != hashKey("key-7") || get.Shard != 0 {
+		t.Errorf("Unexpected get report: %+v", get)
+	}
+}
+
+func TestCloxCacheSlowOpThreshold(t *testing.T) {
+	called := false
+	cache := NewCloxCache(Config{NumShards: 4, SlotsPerShard: 64},
+		WithSlowOpCallback[string, int](1<<62, func(SlowOp) { called = true }))
+	defer cache.Close()
+
+	cache.Put("key", 1)
+	cache.Get("key")
+	if called {
+		t.Error("Callback fired for an operation under the threshold")
+	}
+}
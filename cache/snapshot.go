@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNoCodec is returned when serializing a cache whose value type has no codec
+	ErrNoCodec = errors.New("cache: no codec configured for value type")
+	// ErrInvalidSnapshot is returned when snapshot data is malformed
+	ErrInvalidSnapshot = errors.New("cache: invalid snapshot")
+)
+
+// SnapshotOptions tunes WriteSnapshotContext
+type SnapshotOptions struct {
+	// BytesPerSecond caps the average rate the snapshot is written at, so a
+	// backup doesn't starve foreground traffic of disk or network bandwidth
+	// (0 = unlimited)
+	BytesPerSecond int64
+}
+
+// WriteSnapshot writes all live entries to w using the configured codec.
+// The snapshot is not a consistent point-in-time view: entries written
+// concurrently may or may not be included.
+func (c *CloxCache[K, V]) WriteSnapshot(w io.Writer) error {
+	return c.WriteSnapshotContext(context.Background(), w, SnapshotOptions{})
+}
+
+// WriteSnapshotContext is WriteSnapshot bounded by ctx and paced by opts. The
+// snapshot streams shard by shard straight from the live chains without taking
+// their locks, so the only extra memory is one checksummed block of entries
+// (about 64KB) and a small write buffer, however large the cache. Returns
+// ctx.Err() if ctx ends first.
+func (c *CloxCache[K, V]) WriteSnapshotContext(ctx context.Context, w io.Writer, opts SnapshotOptions) error {
+	if c.codec == nil {
+		return ErrNoCodec
+	}
+	if c.hashOnly {
+		return ErrNoKeys
+	}
+
+	flags := uint64(snapshotFlagBlocks)
+	if c.ttlClockOn.Load() {
+		flags |= snapshotFlagTTL // some entries may have a TTL
+	}
+	bw := bufio.NewWriter(newThrottledWriter(ctx, w, opts.BytesPerSecond))
+	if _, err := bw.Write(appendSnapshotHeader(nil, flags)); err != nil {
+		return err
+	}
+
+	var err error
+	var buf, block, body []byte
+	var count int
+	var entry snapshotEntry
+	writeBlock := func() error {
+		buf = appendSnapshotBlockHeader(buf[:0], block, count)
+		_, err := bw.Write(buf)
+		if err == nil {
+			_, err = bw.Write(block)
+		}
+		block, count = block[:0], 0
+		return err
+	}
+	c.rangeNodes(func(_ int, node *recordNode[K, V]) bool {
+		f := node.freq.Load()
+		if f <= 0 || c.stale(node) {
+			return true
+		}
+		vp := node.value.Load()
+		if vp == nil {
+			return true
+		}
+
+		if entry.value, err = c.codec.Encode(entry.value[:0], *vp); err != nil {
+			return false
+		}
+		entry.key = append(entry.key[:0], node.fullKey()...)
+		entry.freq = uint64(f)
+		entry.expires = uint64(c.deadline(node))
+		block, body = appendSnapshotEntry(block, body, flags, &entry)
+		if count++; len(block) >= snapshotBlockSize {
+			err = writeBlock()
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	block = append(block, snapshotTagEnd)
+	if err := writeBlock(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadSnapshot loads entries from a snapshot written by WriteSnapshot.
+// Entries are added to the current contents (existing keys are overwritten),
+// restoring their recorded frequency and the rest of their TTL; entries whose
+// TTL passed since the snapshot was written are dropped. Returns the number of
+// entries loaded.
+//
+// A damaged snapshot still restores what it can: blocks that fail their checksum
+// are skipped, as is a missing tail, and the entries they held are logged as lost
+// instead of failing the load.
+func (c *CloxCache[K, V]) ReadSnapshot(r io.Reader) (int, error) {
+	loaded, err := c.readSnapshot(r, c.restore, nil)
+	if err != nil {
+		c.logWarn("snapshot load failed", "loaded", loaded, "error", err)
+	}
+	return loaded, err
+}
+
+// restore stores an entry read from a snapshot or export with its frequency and
+// the TTL it has left (0 for none)
+func (c *CloxCache[K, V]) restore(key K, value V, freq int32, ttl time.Duration) bool {
+	if !c.put(key, value, freq, nil) {
+		return false
+	}
+	if ttl > 0 {
+		c.SetTTL(key, ttl)
+	}
+	return true
+}
+
+// readSnapshot decodes a snapshot, passing each entry to store with the TTL it has
+// left (0 for none) and skipping entries that expired. Keys are passed through
+// ownKey, since they alias a buffer that is reused for the next entry. progress, if set, is called
+// after each entry and once at the end with the running counts, lost being the
+// entries in corrupt blocks that were skipped.
+func (c *CloxCache[K, V]) readSnapshot(r io.Reader, store func(key K, value V, freq int32, ttl time.Duration) bool,
+	progress func(loaded, dropped, lost int)) (int, error) {
+	if c.closed.Load() {
+		return 0, ErrClosed
+	}
+	if c.codec == nil {
+		return 0, ErrNoCodec
+	}
+
+	sr, err := newSnapshotReader(r)
+	if err != nil {
+		return 0, err
+	}
+
+	loaded, dropped := 0, 0
+	defer func() {
+		if dropped > 0 {
+			c.logDebug("dropped expired snapshot entries", "dropped", dropped)
+		}
+		if sr.corrupt > 0 {
+			c.logWarn("skipped corrupt snapshot blocks", "blocks", sr.corrupt, "lost", sr.lost, "loaded", loaded)
+		}
+		if progress != nil {
+			progress(loaded, dropped, sr.lost)
+		}
+	}()
+	var entry snapshotEntry
+	for {
+		ok, err := sr.next(&entry)
+		if !ok || err != nil {
+			return loaded, err
+		}
+		ttl, live := remainingTTL(int64(entry.expires))
+		if !live {
+			dropped++
+		} else {
+			value, err := c.codec.Decode(entry.value)
+			if err != nil {
+				return loaded, fmt.Errorf("cache: decoding value for key %q: %w", entry.key, err)
+			}
+			if store(c.ownKey(K(entry.key)), value, clampFreq(entry.freq), ttl) {
+				loaded++
+			}
+		}
+		if progress != nil {
+			progress(loaded, dropped, sr.lost)
+		}
+	}
+}
+
+// clampFreq converts a stored frequency into a valid live frequency
+func clampFreq(freq uint64) int32 {
+	if freq < initialFreq {
+		return initialFreq
+	}
+	if freq > maxFrequency {
+		return maxFrequency
+	}
+	return int32(freq)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the snapshot format
+func (c *CloxCache[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.WriteSnapshot(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using the snapshot format.
+// The cache must already be constructed with NewCloxCache; entries are added to
+// its current contents.
+func (c *CloxCache[K, V]) UnmarshalBinary(data []byte) error {
+	_, err := c.ReadSnapshot(bytes.NewReader(data))
+	return err
+}
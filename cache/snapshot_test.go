@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*CloxCache[string, string])(nil)
+	_ encoding.BinaryUnmarshaler = (*CloxCache[string, string])(nil)
+)
+
+func TestCloxCacheSnapshotRoundTrip(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	src := NewCloxCache[string, []byte](cfg)
+	defer src.Close()
+
+	const numKeys = 50
+	for i := range numKeys {
+		src.Put(fmt.Sprintf("key-%d", i), fmt.Appendf(nil, "value-%d", i))
+	}
+	for range 5 {
+		src.Get("key-7")
+	}
+
+	var buf bytes.Buffer
+	if err := src.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+
+	dst := NewCloxCache[string, []byte](cfg)
+	defer dst.Close()
+	n, err := dst.ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	if n != numKeys {
+		t.Fatalf("Expected %d entries loaded, got %d", numKeys, n)
+	}
+
+	for i := range numKeys {
+		key := fmt.Sprintf("key-%d", i)
+		got, ok := dst.Get(key)
+		if !ok || string(got) != fmt.Sprintf("value-%d", i) {
+			t.Errorf("Get(%q) = %q, %v after restore", key, got, ok)
+		}
+	}
+
+	// Frequency survives the round trip (6 = initial + 5 hits, +1 from the Get above)
+	_, slot := dst.locate(hashKey("key-7"))
+	if f := slot.Load(); f == nil {
+		t.Fatal("key-7 missing from slot")
+	}
+	for node := slot.Load(); node != nil; node = node.next.Load() {
+		if node.key == "key-7" && node.freq.Load() != 7 {
+			t.Errorf("Expected restored freq 7 for key-7, got %d", node.freq.Load())
+		}
+	}
+}
+
+func TestCloxCacheMarshalBinary(t *testing.T) {
+	type Record struct {
+		Name string
+	}
+
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+	src := NewCloxCache(cfg, WithCodec[string](JSONCodec[Record]{}))
+	defer src.Close()
+	src.Put("a", Record{Name: "alpha"})
+	src.Put("b", Record{Name: "beta"})
+
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	dst := NewCloxCache(cfg, WithCodec[string](JSONCodec[Record]{}))
+	defer dst.Close()
+	if err := dst.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if got, ok := dst.Get("b"); !ok || got.Name != "beta" {
+		t.Errorf("Get(b) = %+v, %v after unmarshal", got, ok)
+	}
+}
+
+func TestCloxCacheSnapshotErrors(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64}
+
+	noCodec := NewCloxCache(cfg, WithCodec[string, int](nil))
+	defer noCodec.Close()
+	if _, err := noCodec.MarshalBinary(); !errors.Is(err, ErrNoCodec) {
+		t.Errorf("Expected ErrNoCodec, got %v", err)
+	}
+
+	c := NewCloxCache[string, string](cfg)
+	defer c.Close()
+	c.Put("key", "value")
+	data, _ := c.MarshalBinary()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", []byte("NOPE\x01\x00")},
+		{"bad version", []byte("CLOX\x63\x00")},
+		{"truncated header", data[:5]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := NewCloxCache[string, string](cfg)
+			defer dst.Close()
+			if err := dst.UnmarshalBinary(tt.data); !errors.Is(err, ErrInvalidSnapshot) {
+				t.Errorf("Expected ErrInvalidSnapshot, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloxCacheSnapshotTTL(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, TTLResolution: 100 * time.Millisecond}
+	src := NewCloxCache[string, string](cfg)
+	defer src.Close()
+	src.PutWithTTL("session", "long", time.Hour)
+	src.PutWithTTL("flash", "short", 150*time.Millisecond)
+	src.Put("forever", "none")
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	// The deadlines are absolute, so entries that expired meanwhile are dropped
+	time.Sleep(300 * time.Millisecond)
+	dst := NewCloxCache[string, string](cfg)
+	defer dst.Close()
+	if n, err := dst.ReadSnapshot(bytes.NewReader(data)); err != nil || n != 2 {
+		t.Fatalf("ReadSnapshot = %d, %v; want 2 entries", n, err)
+	}
+	if _, ok := dst.Get("flash"); ok {
+		t.Error("Expired entry was resurrected")
+	}
+	if ttl, ok := dst.TTL("session"); !ok || ttl < 59*time.Minute || ttl > time.Hour+time.Second {
+		t.Errorf("TTL(session) = %v, %v after restore; want about an hour", ttl, ok)
+	}
+	if ttl, ok := dst.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("TTL(forever) = %v, %v after restore; want 0, true", ttl, ok)
+	}
+
+	// MergeSnapshot keeps the TTLs too
+	merged := NewCloxCache[string, string](cfg)
+	defer merged.Close()
+	if n, err := merged.MergeSnapshot(bytes.NewReader(data), nil); err != nil || n != 2 {
+		t.Fatalf("MergeSnapshot = %d, %v; want 2 entries", n, err)
+	}
+	if ttl, ok := merged.TTL("session"); !ok || ttl < 59*time.Minute {
+		t.Errorf("TTL(session) = %v, %v after merge; want about an hour", ttl, ok)
+	}
+}
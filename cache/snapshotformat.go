@@ -0,0 +1,409 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+// Snapshot format (all integers are unsigned varints):
+//
+//	header:  "CLOX" version(1 byte) flags
+//	record:  tag bodyLen body
+//	entry:   body of a tagEntry record: keyLen key freq [expires] valueLen value
+//	trailer: tagEnd
+//
+// expires is the absolute time the entry's TTL ends, in Unix nanoseconds (0 for
+// none), present when flags has snapshotFlagTTL. Only live entries are written;
+// ghosts are rebuilt naturally after restore.
+//
+// The format is versioned so cache files survive library upgrades: a decoder for
+// every earlier version is kept (see readEntryV1), and files from a newer version
+// are rejected with ErrInvalidSnapshot rather than misread. Within version 3,
+// writers can extend the format without breaking older readers by adding record
+// tags or appending fields to an entry body, which readers skip when they don't
+// know them. Changes older readers can't skip get a feature flag instead: a reader
+// rejects snapshots with flags it doesn't know.
+//
+// With snapshotFlagBlocks (set by every current writer) the records are grouped
+// into checksummed blocks of about 64KB, and the trailer goes in the last one:
+//
+//	block: "CLXB" payloadLen(8 bytes) count(4 bytes) payloadCRC(4 bytes) headerCRC(4 bytes) payload
+//
+// Fixed-size fields are little-endian, count is the number of entries in the
+// payload, and the checksums are CRC-32C, headerCRC covering the three fields
+// before it. A block whose payload fails its checksum is skipped whole, and one
+// whose header does is skipped by scanning for the next "CLXB", so a damaged
+// file still restores every intact block (see snapshotReader.lost).
+//
+// Earlier versions had no flags and no record lengths:
+//
+//	version 1 entry: tagEntry keyLen key freq valueLen value
+//	version 2 entry: tagEntry keyLen key freq expires valueLen value
+const (
+	snapshotMagic   = "CLOX"
+	snapshotVersion = 3
+
+	snapshotTagEnd   = 0
+	snapshotTagEntry = 1
+
+	// snapshotFlagTTL marks entries that carry an expires field
+	snapshotFlagTTL = 1 << 0
+	// snapshotFlagBlocks marks records grouped into checksummed blocks
+	snapshotFlagBlocks = 1 << 1
+	// snapshotFlagsKnown holds every flag this version can read
+	snapshotFlagsKnown = snapshotFlagTTL | snapshotFlagBlocks
+
+	snapshotBlockMarker = "CLXB"
+	// snapshotBlockHeaderSize is the size of a block header, marker included
+	snapshotBlockHeaderSize = len(snapshotBlockMarker) + 8 + 4 + 4 + 4
+	// snapshotBlockSize is the payload size writers close a block at
+	snapshotBlockSize = 64 << 10
+
+	// maxSnapshotFieldSize bounds key/value lengths. It only rejects lengths no
+	// writer produces (values aren't capped, so it can't be lower); readSized's
+	// chunked growth is what keeps a corrupt length from allocating more than
+	// the input actually holds.
+	maxSnapshotFieldSize = 1 << 31
+	// maxSnapshotRecordSize bounds a record body: a key and a value plus varints
+	maxSnapshotRecordSize = 2*maxSnapshotFieldSize + 64
+)
+
+// snapshotCRC is the CRC-32C table block checksums use
+var snapshotCRC = crc32.MakeTable(crc32.Castagnoli)
+
+// snapshotEntry is a decoded entry. key and value alias the reader's buffers,
+// which are reused for the next entry.
+type snapshotEntry struct {
+	key, value []byte
+	freq       uint64
+	expires    uint64
+}
+
+// appendSnapshotHeader appends the header of a current-version snapshot
+func appendSnapshotHeader(buf []byte, flags uint64) []byte {
+	buf = append(buf, snapshotMagic...)
+	buf = append(buf, snapshotVersion)
+	return binary.AppendUvarint(buf, flags)
+}
+
+// appendSnapshotEntry appends an entry record; body is scratch space for its body
+func appendSnapshotEntry(buf, body []byte, flags uint64, e *snapshotEntry) (out, scratch []byte) {
+	body = binary.AppendUvarint(body[:0], uint64(len(e.key)))
+	body = append(body, e.key...)
+	body = binary.AppendUvarint(body, e.freq)
+	if flags&snapshotFlagTTL != 0 {
+		body = binary.AppendUvarint(body, e.expires)
+	}
+	body = binary.AppendUvarint(body, uint64(len(e.value)))
+	body = append(body, e.value...)
+
+	buf = append(buf, snapshotTagEntry)
+	buf = binary.AppendUvarint(buf, uint64(len(body)))
+	return append(buf, body...), body
+}
+
+// appendSnapshotBlockHeader appends the header of a block holding payload, which
+// has count entries
+func appendSnapshotBlockHeader(buf, payload []byte, count int) []byte {
+	buf = append(buf, snapshotBlockMarker...)
+	start := len(buf)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(payload)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(count))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(payload, snapshotCRC))
+	return binary.LittleEndian.AppendUint32(buf, crc32.Checksum(buf[start:], snapshotCRC))
+}
+
+// snapshotReader decodes the entries of a snapshot of any supported version
+type snapshotReader struct {
+	br      *bufio.Reader
+	version byte
+	flags   uint64
+	body    []byte // current record body (version 3)
+
+	// With snapshotFlagBlocks
+	block    []byte // unread records of the current block
+	blockBuf []byte
+	skipped  bool // the last block read was corrupt
+
+	corrupt int // blocks skipped as corrupt, counting a missing tail as one
+	lost    int // entries the corrupt blocks said they held (unknown for damaged headers)
+}
+
+// newSnapshotReader reads and checks the snapshot header
+func newSnapshotReader(r io.Reader) (*snapshotReader, error) {
+	s := &snapshotReader{br: bufio.NewReader(r)}
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(s.br, header); err != nil {
+		return nil, fmt.Errorf("%w: reading header: %v", ErrInvalidSnapshot, err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidSnapshot)
+	}
+	s.version = header[len(snapshotMagic)]
+	switch s.version {
+	case 1:
+	case 2:
+		s.flags = snapshotFlagTTL
+	case 3:
+		flags, err := binary.ReadUvarint(s.br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading flags: %v", ErrInvalidSnapshot, err)
+		}
+		if unknown := flags &^ snapshotFlagsKnown; unknown != 0 {
+			return nil, fmt.Errorf("%w: unsupported feature flags %#x", ErrInvalidSnapshot, unknown)
+		}
+		s.flags = flags
+	default:
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, s.version)
+	}
+	return s, nil
+}
+
+// next decodes the next entry into e. Returns false at the trailer.
+func (s *snapshotReader) next(e *snapshotEntry) (bool, error) {
+	if s.version < 3 {
+		return s.readEntryV1(e)
+	}
+	if s.flags&snapshotFlagBlocks != 0 {
+		return s.nextInBlock(e)
+	}
+	for {
+		tag, err := s.br.ReadByte()
+		if err != nil {
+			return false, fmt.Errorf("%w: missing trailer: %v", ErrInvalidSnapshot, err)
+		}
+		if tag == snapshotTagEnd {
+			return false, nil
+		}
+		n, err := binary.ReadUvarint(s.br)
+		if err != nil {
+			return false, fmt.Errorf("%w: reading record length: %v", ErrInvalidSnapshot, err)
+		}
+		if n > maxSnapshotRecordSize {
+			return false, fmt.Errorf("%w: record length %d too large", ErrInvalidSnapshot, n)
+		}
+		if tag != snapshotTagEntry {
+			// A record type added after this version
+			if _, err := s.br.Discard(int(n)); err != nil {
+				return false, fmt.Errorf("%w: truncated record: %v", ErrInvalidSnapshot, err)
+			}
+			continue
+		}
+		if s.body, err = readSized(s.br, s.body, n); err != nil {
+			return false, fmt.Errorf("%w: truncated data: %v", ErrInvalidSnapshot, err)
+		}
+		return true, s.parseEntry(e)
+	}
+}
+
+// nextInBlock is next for snapshots made of checksummed blocks. Corrupt blocks are
+// skipped, so the only errors are damaged records inside a block that passed its
+// checksum, which a writer bug would take.
+func (s *snapshotReader) nextInBlock(e *snapshotEntry) (bool, error) {
+	for {
+		if len(s.block) == 0 {
+			if ok, err := s.readBlock(); !ok {
+				return false, err
+			}
+			continue
+		}
+		tag := s.block[0]
+		if tag == snapshotTagEnd {
+			return false, nil
+		}
+		n, size := binary.Uvarint(s.block[1:])
+		if size <= 0 || n > uint64(len(s.block)-1-size) {
+			return false, fmt.Errorf("%w: bad record in block", ErrInvalidSnapshot)
+		}
+		body := s.block[1+size : 1+size+int(n)]
+		s.block = s.block[1+size+int(n):]
+		if tag != snapshotTagEntry {
+			continue // a record type added after this version
+		}
+		s.body = body
+		return true, s.parseEntry(e)
+	}
+}
+
+// readBlock reads the next intact block into s.block, skipping corrupt ones.
+// Returns false once the input ends, which without a trailer means it was cut
+// short, or with the error reading it failed with.
+func (s *snapshotReader) readBlock() (bool, error) {
+	for {
+		header, err := s.br.Peek(snapshotBlockHeaderSize)
+		if err != nil {
+			if err != io.EOF {
+				return false, err
+			}
+			if len(header) > 0 || !s.skipped {
+				s.corrupt++ // the tail is missing
+			}
+			return false, nil
+		}
+		fields := header[len(snapshotBlockMarker):]
+		n := binary.LittleEndian.Uint64(fields)
+		count := binary.LittleEndian.Uint32(fields[8:])
+		sum := binary.LittleEndian.Uint32(fields[12:])
+		if string(header[:len(snapshotBlockMarker)]) != snapshotBlockMarker ||
+			crc32.Checksum(fields[:16], snapshotCRC) != binary.LittleEndian.Uint32(fields[16:]) ||
+			n > maxSnapshotRecordSize {
+			// The length can't be trusted: look for the next block
+			s.skip(0)
+			if ok, err := s.resync(); !ok {
+				return false, err
+			}
+			continue
+		}
+		s.br.Discard(snapshotBlockHeaderSize)
+
+		s.blockBuf, err = readSized(s.br, s.blockBuf, n)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return false, err
+		}
+		if err != nil || crc32.Checksum(s.blockBuf, snapshotCRC) != sum {
+			s.skip(int(count))
+			if err != nil {
+				return false, nil
+			}
+			continue
+		}
+		s.block, s.skipped = s.blockBuf, false
+		return true, nil
+	}
+}
+
+// skip records a corrupt block that held count entries
+func (s *snapshotReader) skip(count int) {
+	s.corrupt++
+	s.lost += count
+	s.skipped = true
+}
+
+// resync advances past the start of a damaged block to the next block marker.
+// Returns false if the input ends first, with the error if reading failed.
+func (s *snapshotReader) resync() (bool, error) {
+	s.br.Discard(1)
+	for {
+		buf, err := s.br.Peek(s.br.Size())
+		if i := bytes.Index(buf, []byte(snapshotBlockMarker)); i >= 0 {
+			s.br.Discard(i)
+			return true, nil
+		}
+		if err != nil {
+			s.br.Discard(len(buf))
+			if err == io.EOF {
+				err = nil
+			}
+			return false, err
+		}
+		// Keep a partial marker at the end of the buffer
+		s.br.Discard(len(buf) - len(snapshotBlockMarker) + 1)
+	}
+}
+
+// parseEntry decodes a version 3 entry body, ignoring fields appended after it
+func (s *snapshotReader) parseEntry(e *snapshotEntry) error {
+	body := s.body
+	field := func() ([]byte, error) {
+		n, size := binary.Uvarint(body)
+		if size <= 0 || n > uint64(len(body)-size) {
+			return nil, fmt.Errorf("%w: bad field in entry", ErrInvalidSnapshot)
+		}
+		b := body[size : size+int(n)]
+		body = body[size+int(n):]
+		return b, nil
+	}
+	number := func() (uint64, error) {
+		n, size := binary.Uvarint(body)
+		if size <= 0 {
+			return 0, fmt.Errorf("%w: bad number in entry", ErrInvalidSnapshot)
+		}
+		body = body[size:]
+		return n, nil
+	}
+
+	var err error
+	if e.key, err = field(); err != nil {
+		return err
+	}
+	if e.freq, err = number(); err != nil {
+		return err
+	}
+	e.expires = 0
+	if s.flags&snapshotFlagTTL != 0 {
+		if e.expires, err = number(); err != nil {
+			return err
+		}
+	}
+	e.value, err = field()
+	return err
+}
+
+// readEntryV1 decodes an entry of the versions without record lengths (1 and 2)
+func (s *snapshotReader) readEntryV1(e *snapshotEntry) (bool, error) {
+	tag, err := s.br.ReadByte()
+	if err != nil {
+		return false, fmt.Errorf("%w: missing trailer: %v", ErrInvalidSnapshot, err)
+	}
+	switch tag {
+	case snapshotTagEnd:
+		return false, nil
+	case snapshotTagEntry:
+	default:
+		return false, fmt.Errorf("%w: unknown record tag %d", ErrInvalidSnapshot, tag)
+	}
+
+	if e.key, err = readSnapshotBytes(s.br, e.key); err != nil {
+		return false, err
+	}
+	if e.freq, err = binary.ReadUvarint(s.br); err != nil {
+		return false, fmt.Errorf("%w: reading freq: %v", ErrInvalidSnapshot, err)
+	}
+	e.expires = 0
+	if s.flags&snapshotFlagTTL != 0 {
+		if e.expires, err = binary.ReadUvarint(s.br); err != nil {
+			return false, fmt.Errorf("%w: reading expiry: %v", ErrInvalidSnapshot, err)
+		}
+	}
+	if e.value, err = readSnapshotBytes(s.br, e.value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readSnapshotBytes reads a length-prefixed byte string, reusing buf when possible
+func readSnapshotBytes(br *bufio.Reader, buf []byte) ([]byte, error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return buf, fmt.Errorf("%w: reading length: %v", ErrInvalidSnapshot, err)
+	}
+	if n > maxSnapshotFieldSize {
+		return buf, fmt.Errorf("%w: length %d too large", ErrInvalidSnapshot, n)
+	}
+	if buf, err = readSized(br, buf, n); err != nil {
+		return buf, fmt.Errorf("%w: truncated data: %v", ErrInvalidSnapshot, err)
+	}
+	return buf, nil
+}
+
+// readSized reads n bytes into buf, growing it as the data arrives so a corrupt
+// length can't allocate much more than the input holds
+func readSized(br *bufio.Reader, buf []byte, n uint64) ([]byte, error) {
+	const chunk = 1 << 20
+	buf = buf[:0]
+	for uint64(len(buf)) < n {
+		size := int(min(n-uint64(len(buf)), chunk))
+		buf = slices.Grow(buf, size)
+		read, err := io.ReadFull(br, buf[len(buf):len(buf)+size])
+		buf = buf[:len(buf)+read]
+		if err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
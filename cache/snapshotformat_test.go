@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheSnapshotOlderVersions(t *testing.T) {
+	deadline := binary.AppendUvarint(nil, uint64(time.Now().Add(time.Hour).UnixNano()))
+	tests := []struct {
+		name    string
+		data    []byte
+		wantTTL bool
+	}{
+		// Version 1 entries have no expiry
+		{"version 1", []byte("CLOX\x01\x01\x03key\x05\x03old\x00"), false},
+		{"version 2", fmt.Appendf(nil, "CLOX\x02\x01\x03key\x05%s\x03old\x00", deadline), true},
+		{"version 2 without TTL", []byte("CLOX\x02\x01\x03key\x05\x00\x03old\x00"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 64})
+			defer cache.Close()
+			if n, err := cache.ReadSnapshot(bytes.NewReader(tt.data)); err != nil || n != 1 {
+				t.Fatalf("ReadSnapshot = %d, %v; want 1 entry", n, err)
+			}
+			if v, ok := cache.Get("key"); !ok || v != "old" {
+				t.Errorf("Get(key) = %q, %v; want old", v, ok)
+			}
+			if ttl, _ := cache.TTL("key"); (ttl > 0) != tt.wantTTL {
+				t.Errorf("TTL(key) = %v, want a TTL: %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestCloxCacheSnapshotForwardCompatible(t *testing.T) {
+	// A newer writer added a record type and appended a field to entries
+	data := appendSnapshotHeader(nil, 0)
+	data = append(data, 9, 4, 'n', 'e', 'w', '!')
+	data = append(data, snapshotTagEntry, 10, 3, 'k', 'e', 'y', 5, 3, 'n', 'e', 'w', 42)
+	data = append(data, snapshotTagEnd)
+
+	cache := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 64})
+	defer cache.Close()
+	if n, err := cache.ReadSnapshot(bytes.NewReader(data)); err != nil || n != 1 {
+		t.Fatalf("ReadSnapshot = %d, %v; want 1 entry", n, err)
+	}
+	if v, ok := cache.Get("key"); !ok || v != "new" {
+		t.Errorf("Get(key) = %q, %v; want new", v, ok)
+	}
+
+	// Flags announce changes that can't be skipped
+	flagged := appendSnapshotHeader(nil, 1<<5)
+	flagged = append(flagged, snapshotTagEnd)
+	if _, err := cache.ReadSnapshot(bytes.NewReader(flagged)); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("Unknown flags: got %v, want ErrInvalidSnapshot", err)
+	}
+	if _, err := cache.ReadSnapshot(bytes.NewReader([]byte("CLOX\x04\x00\x00"))); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("Newer version: got %v, want ErrInvalidSnapshot", err)
+	}
+}
+
+func TestCloxCacheSnapshotCorruptBlocks(t *testing.T) {
+	cfg := Config{NumShards: 4, SlotsPerShard: 256, Capacity: 1024}
+	src := NewCloxCache[string, string](cfg)
+	for i := range 400 {
+		src.Put(fmt.Sprintf("key-%d", i), strings.Repeat("v", 1000))
+	}
+	data, err := src.MarshalBinary()
+	src.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Values never contain the marker, so this finds every block
+	var blocks []int
+	for i := 0; ; i++ {
+		j := bytes.Index(data[i:], []byte(snapshotBlockMarker))
+		if j < 0 {
+			break
+		}
+		i += j
+		blocks = append(blocks, i)
+	}
+	if len(blocks) < 4 {
+		t.Fatalf("Snapshot has %d blocks, want several", len(blocks))
+	}
+	count := func(block int) int {
+		return int(binary.LittleEndian.Uint32(data[blocks[block]+len(snapshotBlockMarker)+8:]))
+	}
+	damaged := func(damage func(data []byte) []byte) []byte {
+		return damage(bytes.Clone(data))
+	}
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     int
+		wantLost int
+	}{
+		{"intact", data, 400, 0},
+		{"bad payload", damaged(func(d []byte) []byte {
+			d[blocks[1]+snapshotBlockHeaderSize+10] ^= 0xff
+			return d
+		}), 400 - count(1), count(1)},
+		// A damaged length can't be trusted, so the reader scans for the next block
+		{"bad header", damaged(func(d []byte) []byte {
+			d[blocks[2]+len(snapshotBlockMarker)] ^= 0xff
+			return d
+		}), 400 - count(2), 0},
+		{"truncated", damaged(func(d []byte) []byte {
+			return d[:len(d)-100]
+		}), 400 - count(len(blocks)-1), count(len(blocks) - 1)},
+		{"missing blocks", damaged(func(d []byte) []byte {
+			return d[:blocks[len(blocks)-1]]
+		}), 400 - count(len(blocks)-1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewCloxCache[string, string](cfg)
+			defer cache.Close()
+			var last LoadProgress
+			n, err := cache.LoadFrom(bytes.NewReader(tt.data), func(p LoadProgress) { last = p })()
+			if err != nil || n != tt.want {
+				t.Errorf("LoadFrom = %d, %v; want %d entries", n, err, tt.want)
+			}
+			if last.Lost != tt.wantLost {
+				t.Errorf("Lost = %d, want %d", last.Lost, tt.wantLost)
+			}
+			if v, ok := cache.Get("key-0"); ok && v != strings.Repeat("v", 1000) {
+				t.Error("A restored value is damaged")
+			}
+		})
+	}
+}
+
+func FuzzReadSnapshot(f *testing.F) {
+	src := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 64})
+	src.Put("a", "1")
+	src.PutWithTTL("b", "2", time.Hour)
+	data, _ := src.MarshalBinary()
+	src.Close()
+
+	f.Add(data)
+	f.Add([]byte("CLOX\x01\x01\x03key\x05\x03old\x00"))
+	f.Add([]byte("CLOX\x02\x01\x03key\x05\x00\x03old\x00"))
+	f.Add([]byte("CLOX\x03\x00\x09\x01!\x00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cache := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 64})
+		defer cache.Close()
+		n, err := cache.ReadSnapshot(bytes.NewReader(data))
+		if err != nil && !errors.Is(err, ErrInvalidSnapshot) {
+			t.Fatalf("ReadSnapshot error %v is not ErrInvalidSnapshot", err)
+		}
+		if n < 0 || n > len(data) {
+			t.Fatalf("ReadSnapshot loaded %d entries from %d bytes", n, len(data))
+		}
+		if report := cache.VerifyIntegrity(); !report.OK() {
+			t.Fatalf("Integrity issues: %v", report.Issues)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package cache
+
+// PutSoft stores value like Put and marks the entry soft: nice-to-have data that
+// may be dropped before anything else. Eviction scans take soft entries (oldest
+// first) ahead of every other candidate, including when a TargetHitRate governor
+// shrinks the cache, and ReleaseSoft drops them all at once.
+//
+// The mark sticks to the key until it is removed from the cache (it survives
+// plain Puts and ghosting), like a priority.
+func (c *CloxCache[K, V]) PutSoft(key K, value V) bool {
+	if !c.Put(key, value) {
+		return false
+	}
+	c.SetSoft(key, true)
+	return true
+}
+
+// SetSoft marks or unmarks a live or ghost key as soft (see PutSoft).
+// Returns false if the key is not in the cache.
+func (c *CloxCache[K, V]) SetSoft(key K, soft bool) bool {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		node.soft.Store(soft)
+		return true
+	}
+	return false
+}
+
+// IsSoft reports whether a live or ghost key is marked soft
+func (c *CloxCache[K, V]) IsSoft(key K) bool {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	if node := c.findNode(shard, hash, hi, key, nil); node != nil {
+		return node.soft.Load()
+	}
+	return false
+}
+
+// ReleaseSoft removes every live soft entry and returns how many were dropped,
+// keeping the must-keep data. WithMemoryPressure calls it when memory use nears
+// the Go memory limit (GOMEMLIMIT); call it directly for other signals. OnEvict
+// reports the entries with EvictReasonReleased. A no-op once the cache is closed.
+func (c *CloxCache[K, V]) ReleaseSoft() int {
+	if c.closed.Load() {
+		return 0
+	}
+	released := 0
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		slots := shard.slots()
+		for s := range slots {
+			slot := &slots[s]
+			var prev *recordNode[K, V]
+			for node := slot.Load(); node != nil; {
+				next := node.next.Load()
+				if node.soft.Load() && node.freq.Load() > 0 {
+					c.removeLocked(shard, slot, prev, node, EvictReasonReleased)
+					released++
+				} else {
+					prev = node
+				}
+				node = next
+			}
+		}
+		shard.mu.Unlock()
+	}
+	if released > 0 {
+		c.logDebug("released soft entries", "count", released)
+	}
+	return released
+}
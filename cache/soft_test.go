@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"runtime/debug"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheSoftEvictedFirst(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	cache.PutSoft("soft", 0)
+	for range 10 {
+		cache.Get("soft") // frequent, but still droppable
+	}
+	for i := range 7 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if !cache.IsSoft("soft") {
+		t.Fatal("Expected soft to be marked soft")
+	}
+
+	cache.Put("incoming", 1)
+	if _, ok := cache.Get("soft"); ok {
+		t.Error("Soft entry survived while must-keep entries were candidates")
+	}
+	for i := range 7 {
+		if _, ok := cache.Get(fmt.Sprintf("key-%d", i)); !ok {
+			t.Errorf("key-%d evicted ahead of the soft entry", i)
+		}
+	}
+}
+
+func TestCloxCacheReleaseSoft(t *testing.T) {
+	cfg := Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64}
+	var released int
+	cache := NewCloxCache(cfg, WithHooks(Hooks[string, int]{
+		OnEvict: func(_ string, _ int, reason EvictReason) {
+			if reason == EvictReasonReleased {
+				released++
+			}
+		},
+	}))
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.PutSoft(fmt.Sprintf("soft-%d", i), i)
+		cache.Put(fmt.Sprintf("keep-%d", i), i)
+	}
+	cache.SetSoft("soft-0", false)
+
+	if n := cache.ReleaseSoft(); n != 9 || released != 9 {
+		t.Errorf("ReleaseSoft = %d (OnEvict %d), want 9", n, released)
+	}
+	if _, ok := cache.Get("soft-0"); !ok {
+		t.Error("Unmarked entry was released")
+	}
+	for i := range 10 {
+		if _, ok := cache.Get(fmt.Sprintf("keep-%d", i)); !ok {
+			t.Errorf("keep-%d was released", i)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues after ReleaseSoft: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheMemoryPressure(t *testing.T) {
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(math.MaxInt64))
+
+	var released atomic.Bool
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64},
+		WithMemoryPressure[string, int](time.Millisecond, 0.9),
+		WithHooks(Hooks[string, int]{
+			OnEvict: func(key string, _ int, reason EvictReason) {
+				if reason == EvictReasonReleased {
+					released.Store(true)
+				}
+			},
+		}))
+	defer cache.Close()
+
+	cache.PutSoft("soft", 1)
+	cache.Put("hard", 2)
+
+	// No memory limit, no pressure
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("soft"); !ok {
+		t.Fatal("Soft entry released without a memory limit")
+	}
+
+	// A limit below what the process already uses
+	debug.SetMemoryLimit(1)
+	deadline := time.Now().Add(2 * time.Second)
+	for !released.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	debug.SetMemoryLimit(math.MaxInt64)
+	if _, ok := cache.Get("soft"); ok {
+		t.Error("Soft entry survived memory pressure")
+	}
+	if _, ok := cache.Get("hard"); !ok {
+		t.Error("Memory pressure released a regular entry")
+	}
+}
@@ -0,0 +1,120 @@
+package cache
+
+import "time"
+
+// StatsSnapshot is a point-in-time reading of the cache counters.
+// Take one periodically and use Delta to derive per-interval rates.
+type StatsSnapshot struct {
+	Time      time.Time
+	Hits      uint64 // lifetime hits (always collected)
+	Misses    uint64 // lifetime misses (always collected)
+	Evictions uint64 // lifetime full evictions (requires CollectStats)
+	Ghosted   uint64 // lifetime live entries converted to ghosts (always collected)
+	Rejected  uint64 // lifetime Puts that didn't store their value (always collected)
+	Entries   int64  // live entries at snapshot time
+	Ghosts    int64  // ghost entries at snapshot time
+	// TTLs (see PutWithTTL): lifetime entries reclaimed after their TTL passed
+	// (always collected), entries past their TTL that weren't reclaimed yet, and
+	// live entries with a TTL and their average time left
+	Expired      uint64
+	ExpiredHeld  int64
+	TTLEntries   int64
+	RemainingTTL time.Duration
+}
+
+// StatsSnapshot reads the current counters
+func (c *CloxCache[K, V]) StatsSnapshot() StatsSnapshot {
+	snap := StatsSnapshot{
+		Time:      time.Now(),
+		Evictions: c.evictions.Load(),
+	}
+	var bucketSum uint64
+	for i := range c.shards {
+		shard := &c.shards[i]
+		hits, ops := shard.counters()
+		snap.Hits += hits
+		snap.Misses += ops - hits
+		snap.Ghosted += shard.ghosted.Load()
+		for reason := range shard.rejects {
+			snap.Rejected += shard.rejects[reason].Load()
+		}
+		snap.Entries += shard.entryCount.Load()
+		snap.Ghosts += shard.ghostCount.Load()
+		snap.Expired += shard.ttlReclaimed.Load()
+		snap.ExpiredHeld += shard.ttlExpired.Load()
+		snap.TTLEntries += shard.ttlLive.Load()
+		bucketSum += shard.ttlLiveSum.Load()
+	}
+	if snap.TTLEntries > 0 {
+		// Entries expire at the end of their bucket, so the average end is that of the average bucket
+		end := c.ttlBase + int64(float64(bucketSum)/float64(snap.TTLEntries)*float64(c.ttlResolution()))
+		snap.RemainingTTL = max(time.Duration(end-snap.Time.UnixNano()), 0)
+	}
+	return snap
+}
+
+// StatsDelta holds the counter changes between two snapshots
+type StatsDelta struct {
+	Interval  time.Duration
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Ghosted   uint64
+	Rejected  uint64
+	Expired   uint64
+}
+
+// Delta returns the change in counters since prev. Counter subtraction is modular,
+// so a counter that wrapped around between the snapshots still yields the right delta.
+func (s StatsSnapshot) Delta(prev StatsSnapshot) StatsDelta {
+	return StatsDelta{
+		Interval:  s.Time.Sub(prev.Time),
+		Hits:      s.Hits - prev.Hits,
+		Misses:    s.Misses - prev.Misses,
+		Evictions: s.Evictions - prev.Evictions,
+		Ghosted:   s.Ghosted - prev.Ghosted,
+		Rejected:  s.Rejected - prev.Rejected,
+		Expired:   s.Expired - prev.Expired,
+	}
+}
+
+// Requests returns the number of Gets in the interval
+func (d StatsDelta) Requests() uint64 {
+	return d.Hits + d.Misses
+}
+
+// HitRatio returns hits / (hits + misses), or 0 when there were no Gets
+func (d StatsDelta) HitRatio() float64 {
+	if total := d.Requests(); total > 0 {
+		return float64(d.Hits) / float64(total)
+	}
+	return 0
+}
+
+// RequestsPerSecond returns the Get rate over the interval
+func (d StatsDelta) RequestsPerSecond() float64 {
+	return d.perSecond(d.Requests())
+}
+
+// EvictionsPerSecond returns the full eviction rate over the interval
+func (d StatsDelta) EvictionsPerSecond() float64 {
+	return d.perSecond(d.Evictions)
+}
+
+// GhostedPerSecond returns the rate at which live entries became ghosts
+func (d StatsDelta) GhostedPerSecond() float64 {
+	return d.perSecond(d.Ghosted)
+}
+
+// ExpirationsPerSecond returns the rate at which entries were reclaimed after
+// their TTL passed
+func (d StatsDelta) ExpirationsPerSecond() float64 {
+	return d.perSecond(d.Expired)
+}
+
+func (d StatsDelta) perSecond(n uint64) float64 {
+	if d.Interval <= 0 {
+		return 0
+	}
+	return float64(n) / d.Interval.Seconds()
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheStatsSnapshotDelta(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, CollectStats: true})
+	defer cache.Close()
+
+	prev := cache.StatsSnapshot()
+	for i := range 10 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	for i := range 20 {
+		cache.Get(fmt.Sprintf("key-%d", i)) // 10 hits, 10 misses
+	}
+	cur := cache.StatsSnapshot()
+
+	if cur.Entries != 10 {
+		t.Errorf("Expected 10 entries, got %d", cur.Entries)
+	}
+
+	delta := cur.Delta(prev)
+	if delta.Hits != 10 || delta.Misses != 10 {
+		t.Errorf("Delta hits=%d misses=%d, want 10/10", delta.Hits, delta.Misses)
+	}
+	if delta.HitRatio() != 0.5 {
+		t.Errorf("HitRatio = %v, want 0.5", delta.HitRatio())
+	}
+	if delta.Interval <= 0 {
+		t.Errorf("Expected a positive interval, got %v", delta.Interval)
+	}
+}
+
+func TestStatsDeltaRates(t *testing.T) {
+	start := time.Unix(1000, 0)
+	prev := StatsSnapshot{Time: start, Hits: math.MaxUint64 - 4, Misses: 10, Evictions: 100}
+	cur := StatsSnapshot{Time: start.Add(2 * time.Second), Hits: 5, Misses: 20, Evictions: 140}
+
+	delta := cur.Delta(prev)
+	// Hits wrapped around: 5 increments to MaxUint64, then 5 more from zero
+	if delta.Hits != 10 {
+		t.Errorf("Wraparound delta = %d, want 10", delta.Hits)
+	}
+	if got := delta.RequestsPerSecond(); got != 10 {
+		t.Errorf("RequestsPerSecond = %v, want 10", got)
+	}
+	if got := delta.EvictionsPerSecond(); got != 20 {
+		t.Errorf("EvictionsPerSecond = %v, want 20", got)
+	}
+
+	var empty StatsDelta
+	if empty.HitRatio() != 0 || empty.EvictionsPerSecond() != 0 {
+		t.Error("Empty delta should report zero rates")
+	}
+}
+
+func TestCloxCacheStatsTTL(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64})
+	defer cache.Close()
+
+	for i := range 10 {
+		cache.PutWithTTL(fmt.Sprintf("short-%d", i), i, time.Minute)
+		cache.PutWithTTL(fmt.Sprintf("long-%d", i), i, 3*time.Minute)
+	}
+	cache.Put("forever", 1)
+
+	prev := cache.StatsSnapshot()
+	if prev.TTLEntries != 20 {
+		t.Errorf("TTLEntries = %d, want 20", prev.TTLEntries)
+	}
+	// Half expire in about a minute, half in three, rounded up to a second
+	if prev.RemainingTTL < 119*time.Second || prev.RemainingTTL > 122*time.Second {
+		t.Errorf("RemainingTTL = %v, want about 2m", prev.RemainingTTL)
+	}
+
+	// Freeze keeps the reaper away, so the expired entries are held
+	cache.Freeze()
+	cache.advanceTTL(time.Now().Add(90 * time.Second))
+	held := cache.StatsSnapshot()
+	if held.TTLEntries != 10 || held.ExpiredHeld != 10 || held.Expired != 0 {
+		t.Errorf("TTLEntries, ExpiredHeld, Expired = %d, %d, %d; want 10, 10, 0",
+			held.TTLEntries, held.ExpiredHeld, held.Expired)
+	}
+	cache.Thaw()
+	cache.advanceTTL(time.Now().Add(100 * time.Second))
+
+	cur := cache.StatsSnapshot()
+	if cur.Expired != 10 || cur.ExpiredHeld != 0 {
+		t.Errorf("Expired, ExpiredHeld = %d, %d after the reaper ran; want 10, 0", cur.Expired, cur.ExpiredHeld)
+	}
+	if delta := cur.Delta(prev); delta.Expired != 10 || delta.ExpirationsPerSecond() <= 0 {
+		t.Errorf("Delta expired %d at %v/s, want 10 at a positive rate", delta.Expired, delta.ExpirationsPerSecond())
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SnapshotStore is where snapshots are persisted: a local file (see
+// FileSnapshotStore), an object store bucket, or anything else that can hold one
+// blob. It is what warm starts (LoadFromStore) and periodic persistence
+// (WithSnapshotStore) read and write, so a stateless container can pull its warm
+// cache from object storage on boot. An S3 or GCS store maps Create to an upload
+// that only becomes visible when completed, such as a multipart upload or a
+// resumable write, and Open to a streaming download.
+type SnapshotStore interface {
+	// Create starts a new snapshot. It must not replace the stored snapshot
+	// until the writer's Commit succeeds.
+	Create(ctx context.Context) (SnapshotWriter, error)
+	// Open returns a reader for the stored snapshot, or an error wrapping
+	// fs.ErrNotExist if there is none yet.
+	Open(ctx context.Context) (io.ReadCloser, error)
+}
+
+// SnapshotWriter receives a snapshot for a SnapshotStore
+type SnapshotWriter interface {
+	io.Writer
+	// Commit makes the written snapshot the stored one
+	Commit() error
+	// Abort discards a snapshot that failed midway, keeping the stored one
+	Abort() error
+}
+
+// SaveSnapshot writes a snapshot to store like WriteSnapshotContext, committing it
+// only if it was written completely
+func (c *CloxCache[K, V]) SaveSnapshot(ctx context.Context, store SnapshotStore, opts SnapshotOptions) error {
+	w, err := store.Create(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.WriteSnapshotContext(ctx, w, opts); err != nil {
+		return errors.Join(err, w.Abort())
+	}
+	return w.Commit()
+}
+
+// LoadFromStore restores the snapshot in store in the background like LoadFrom.
+// A store without a snapshot yet is a cold start: the load finishes at once with
+// no entries and no error.
+func (c *CloxCache[K, V]) LoadFromStore(ctx context.Context, store SnapshotStore, progress func(LoadProgress)) (wait func() (int, error)) {
+	r, err := store.Open(ctx)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		return func() (int, error) { return 0, err }
+	}
+	return c.loadFrom(r, progress, func() { r.Close() })
+}
+
+// WithSnapshotStore persists the cache to store: every interval (unless it is 0)
+// with opts pacing the write, and a final time on Close like WithSnapshotOnClose.
+// Failed periodic saves are logged and leave the stored snapshot as it was.
+// Clone reapplies options, so clones persist to the same store. Requires a codec
+// for V.
+func WithSnapshotStore[K Key, V any](store SnapshotStore, interval time.Duration, opts SnapshotOptions) Option[K, V] {
+	return func(c *CloxCache[K, V]) {
+		c.snapshotStore = store
+		c.persistEvery = interval
+		c.persistOptions = opts
+	}
+}
+
+// runPersist starts the goroutine that saves periodic snapshots until Close.
+// Caller must hold the lifecycle lock (or be New).
+func (c *CloxCache[K, V]) runPersist() {
+	stop := c.stop
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.persistEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			// Close cancels a save in progress so the final one doesn't wait for it.
+			// Publishing cancel before checking closed means either Close sees it or
+			// this sees Close.
+			ctx, cancel := context.WithCancel(context.Background())
+			c.persistCancel.Store(&cancel)
+			if !c.closed.Load() {
+				if err := c.persist(ctx, c.persistOptions); err != nil && ctx.Err() == nil {
+					c.logWarn("periodic snapshot failed", "error", err)
+				}
+			}
+			c.persistCancel.Store(nil)
+			cancel()
+		}
+	}()
+}
+
+// persist saves a snapshot to the store, one save at a time
+func (c *CloxCache[K, V]) persist(ctx context.Context, opts SnapshotOptions) error {
+	c.persistMu.Lock()
+	defer c.persistMu.Unlock()
+	return c.SaveSnapshot(ctx, c.snapshotStore, opts)
+}
+
+// FileSnapshotStore keeps the snapshot in a local file. New snapshots are written
+// to a temporary file next to it and renamed over it on Commit, so readers never
+// see a partial snapshot and a crash mid-write keeps the previous one.
+type FileSnapshotStore struct {
+	Path string
+}
+
+// Create implements SnapshotStore
+func (s FileSnapshotStore) Create(context.Context) (SnapshotWriter, error) {
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &fileSnapshotWriter{File: f, path: s.Path}, nil
+}
+
+// Open implements SnapshotStore
+func (s FileSnapshotStore) Open(context.Context) (io.ReadCloser, error) {
+	return os.Open(s.Path)
+}
+
+// fileSnapshotWriter is a temporary file that becomes the snapshot on Commit
+type fileSnapshotWriter struct {
+	*os.File
+	path string
+}
+
+func (w *fileSnapshotWriter) Commit() error {
+	err := w.Sync()
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = os.Rename(w.Name(), w.path)
+	}
+	if err != nil {
+		return errors.Join(err, w.Abort())
+	}
+	return nil
+}
+
+func (w *fileSnapshotWriter) Abort() error {
+	w.Close()
+	return os.Remove(w.Name())
+}
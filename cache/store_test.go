@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCloxCacheFileSnapshotStore(t *testing.T) {
+	dir := t.TempDir()
+	store := FileSnapshotStore{Path: filepath.Join(dir, "cache.snapshot")}
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128}
+
+	// No snapshot yet is a cold start
+	cold := NewCloxCache[string, int](cfg)
+	defer cold.Close()
+	if n, err := cold.LoadFromStore(context.Background(), store, nil)(); n != 0 || err != nil {
+		t.Errorf("LoadFromStore without a snapshot = %d, %v; want 0, nil", n, err)
+	}
+
+	src := NewCloxCache[string, int](cfg)
+	defer src.Close()
+	for i := range 10 {
+		src.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	if err := src.SaveSnapshot(context.Background(), store, SnapshotOptions{}); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	// A failed save keeps the stored snapshot and leaves no temporary file behind
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	src.Put("late", 11)
+	if err := src.SaveSnapshot(ctx, store, SnapshotOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("SaveSnapshot with a canceled ctx = %v, want context.Canceled", err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("%d files in the store directory, want only the snapshot", len(files))
+	}
+
+	dst := NewCloxCache[string, int](cfg)
+	defer dst.Close()
+	if n, err := dst.LoadFromStore(context.Background(), store, nil)(); n != 10 || err != nil {
+		t.Fatalf("LoadFromStore = %d, %v; want 10 entries", n, err)
+	}
+	if _, ok := dst.Get("late"); ok {
+		t.Error("The failed save replaced the stored snapshot")
+	}
+}
+
+func TestCloxCacheWithSnapshotStore(t *testing.T) {
+	store := FileSnapshotStore{Path: filepath.Join(t.TempDir(), "cache.snapshot")}
+	cfg := Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128}
+	cache := NewCloxCache(cfg, WithSnapshotStore[string, int](store, 10*time.Millisecond, SnapshotOptions{}))
+	cache.Put("a", 1)
+
+	// Periodic saves
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(store.Path); err == nil {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := os.Stat(store.Path); err != nil {
+		t.Fatalf("No periodic snapshot: %v", err)
+	}
+
+	// And a final one on Close
+	cache.Put("b", 2)
+	cache.Close()
+	restored := NewCloxCache[string, int](cfg)
+	defer restored.Close()
+	if n, err := restored.LoadFromStore(context.Background(), store, nil)(); n != 2 || err != nil {
+		t.Fatalf("LoadFromStore = %d, %v; want both entries", n, err)
+	}
+
+	// Saves resume after Reopen
+	os.Remove(store.Path)
+	cache.Reopen()
+	defer cache.Close()
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(store.Path); err == nil {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("No periodic snapshot after Reopen")
+}
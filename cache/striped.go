@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+)
+
+// Striped statistics counters.
+//
+// The CollectStats counters are bumped on every Get, so a single atomic per
+// counter is one cache line every core writes. A stripedCounter spreads the adds
+// over one padded stripe per P and sums them on read. Go doesn't expose which P
+// a goroutine runs on, so each add picks a stripe with the runtime's per-thread
+// random source, which needs no shared state: concurrent adds land on different
+// lines nearly always, and reads (Stats, StatsSnapshot) are rare enough to sum.
+
+// maxCounterStripes caps the stripes per counter (each takes a cache line)
+const maxCounterStripes = 64
+
+// counterStripe is one cache line holding part of a counter's count
+type counterStripe struct {
+	n atomic.Uint64
+	_ [56]byte
+}
+
+// stripedCounter is a uint64 counter for frequent adds from many goroutines
+type stripedCounter struct {
+	stripes []counterStripe
+	mask    uint32
+}
+
+// newStripedCounter returns a counter with a stripe per P, rounded up to a power of two
+func newStripedCounter() stripedCounter {
+	n := min(1<<bits.Len(uint(runtime.GOMAXPROCS(0)-1)), maxCounterStripes)
+	return stripedCounter{stripes: make([]counterStripe, n), mask: uint32(n - 1)}
+}
+
+// Add adds delta to the counter
+func (s *stripedCounter) Add(delta uint64) {
+	var i uint32
+	if s.mask != 0 {
+		i = rand.Uint32() & s.mask
+	}
+	s.stripes[i].n.Add(delta)
+}
+
+// Load returns the counter's value; adds racing with it may or may not be included
+func (s *stripedCounter) Load() uint64 {
+	var total uint64
+	for i := range s.stripes {
+		total += s.stripes[i].n.Load()
+	}
+	return total
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+)
+
+func TestStripedCounter(t *testing.T) {
+	counter := newStripedCounter()
+	if n := len(counter.stripes); n&(n-1) != 0 || n > maxCounterStripes || n < min(runtime.GOMAXPROCS(0), maxCounterStripes) {
+		t.Errorf("%d stripes for GOMAXPROCS %d", n, runtime.GOMAXPROCS(0))
+	}
+	if size := unsafe.Sizeof(counterStripe{}); size != cacheLineSize {
+		t.Errorf("counterStripe is %d bytes, want a cache line", size)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10000 {
+				counter.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	counter.Add(5)
+	if n := counter.Load(); n != 80005 {
+		t.Errorf("Load() = %d, want 80005", n)
+	}
+}
+
+func TestCloxCacheStatsStriped(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 128, CollectStats: true})
+	defer cache.Close()
+	cache.Put("a", 1)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 500 {
+				cache.Get("a")
+				cache.Get("b")
+			}
+		}()
+	}
+	wg.Wait()
+	if hits, misses, _ := cache.Stats(); hits != 2000 || misses != 2000 {
+		t.Errorf("Stats() = %d hits, %d misses; want 2000 each", hits, misses)
+	}
+}
+
+// BenchmarkStatsCounter compares a striped counter against a single atomic under parallel adds
+func BenchmarkStatsCounter(b *testing.B) {
+	b.Run("atomic", func(b *testing.B) {
+		var counter atomic.Uint64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				counter.Add(1)
+			}
+		})
+	})
+	b.Run("striped", func(b *testing.B) {
+		counter := newStripedCounter()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				counter.Add(1)
+			}
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package cache
+
+// Adaptive scan width (Config.AdaptiveSweep).
+//
+// Each shard starts scanning SweepPercent of its slots per eviction and tunes
+// that width from its victim quality (see victim.go): after every
+// sweepAdaptEvictions evictions, a mean score below sweepWidenQuality doubles the
+// width, since the window keeps missing unprotected entries, and a mean at or
+// above sweepNarrowQuality narrows it by a quarter, since a smaller window found
+// victims as good. Widths are kept in hundredths of a percent of the shard's
+// slots, so they follow the slot table when it grows, and never drop below one slot.
+
+const (
+	// sweepAdaptEvictions is how many evictions each width adjustment is based on
+	sweepAdaptEvictions = 64
+	// sweepWidenQuality - mean victim quality below which the scan widens
+	sweepWidenQuality = 0.95
+	// sweepNarrowQuality - mean victim quality at or above which the scan narrows
+	sweepNarrowQuality = 0.99
+	// sweepFullWidth is a scan of every slot, in the units of shard.scanWidth
+	sweepFullWidth = 10000
+)
+
+// adaptSweep records the score of an eviction from shard and adjusts its scan
+// width once enough evictions were scored. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) adaptSweep(shardID int, shard *shard[K, V], score uint64, slotsPerShard int) {
+	scores := shard.sweepScores.Add(score)
+	if shard.sweepScored.Add(1) < sweepAdaptEvictions {
+		return
+	}
+	shard.sweepScores.Store(0)
+	shard.sweepScored.Store(0)
+
+	quality := float64(scores) / sweepAdaptEvictions / victimScoreScale
+	width := shard.scanWidth.Load()
+	switch {
+	case quality < sweepWidenQuality:
+		width = min(width*2, sweepFullWidth)
+	case quality >= sweepNarrowQuality:
+		width = max(width*3/4, int32(sweepFullWidth/slotsPerShard), 1)
+	default:
+		return
+	}
+	if old := shard.scanWidth.Swap(width); old != width {
+		c.logDebug("adapted eviction scan width",
+			"shard", shardID, "victim_quality", quality,
+			"old_percent", float64(old)/100, "new_percent", float64(width)/100)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sweepWorkload fills a single-shard cache, reads the first hot entries often
+// enough to protect them and streams new keys through it; returns the shard's
+// scan width afterwards
+func sweepWorkload(t *testing.T, sweepPercent, hot int) int32 {
+	cache := NewCloxCache[string, int](Config{
+		NumShards: 1, SlotsPerShard: 1024, Capacity: 256, SweepPercent: sweepPercent, AdaptiveSweep: true,
+	})
+	defer cache.Close()
+
+	for i := range 256 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	for range 8 {
+		for i := range hot {
+			cache.Get(fmt.Sprintf("key-%d", i))
+		}
+	}
+	for i := range 20 * sweepAdaptEvictions {
+		cache.Put(fmt.Sprintf("new-%d", i), i)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+	if slots := cache.GetAdaptiveStats()[0].ScanSlots; slots != cache.scanLength(&cache.shards[0], 1024) {
+		t.Errorf("ScanSlots = %d, want the shard's scan length", slots)
+	}
+	return cache.shards[0].scanWidth.Load()
+}
+
+func TestCloxCacheAdaptiveSweep(t *testing.T) {
+	// Most entries are protected: a 1% window keeps forcing them out and widens
+	if width := sweepWorkload(t, 1, 240); width <= 100 {
+		t.Errorf("Scan width = %d after forced protected evictions, want above the initial 100", width)
+	}
+	// Nothing is protected: every window finds the oldest entry, so a 50% scan narrows
+	if width := sweepWorkload(t, 50, 0); width >= 5000 {
+		t.Errorf("Scan width = %d with perfect victims, want below the initial 5000", width)
+	}
+}
+
+func TestAdaptSweepBounds(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, AdaptiveSweep: true, SweepPercent: 100})
+	defer cache.Close()
+	shard := &cache.shards[0]
+
+	for range 4 * sweepAdaptEvictions {
+		cache.adaptSweep(0, shard, 0, 64)
+	}
+	if width := shard.scanWidth.Load(); width != sweepFullWidth {
+		t.Errorf("Width = %d after poor victims at full width, want %d", width, sweepFullWidth)
+	}
+	for range 100 * sweepAdaptEvictions {
+		cache.adaptSweep(0, shard, victimScoreScale, 64)
+	}
+	if width := shard.scanWidth.Load(); width != sweepFullWidth/64 {
+		t.Errorf("Width = %d after perfect victims, want one slot (%d)", width, sweepFullWidth/64)
+	}
+	if n := cache.scanLength(shard, 64); n != 1 {
+		t.Errorf("scanLength = %d at the narrowest width, want 1", n)
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// throttleChunk is the most a throttled writer passes on at once, so a large
+// entry is spread over time instead of sent in one burst after a long wait
+const throttleChunk = 32 << 10
+
+// throttledWriter fails writes once its context is done and, with a rate, paces
+// them to that many bytes per second on average. The first chunk goes out at
+// once; later ones wait until the average allows them.
+type throttledWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	rate    int64 // bytes per second (0 = unlimited)
+	start   time.Time
+	written int64
+}
+
+func newThrottledWriter(ctx context.Context, w io.Writer, rate int64) *throttledWriter {
+	return &throttledWriter{ctx: ctx, w: w, rate: max(rate, 0), start: time.Now()}
+}
+
+func (t *throttledWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if err := t.ctx.Err(); err != nil {
+			return n, err
+		}
+		chunk := p
+		if t.rate > 0 {
+			chunk = p[:min(len(p), throttleChunk)]
+			if err := t.wait(len(chunk)); err != nil {
+				return n, err
+			}
+		}
+		m, err := t.w.Write(chunk)
+		n += m
+		t.written += int64(m)
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// wait sleeps until sending size more bytes keeps within the rate
+func (t *throttledWriter) wait(size int) error {
+	ahead := t.written + int64(size) - throttleChunk
+	due := t.start.Add(time.Duration(float64(ahead) / float64(t.rate) * float64(time.Second)))
+	d := time.Until(due)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
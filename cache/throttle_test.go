@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThrottledWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newThrottledWriter(context.Background(), &buf, 1<<20)
+	data := bytes.Repeat([]byte("x"), throttleChunk+256<<10)
+
+	start := time.Now()
+	if n, err := w.Write(data); err != nil || n != len(data) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	// The first chunk is free, the rest paced at 1MB/s
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Writing 256KB past the first chunk at 1MB/s took %v, want about 250ms", elapsed)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("Written data differs")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := newThrottledWriter(ctx, &buf, 0).Write(data); !errors.Is(err, context.Canceled) {
+		t.Errorf("Write after cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestCloxCacheWriteSnapshotContext(t *testing.T) {
+	cache := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 256, Capacity: 512})
+	defer cache.Close()
+	value := strings.Repeat("v", 1<<10)
+	for i := range 200 {
+		cache.Put(fmt.Sprintf("key-%d", i), value)
+	}
+
+	var buf bytes.Buffer
+	start := time.Now()
+	if err := cache.WriteSnapshotContext(context.Background(), &buf, SnapshotOptions{BytesPerSecond: 1 << 20}); err != nil {
+		t.Fatalf("WriteSnapshotContext: %v", err)
+	}
+	if floor := time.Duration(float64(buf.Len()-throttleChunk) / (1 << 20) * float64(time.Second)); time.Since(start) < floor*9/10 {
+		t.Errorf("Wrote %d bytes in %v, faster than the 1MB/s limit", buf.Len(), time.Since(start))
+	}
+	restored := NewCloxCache[string, string](Config{NumShards: 4, SlotsPerShard: 256, Capacity: 512})
+	defer restored.Close()
+	if n, err := restored.ReadSnapshot(&buf); err != nil || n != 200 {
+		t.Errorf("ReadSnapshot = %d, %v; want 200 entries", n, err)
+	}
+
+	// A slow limit gives up when ctx ends
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := cache.WriteSnapshotContext(ctx, &buf, SnapshotOptions{BytesPerSecond: 1 << 10}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteSnapshotContext = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cache
+
+import "time"
+
+// Touch extends a live key's TTL so it expires no sooner than ttl from now,
+// without rewriting its value or recording an access, for heartbeat-style
+// session renewal. TTLs are never shortened, and keys without one are left
+// alone since they don't expire. Touches that land in the key's current expiry
+// bucket return without taking a lock. Returns false if the key is not live.
+func (c *CloxCache[K, V]) Touch(key K, ttl time.Duration) bool {
+	if c.closed.Load() {
+		return false
+	}
+	target := c.ttlBucket(c.now().Add(max(ttl, 0)))
+	return c.retime(key, func(bucket uint32) uint32 {
+		if bucket == 0 {
+			return 0
+		}
+		return max(bucket, target)
+	})
+}
+
+// ExtendTTL pushes a live key's expiry back by d (rounded up to the cache's
+// TTLResolution), without rewriting its value or recording an access. Keys
+// without a TTL are left alone, as is everything for d <= 0. Returns false if
+// the key is not live.
+func (c *CloxCache[K, V]) ExtendTTL(key K, d time.Duration) bool {
+	if c.closed.Load() {
+		return false
+	}
+	return c.retime(key, func(bucket uint32) uint32 {
+		if bucket == 0 || d <= 0 {
+			return bucket
+		}
+		return c.ttlBucket(c.bucketEnd(bucket).Add(d))
+	})
+}
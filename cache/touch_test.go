@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCloxCacheTouch(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64})
+	defer cache.Close()
+
+	cache.PutWithTTL("session", 1, time.Minute)
+	cache.Put("forever", 2)
+	freq := liveFreq(cache, "session")
+
+	if !cache.Touch("session", time.Hour) {
+		t.Fatal("Touch failed for a live key")
+	}
+	if ttl, _ := cache.TTL("session"); ttl < 59*time.Minute {
+		t.Errorf("TTL = %v after Touch, want about an hour", ttl)
+	}
+	// Touch never shortens a TTL, and leaves keys without one alone
+	cache.Touch("session", time.Minute)
+	if ttl, _ := cache.TTL("session"); ttl < 59*time.Minute {
+		t.Errorf("TTL = %v after a shorter Touch, want about an hour", ttl)
+	}
+	if !cache.Touch("forever", time.Minute) {
+		t.Error("Touch failed for a key without a TTL")
+	}
+	if ttl, _ := cache.TTL("forever"); ttl != 0 {
+		t.Errorf("Touch gave a key without a TTL one: %v", ttl)
+	}
+	if f := liveFreq(cache, "session"); f != freq {
+		t.Errorf("Touch changed the frequency from %d to %d", freq, f)
+	}
+	if v, _ := cache.Get("session"); v != 1 {
+		t.Errorf("Get(session) = %d after Touch, want 1", v)
+	}
+	if cache.Touch("absent", time.Minute) {
+		t.Error("Touch succeeded for an absent key")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheTouchSameBucket(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64, TTLResolution: time.Hour})
+	defer cache.Close()
+	cache.PutWithTTL("session", 1, time.Minute)
+
+	// Heartbeats within one bucket don't refile the entry
+	wheel := cache.shards[0].ttlWheel
+	filed := func() (n int) {
+		for level := range wheel.levels {
+			for slot := range wheel.levels[level] {
+				n += len(wheel.levels[level][slot])
+			}
+		}
+		return n
+	}
+	for range 100 {
+		cache.Touch("session", time.Minute)
+		cache.SetTTL("session", time.Minute)
+	}
+	if n := filed(); n != 1 {
+		t.Errorf("Wheel holds %d entries after repeated touches, want 1", n)
+	}
+}
+
+func TestCloxCacheExtendTTL(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64})
+	defer cache.Close()
+
+	cache.PutWithTTL("session", 1, time.Minute)
+	cache.Put("forever", 2)
+	before, _ := cache.TTL("session")
+	if !cache.ExtendTTL("session", 10*time.Minute) {
+		t.Fatal("ExtendTTL failed for a live key")
+	}
+	if after, _ := cache.TTL("session"); after-before < 10*time.Minute-time.Second || after-before > 10*time.Minute+time.Second {
+		t.Errorf("TTL went from %v to %v, want 10m more", before, after)
+	}
+	cache.ExtendTTL("forever", time.Minute)
+	if ttl, _ := cache.TTL("forever"); ttl != 0 {
+		t.Errorf("ExtendTTL gave a key without a TTL one: %v", ttl)
+	}
+	if cache.ExtendTTL("absent", time.Minute) {
+		t.Error("ExtendTTL succeeded for an absent key")
+	}
+
+	cache.Close()
+	if cache.Touch("session", time.Hour) || cache.ExtendTTL("session", time.Hour) {
+		t.Error("TTL changed on a closed cache")
+	}
+}
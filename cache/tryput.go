@@ -0,0 +1,14 @@
+package cache
+
+// TryPut is Put for fire-and-forget cache fills that must not block: if the
+// key's shard lock is held (by a writer or an eviction scan), the write is
+// dropped and counted as RejectShardLocked instead of waiting, and if the shard
+// is full it is dropped as RejectNoVictim instead of running an eviction scan
+// (capacity borrowed from other shards is still used). Updates of a live key
+// normally take no lock and go through regardless. Returns false if the value
+// wasn't stored.
+func (c *CloxCache[K, V]) TryPut(key K, value V) bool {
+	hash, hi := c.hashes(key)
+	ok, _ := c.putReasonWithHash(key, hash, hi, value, &opTrace{try: true})
+	return ok
+}
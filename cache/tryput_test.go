@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCloxCacheTryPut(t *testing.T) {
+	cache := NewCloxCache[string, int](ConfigFromCapacity(100))
+	defer cache.Close()
+
+	if !cache.TryPut("a", 1) {
+		t.Fatal("TryPut on an idle shard failed")
+	}
+
+	hash, _ := cache.hashes("b")
+	shard, _ := cache.locate(hash)
+	shard.mu.Lock()
+	if cache.TryPut("b", 2) {
+		t.Error("TryPut succeeded while the shard lock was held")
+	}
+	shard.mu.Unlock()
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Dropped TryPut stored its value")
+	}
+	if n := cache.Rejections(RejectShardLocked); n != 1 {
+		t.Errorf("Rejections(%s) = %d, want 1", RejectShardLocked, n)
+	}
+
+	// Updating a live key needs no lock
+	hash, _ = cache.hashes("a")
+	shard, _ = cache.locate(hash)
+	shard.mu.Lock()
+	updated := cache.TryPut("a", 3)
+	shard.mu.Unlock()
+	if v, _ := cache.Get("a"); !updated || v != 3 {
+		t.Errorf("TryPut(a) on a locked shard = %v, then Get = %d; want true, 3", updated, v)
+	}
+
+	// A full shard rejects instead of scanning for a victim
+	for i := range 1000 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	evictions := func() (n uint64) {
+		for _, s := range cache.GetAdaptiveStats() {
+			n += s.EvictedUnprotected + s.EvictedProtected
+		}
+		return n
+	}
+	before, rejected := evictions(), cache.Rejections(RejectNoVictim)
+	if cache.TryPut("last", 1) {
+		t.Error("TryPut into a full shard succeeded")
+	}
+	if n := evictions(); n != before {
+		t.Errorf("TryPut evicted %d entries, want none", n-before)
+	}
+	if n := cache.Rejections(RejectNoVictim) - rejected; n != 1 {
+		t.Errorf("TryPut added %d %s rejections, want 1", n, RejectNoVictim)
+	}
+	if _, ok := cache.Get("last"); ok {
+		t.Error("Rejected TryPut stored its value")
+	}
+}
@@ -0,0 +1,309 @@
+package cache
+
+import (
+	"math"
+	"time"
+)
+
+// Per-entry TTLs, segmented into coarse time buckets.
+//
+// A node with a TTL records the bucket its deadline falls in: buckets are
+// Config.TTLResolution wide and numbered from the cache's creation. The cache
+// keeps one watermark, the newest bucket that has fully passed, which a
+// background clock moves forward once per bucket; a node is expired once its
+// bucket is at or below it. Expiring a whole bucket is therefore a single store,
+// and checking an entry is an integer compare on its first cache line rather than
+// a clock read. Deadlines round up to the end of their bucket, so entries live up
+// to one TTLResolution longer than asked, never shorter.
+//
+// Expired entries are handled like invalidated ones (see invalidate.go): reads
+// treat them as absent from the moment their bucket passes. Reclaiming them is a
+// hybrid. Proactively, each tick of the clock hands the passed buckets' entries
+// to a reaper through each shard's timing wheel (see wheel.go), which removes a
+// bounded batch per shard. Lazily, whoever runs into one first reclaims it: a Get
+// turns it into a ghost if the shard lock is free, so the reload that follows the
+// miss keeps the key's frequency; a Put for its key, an eviction scan (ahead of
+// any other victim, without a ghost) and DeleteFunc or ExpireFunc remove it. So
+// dead entries don't hold capacity until a sweep gets to them. AdaptiveStats and
+// ExpiryStats count both kinds.
+// Each shard counts its TTL entries per bucket, and the clock moves a bucket's
+// count to the shard's expired total when it passes, so the shard knows how
+// many dead entries it holds without looking at them.
+//
+// The clock starts with the first TTL and runs until Close.
+
+// defaultTTLResolution is used when Config.TTLResolution is unset
+const defaultTTLResolution = time.Second
+
+// ttlReapBatch is how many fired entries the reaper handles per shard and tick
+const ttlReapBatch = 1024
+
+// minTTLResolution bounds Config.TTLResolution so bucket numbers, 32 bits wide,
+// last for years (13 at this resolution, 136 at the default)
+const minTTLResolution = 100 * time.Millisecond
+
+// PutWithTTL stores value like Put and expires the entry once ttl has passed
+// (rounded up to the cache's TTLResolution). Like a priority, the TTL sticks to
+// the key across later Puts until it expires, is removed or is changed with
+// SetTTL. A ttl <= 0 stores the entry without one.
+func (c *CloxCache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) bool {
+	if !c.Put(key, value) {
+		return false
+	}
+	c.SetTTL(key, ttl)
+	return true
+}
+
+// SetTTL expires a live key once ttl has passed, replacing any earlier TTL; a
+// ttl <= 0 clears it. Returns false if the key is not live in the cache.
+func (c *CloxCache[K, V]) SetTTL(key K, ttl time.Duration) bool {
+	if c.closed.Load() {
+		return false
+	}
+	var bucket uint32
+	if ttl > 0 {
+		c.startTTLClock()
+		bucket = c.ttlBucket(c.now().Add(ttl))
+	}
+	return c.retime(key, func(uint32) uint32 { return bucket })
+}
+
+// retime moves a live key to the expiry bucket next returns for its current one
+// (0 = none). Keys whose bucket doesn't change are left alone without taking the
+// shard lock, so frequent calls within one bucket stay cheap. Returns false if
+// the key is not live.
+func (c *CloxCache[K, V]) retime(key K, next func(bucket uint32) uint32) bool {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	node := c.findNode(shard, hash, hi, key, c.liveNode)
+	if node == nil {
+		return false
+	}
+	if bucket := node.expires.Load(); next(bucket) == bucket {
+		return true
+	}
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if node = c.findNode(shard, hash, hi, key, c.liveNode); node == nil {
+		return false
+	}
+	if bucket := next(node.expires.Load()); bucket != node.expires.Load() {
+		c.ttlLeft(shard, node)
+		ttlJoined(shard, node, bucket)
+	}
+	return true
+}
+
+// TTL returns how long a live key has left before it expires, rounded up to the
+// end of its bucket, or 0 if it has no TTL. ok is false if the key is not live.
+func (c *CloxCache[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	node := c.findNode(shard, hash, hi, key, c.liveNode)
+	if node == nil {
+		return 0, false
+	}
+	if bucket := node.expires.Load(); bucket != 0 {
+		return max(c.bucketEnd(bucket).Sub(c.now()), 0), true
+	}
+	return 0, true
+}
+
+// deadline returns when a node's TTL ends in Unix nanoseconds, or 0 without one
+func (c *CloxCache[K, V]) deadline(node *recordNode[K, V]) int64 {
+	if bucket := node.expires.Load(); bucket != 0 {
+		return c.bucketEnd(bucket).UnixNano()
+	}
+	return 0
+}
+
+// remainingTTL returns the time left until a persisted deadline (0 for none) and
+// whether it is still in the future
+func remainingTTL(deadline int64) (ttl time.Duration, live bool) {
+	if deadline == 0 {
+		return 0, true
+	}
+	ttl = time.Until(time.Unix(0, deadline))
+	return ttl, ttl > 0
+}
+
+// ttlResolution returns the width of an expiry bucket
+func (c *CloxCache[K, V]) ttlResolution() time.Duration {
+	if c.config.TTLResolution > 0 {
+		return c.config.TTLResolution
+	}
+	return defaultTTLResolution
+}
+
+// ttlBucket returns the bucket a deadline falls in (buckets start at 1, so 0 means no TTL)
+func (c *CloxCache[K, V]) ttlBucket(deadline time.Time) uint32 {
+	res := int64(c.ttlResolution())
+	n := (deadline.UnixNano() - c.ttlBase + res - 1) / res
+	return uint32(min(max(n, 1), math.MaxUint32))
+}
+
+// bucketEnd returns when bucket ends, which is when its entries expire
+func (c *CloxCache[K, V]) bucketEnd(bucket uint32) time.Time {
+	return time.Unix(0, c.ttlBase+int64(bucket)*int64(c.ttlResolution()))
+}
+
+// expired reports whether a node's TTL has passed
+func (c *CloxCache[K, V]) expired(node *recordNode[K, V]) bool {
+	bucket := node.expires.Load()
+	return bucket != 0 && bucket <= c.ttlClock.Load()
+}
+
+// ttlJoined gives a node the expiry bucket and counts it in its shard (a no-op
+// for bucket 0). Caller must hold the shard lock.
+func ttlJoined[K Key, V any](shard *shard[K, V], node *recordNode[K, V], bucket uint32) {
+	if bucket == 0 {
+		return
+	}
+	node.expires.Store(bucket)
+	if shard.ttlWheel == nil {
+		shard.ttlWheel = newTimingWheel[K, V](shard.ttlSwept)
+	}
+	shard.ttlWheel.add(node, bucket)
+	if bucket <= shard.ttlSwept {
+		shard.ttlExpired.Add(1) // passed while the caller waited for the lock
+		return
+	}
+	shard.ttlCount(bucket, 1)
+}
+
+// ttlCount adds delta entries to the count of a bucket that hasn't passed,
+// keeping the shard's lock-free totals in step. Caller must hold the shard lock.
+func (s *shard[K, V]) ttlCount(bucket uint32, delta int64) {
+	if s.ttlBuckets == nil {
+		s.ttlBuckets = make(map[uint32]int64)
+	}
+	if s.ttlBuckets[bucket] += delta; s.ttlBuckets[bucket] == 0 {
+		delete(s.ttlBuckets, bucket)
+	}
+	s.ttlLive.Add(delta)
+	s.ttlLiveSum.Add(uint64(delta * int64(bucket))) // wraps for negative deltas
+}
+
+// ttlLeft drops a node's TTL from its shard's bucket counts as it leaves the live
+// set or gets a new TTL. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) ttlLeft(shard *shard[K, V], node *recordNode[K, V]) {
+	bucket := node.expires.Swap(0)
+	if bucket != 0 && bucket <= c.ttlClock.Load() {
+		shard.ttlReclaimed.Add(1)
+	}
+	switch {
+	case bucket == 0:
+	case bucket <= shard.ttlSwept:
+		shard.ttlExpired.Add(-1)
+	default:
+		shard.ttlCount(bucket, -1)
+	}
+}
+
+// expireOnRead reclaims an expired node a Get ran into, unless the shard lock is
+// busy or the cache is frozen: it becomes a ghost if there is room, and is
+// removed otherwise
+func (c *CloxCache[K, V]) expireOnRead(shard *shard[K, V], node *recordNode[K, V]) {
+	if c.frozen.Load() || !shard.mu.TryLock() {
+		return
+	}
+	defer shard.mu.Unlock()
+	if node.freq.Load() <= 0 || c.staleReason(node) != EvictReasonExpired || !c.expired(node) {
+		return
+	}
+	if shard.ghostCount.Load() < shard.ghostCapacity {
+		c.preserve(shard, node.keyHash)
+		key := node.fullKey()
+		c.finalize(shard, key, c.ghostLocked(shard, node, EvictReasonExpired))
+		c.dropOverflow(key)
+		return
+	}
+	c.unlinkLocked(shard, node, EvictReasonExpired)
+}
+
+// ExpiryStats returns how many entries were reclaimed after their TTL passed,
+// lazily by the operations that ran into them and proactively by the clock
+func (c *CloxCache[K, V]) ExpiryStats() (lazily, proactively uint64) {
+	for i := range c.shards {
+		reaped := c.shards[i].ttlReaped.Load()
+		lazily += c.shards[i].ttlReclaimed.Load() - reaped
+		proactively += reaped
+	}
+	return lazily, proactively
+}
+
+// startTTLClock starts the goroutine that advances the expiry watermark, once
+func (c *CloxCache[K, V]) startTTLClock() {
+	if c.ttlClockOn.Load() {
+		return
+	}
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if !c.ttlClockOn.Load() && !c.closed.Load() {
+		c.ttlClockOn.Store(true)
+		c.runTTLClock()
+	}
+}
+
+// runTTLClock advances the watermark at every bucket boundary until the cache
+// is closed. Deterministic caches advance it on Tick instead. Caller must hold
+// the lifecycle lock.
+func (c *CloxCache[K, V]) runTTLClock() {
+	if c.clock != nil {
+		return
+	}
+	stop := c.stop
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.ttlResolution())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				c.advanceTTL(now)
+			}
+		}
+	}()
+}
+
+// advanceTTL expires the buckets that ended by now: it raises the watermark,
+// moves each shard's counts for those buckets to its expired total and has the
+// shard's reaper remove a batch of their entries
+func (c *CloxCache[K, V]) advanceTTL(now time.Time) {
+	clock := uint32(min(max((now.UnixNano()-c.ttlBase)/int64(c.ttlResolution()), 0), math.MaxUint32))
+	if clock <= c.ttlClock.Load() {
+		return
+	}
+	c.ttlClock.Store(clock)
+	for i := range c.shards {
+		shard := &c.shards[i]
+		shard.mu.Lock()
+		if passed := clock - shard.ttlSwept; int64(passed) <= int64(len(shard.ttlBuckets)) {
+			for bucket := uint64(shard.ttlSwept) + 1; bucket <= uint64(clock); bucket++ {
+				if n := shard.ttlBuckets[uint32(bucket)]; n != 0 {
+					shard.ttlExpired.Add(n)
+					shard.ttlCount(uint32(bucket), -n)
+				}
+			}
+		} else {
+			// More buckets passed than the shard holds (the first tick, or a stalled clock)
+			for bucket, n := range shard.ttlBuckets {
+				if bucket <= clock {
+					shard.ttlExpired.Add(n)
+					shard.ttlCount(bucket, -n)
+				}
+			}
+		}
+		shard.ttlSwept = clock
+		// Frozen shards keep their expired entries until Thaw (reads already miss them)
+		if shard.ttlWheel != nil && !c.frozen.Load() {
+			shard.ttlWheel.advance(clock)
+			shard.ttlWheel.reap(ttlReapBatch, func(node *recordNode[K, V]) { c.reapLocked(shard, node) })
+		}
+		shard.mu.Unlock()
+	}
+}
@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// live reports whether key is live without recording an access
+func live(cache *CloxCache[string, int], key string) bool {
+	_, ok := cache.TTL(key)
+	return ok
+}
+
+func TestCloxCachePutWithTTL(t *testing.T) {
+	var mu sync.Mutex
+	var expired []string
+	cache := NewCloxCache[string, int](Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64, TTLResolution: 100 * time.Millisecond},
+		WithHooks(Hooks[string, int]{OnEvict: func(key string, _ int, reason EvictReason) {
+			if reason == EvictReasonExpired {
+				mu.Lock()
+				expired = append(expired, key)
+				mu.Unlock()
+			}
+		}}))
+	defer cache.Close()
+
+	cache.PutWithTTL("session", 1, 150*time.Millisecond)
+	cache.Put("forever", 2)
+	if v, ok := cache.Get("session"); !ok || v != 1 {
+		t.Fatalf("Get(session) = %d, %v before its TTL passed", v, ok)
+	}
+	if ttl, ok := cache.TTL("session"); !ok || ttl <= 0 || ttl > 250*time.Millisecond {
+		t.Errorf("TTL(session) = %v, %v; want up to 150ms rounded up to a bucket", ttl, ok)
+	}
+	if ttl, ok := cache.TTL("forever"); !ok || ttl != 0 {
+		t.Errorf("TTL(forever) = %v, %v; want 0, true", ttl, ok)
+	}
+
+	// Plain Puts keep the TTL
+	cache.Put("session", 3)
+	deadline := time.Now().Add(2 * time.Second)
+	for live(cache, "session") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := cache.Get("session"); ok {
+		t.Fatal("Entry still served after its TTL passed")
+	}
+	if _, ok := cache.TTL("session"); ok {
+		t.Error("TTL reports an expired entry")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("Entry without a TTL expired")
+	}
+
+	// The expiry is reported once, whether the clock or a Put reclaims the entry,
+	// and a Put stores one without a TTL
+	cache.Put("session", 4)
+	mu.Lock()
+	if !slices.Equal(expired, []string{"session"}) {
+		t.Errorf("OnEvict reported %v as expired, want [session]", expired)
+	}
+	mu.Unlock()
+	if ttl, ok := cache.TTL("session"); !ok || ttl != 0 {
+		t.Errorf("TTL(session) = %v, %v after a fresh Put; want 0, true", ttl, ok)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheTTLBuckets(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 64})
+	defer cache.Close()
+	shard := &cache.shards[0]
+
+	for i := range 10 {
+		cache.PutWithTTL(fmt.Sprintf("short-%d", i), i, time.Minute)
+		cache.PutWithTTL(fmt.Sprintf("long-%d", i), i, time.Hour)
+	}
+	if n := len(shard.ttlBuckets); n != 2 && n != 3 && n != 4 {
+		t.Errorf("%d buckets for two TTLs, want one or two each", n)
+	}
+
+	// Passing the first TTL expires its buckets as a whole, and the wheel removes them
+	cache.advanceTTL(time.Now().Add(2 * time.Minute))
+	if n := shard.ttlExpired.Load(); n != 0 {
+		t.Errorf("ttlExpired = %d after the wheel ran, want 0", n)
+	}
+	if n := shard.entryCount.Load(); n != 10 {
+		t.Errorf("entryCount = %d, want the 10 entries with a later TTL", n)
+	}
+	if _, ok := cache.Get("short-3"); ok {
+		t.Error("Expired entry was served")
+	}
+	if _, ok := cache.Get("long-3"); !ok {
+		t.Error("Entry with a later TTL was not served")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+
+	// Clearing and deleting leave the counts consistent
+	cache.SetTTL("long-1", 0)
+	cache.Delete("long-2")
+	if n := shard.ttlExpired.Load(); n != 0 {
+		t.Errorf("ttlExpired = %d, want 0", n)
+	}
+	if cache.SetTTL("absent", time.Second) {
+		t.Error("SetTTL succeeded for an absent key")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheTTLEviction(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 64, Capacity: 8, SweepPercent: 100})
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.PutWithTTL(fmt.Sprintf("old-%d", i), i, time.Minute)
+	}
+	for range 3 {
+		for i := range 8 {
+			cache.Get(fmt.Sprintf("old-%d", i))
+		}
+	}
+	cache.advanceTTL(time.Now().Add(2 * time.Minute))
+
+	// Expired entries go first and don't become ghosts, however popular they were
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("new-%d", i), i)
+	}
+	shard := &cache.shards[0]
+	if n := shard.ghostCount.Load(); n != 0 {
+		t.Errorf("%d expired entries became ghosts", n)
+	}
+	if n := shard.ttlExpired.Load(); n != 0 {
+		t.Errorf("ttlExpired = %d after the expired entries were evicted", n)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheTTLCloneAndReopen(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 2, SlotsPerShard: 64, Capacity: 64, TTLResolution: 100 * time.Millisecond})
+	defer cache.Close()
+	cache.PutWithTTL("a", 1, time.Hour)
+
+	clone := cache.Clone(false)
+	defer clone.Close()
+	if ttl, ok := clone.TTL("a"); !ok || ttl < 59*time.Minute {
+		t.Errorf("Clone TTL(a) = %v, %v; want about an hour", ttl, ok)
+	}
+	if report := clone.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Clone integrity issues: %v", report.Issues)
+	}
+
+	// The clock keeps running after a Close/Reopen cycle
+	cache.Close()
+	cache.Reopen()
+	cache.PutWithTTL("b", 2, 100*time.Millisecond)
+	deadline := time.Now().Add(2 * time.Second)
+	for live(cache, "b") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if live(cache, "b") {
+		t.Error("Entry didn't expire after Reopen")
+	}
+}
+
+func TestCloxCacheTTLHybridExpiry(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 4096, Capacity: 2048})
+	defer cache.Close()
+	shard := &cache.shards[0]
+
+	const n = ttlReapBatch + 500
+	for i := range n {
+		cache.PutWithTTL(fmt.Sprintf("key-%d", i), i, time.Minute)
+	}
+	cache.Put("forever", 1)
+
+	// One tick reaps a batch; the rest waits for the next tick
+	start := time.Now()
+	cache.advanceTTL(start.Add(2 * time.Minute))
+	if got := shard.entryCount.Load(); got != n-ttlReapBatch+1 {
+		t.Errorf("entryCount = %d after one tick, want %d", got, n-ttlReapBatch+1)
+	}
+
+	// Reads reclaim the ones they run into, keeping their frequency as ghosts
+	for i := range n {
+		if _, ok := cache.Get(fmt.Sprintf("key-%d", i)); ok {
+			t.Fatalf("key-%d served after its TTL passed", i)
+		}
+	}
+	if got := shard.entryCount.Load(); got != 1 {
+		t.Errorf("entryCount = %d after reading the expired keys, want 1", got)
+	}
+	if got := shard.ghostCount.Load(); got != n-ttlReapBatch {
+		t.Errorf("ghostCount = %d, want the %d entries reads reclaimed", got, n-ttlReapBatch)
+	}
+	lazily, proactively := cache.ExpiryStats()
+	if lazily != n-ttlReapBatch || proactively != ttlReapBatch {
+		t.Errorf("ExpiryStats = %d, %d; want %d, %d", lazily, proactively, n-ttlReapBatch, ttlReapBatch)
+	}
+
+	// The reaper skips what reads already reclaimed
+	cache.advanceTTL(start.Add(3 * time.Minute))
+	if stats := cache.GetAdaptiveStats()[0]; stats.ExpiredLazily != lazily || stats.ExpiredProactively != proactively {
+		t.Errorf("AdaptiveStats expired %d, %d after the next tick; want %d, %d",
+			stats.ExpiredLazily, stats.ExpiredProactively, lazily, proactively)
+	}
+	if n := shard.ttlExpired.Load(); n != 0 {
+		t.Errorf("ttlExpired = %d, want 0", n)
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
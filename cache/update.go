@@ -0,0 +1,110 @@
+package cache
+
+// Update atomically replaces the value for key with fn(old, found), where found
+// reports whether key was live. It returns the stored value and false if the value
+// could not be stored because eviction failed or it exceeds MaxKeyBytes or
+// MaxValueBytes (an existing value is then left in place).
+//
+// Concurrent Puts and Updates to the same key never interleave with the
+// read-modify-write: if the value changes between reading old and storing the
+// result, fn is called again with the newer value. fn must therefore be free of
+// side effects, and must not modify old in place (readers may still hold it);
+// return a new value instead. Counts as an access like Put, and fires OnPut.
+func (c *CloxCache[K, V]) Update(key K, fn func(old V, found bool) V) (V, bool) {
+	value, ok := c.update(key, fn)
+	if ok && c.hooks != nil && c.hooks.OnPut != nil {
+		c.hooks.OnPut(key, value)
+	}
+	return value, ok
+}
+
+// updateExisting is Update restricted to live keys: it never inserts, and
+// returns false if key is not live
+func (c *CloxCache[K, V]) updateExisting(key K, fn func(old V) V) (V, bool) {
+	if c.closed.Load() {
+		var zero V
+		return zero, false
+	}
+	hash, hi := c.hashes(key)
+	shard, _ := c.locate(hash)
+	update := func(old V, _ bool) V { return fn(old) }
+	var value V
+	var ok bool
+	if c.snapshotting.Load() {
+		// A running snapshot must see the old value: write under the lock
+		shard.mu.Lock()
+		c.preserve(shard, hash)
+		value, _, ok = c.tryUpdate(shard, hash, hi, key, update)
+		shard.mu.Unlock()
+	} else {
+		value, _, ok = c.tryUpdate(shard, hash, hi, key, update)
+	}
+	if ok && c.hooks != nil && c.hooks.OnPut != nil {
+		c.hooks.OnPut(key, value)
+	}
+	return value, ok
+}
+
+func (c *CloxCache[K, V]) update(key K, fn func(old V, found bool) V) (V, bool) {
+	if c.closed.Load() {
+		var zero V
+		return zero, false
+	}
+	hash, hi := c.hashes(key)
+	shardID := hash & uint64(c.numShards-1)
+	shard, _ := c.locate(hash)
+
+	// Lock-free compare-and-swap on a live node (unless a snapshot is running)
+	if !c.snapshotting.Load() {
+		if value, found, stored := c.tryUpdate(shard, hash, hi, key, fn); found {
+			return value, stored
+		}
+	}
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	c.preserve(shard, hash)
+
+	// Inserts are serialized by the lock, so a live node can only have appeared
+	// before we took it; values may still be swapped lock-free, hence the CAS.
+	if value, found, stored := c.tryUpdate(shard, hash, hi, key, fn); found {
+		return value, stored
+	}
+
+	var zero V
+	value := fn(zero, false)
+	if c.oversized(key, value) {
+		shard.rejected(RejectOverWeight)
+		return value, false
+	}
+	newNode := c.newRecord(shard, hash, hi, key, value, initialFreq)
+	return value, c.putLocked(int(shardID), shard, newNode, classUnchanged, nil) == RejectNone
+}
+
+// tryUpdate applies fn to the live node for key with a CAS retry loop.
+// found is false if key has no live node; stored is false if fn's result was
+// rejected for exceeding MaxValueBytes, leaving the old value in place.
+func (c *CloxCache[K, V]) tryUpdate(shard *shard[K, V], hash, hi uint64, key K,
+	fn func(old V, found bool) V) (value V, found, stored bool) {
+	for {
+		node := c.findNode(shard, hash, hi, key, c.liveNode)
+		var zero V
+		if node == nil {
+			return zero, false, false
+		}
+		from := node.value.Load()
+		if from == nil {
+			return zero, false, false // ghosted concurrently
+		}
+
+		value := fn(*from, true)
+		if c.oversized(key, value) {
+			shard.rejected(RejectOverWeight)
+			return value, true, false
+		}
+		if node.value.CompareAndSwap(from, &value) {
+			c.updated(shard, node, key, from, &value)
+			return value, true, true
+		}
+	}
+}
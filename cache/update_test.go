@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCloxCacheUpdateFunc(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	v, ok := cache.Update("counter", func(old int, found bool) int {
+		if found {
+			t.Error("Expected a missing key")
+		}
+		return old + 1
+	})
+	if !ok || v != 1 {
+		t.Fatalf("Update = %d, %v; want 1, true", v, ok)
+	}
+	v, _ = cache.Update("counter", func(old int, found bool) int {
+		if !found {
+			t.Error("Expected an existing key")
+		}
+		return old + 1
+	})
+	if v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	if got, _ := cache.Get("counter"); got != 2 {
+		t.Errorf("Get after Update = %d, want 2", got)
+	}
+
+	if _, ok := cache.updateExisting("missing", func(old int) int { return old + 1 }); ok {
+		t.Error("updateExisting created a missing key")
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("updateExisting inserted a missing key")
+	}
+}
+
+func TestCloxCacheUpdateFuncConcurrent(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 64, Capacity: 100})
+	defer cache.Close()
+
+	const goroutines, increments = 8, 1000
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range increments {
+				cache.Update(fmt.Sprintf("k%d", i%4), func(old int, _ bool) int { return old + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 4 {
+		key := fmt.Sprintf("k%d", i)
+		if v, _ := cache.Get(key); v != goroutines*increments/4 {
+			t.Errorf("%s = %d, want %d (lost updates)", key, v, goroutines*increments/4)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
+
+func TestCloxCacheUpdateFuncGhost(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 16, Capacity: 4, SweepPercent: 100}
+	cache := NewCloxCache[string, int](cfg)
+	defer cache.Close()
+
+	for i := range 8 {
+		cache.Put(fmt.Sprintf("key-%d", i), i)
+	}
+	ghosts := cache.Ghosts()
+	if len(ghosts) == 0 {
+		t.Fatal("Expected ghosts")
+	}
+	key := ghosts[0].Key
+
+	v, ok := cache.Update(key, func(old int, found bool) int {
+		if found || old != 0 {
+			t.Errorf("Ghost passed as found=%v old=%d", found, old)
+		}
+		return 42
+	})
+	if !ok || v != 42 {
+		t.Errorf("Update on ghost = %d, %v", v, ok)
+	}
+	if cache.IsGhost(key) {
+		t.Error("Ghost was not promoted")
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+}
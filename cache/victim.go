@@ -0,0 +1,57 @@
+package cache
+
+// Victim quality.
+//
+// An eviction only weighs the entries in its scan window, so a window too narrow
+// for the workload often finds no entry the policy is willing to evict. Each
+// victim is scored by where its last access falls between the oldest
+// unprotected entry the scan saw (frequency at most k; every entry under GDSF,
+// HIR entries under LIRS) and now: 1 when it was that entry, lower the more
+// recently it was read. Forced evictions of protected entries score 0, as they
+// mean the window held no unprotected candidate at all.
+//
+// A low score therefore mostly means SweepPercent (or EvictionSamples) is too
+// low. Soft entries, priority classes, cost bands and AdaptiveBalance segments
+// also pick victims other than the oldest, which shows as lag with a high score.
+
+// victimScoreScale is the fixed-point scale victim scores are summed in
+const victimScoreScale = 10000
+
+// victimScoreDecay is how many scored evictions halve the older ones' weight
+const victimScoreDecay = 1024
+
+// scoreVictim records and returns the quality of a victim last accessed at
+// victimAccess when the oldest unprotected entry scanned was at minAccess, scaled
+// by victimScoreScale. Caller must hold the shard lock.
+func scoreVictim[K Key, V any](shard *shard[K, V], victimAccess, minAccess uint64, protected bool) uint64 {
+	var score, lag uint64
+	if victimAccess > minAccess {
+		lag = victimAccess - minAccess
+	}
+	if now := shard.timestamp.Load(); !protected {
+		score = victimScoreScale
+		if span := now - min(minAccess, now); span > 0 {
+			score -= min(lag, span) * victimScoreScale / span
+		}
+	}
+
+	scored := shard.victimsScored.Load() + 1
+	scores, lags := shard.victimScores.Load()+score, shard.victimLags.Load()+lag
+	if scored >= victimScoreDecay {
+		scored, scores, lags = scored/2, scores/2, lags/2
+	}
+	shard.victimsScored.Store(scored)
+	shard.victimScores.Store(scores)
+	shard.victimLags.Store(lags)
+	return score
+}
+
+// victimQuality returns the shard's average victim score and lag (0, 0 before any eviction)
+func (s *shard[K, V]) victimQuality() (quality, lag float64) {
+	scored := s.victimsScored.Load()
+	if scored == 0 {
+		return 0, 0
+	}
+	return float64(s.victimScores.Load()) / float64(scored) / victimScoreScale,
+		float64(s.victimLags.Load()) / float64(scored)
+}
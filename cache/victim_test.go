@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestScoreVictim(t *testing.T) {
+	var shard shard[string, int]
+	shard.timestamp.Store(200)
+
+	scoreVictim(&shard, 100, 100, false) // the oldest entry scanned: 1
+	scoreVictim(&shard, 150, 100, false) // halfway to now: 0.5
+	scoreVictim(&shard, 100, 100, true)  // protected: 0
+	quality, lag := shard.victimQuality()
+	if math.Abs(quality-0.5) > 1e-9 {
+		t.Errorf("quality = %v, want 0.5", quality)
+	}
+	if math.Abs(lag-50.0/3) > 1e-9 {
+		t.Errorf("lag = %v, want %v", lag, 50.0/3)
+	}
+
+	// Older scores fade
+	for range 2 * victimScoreDecay {
+		scoreVictim(&shard, 100, 100, false)
+	}
+	if quality, _ := shard.victimQuality(); quality < 0.99 {
+		t.Errorf("quality = %v after many perfect victims, want about 1", quality)
+	}
+}
+
+func TestCloxCacheVictimQuality(t *testing.T) {
+	quality := func(sweep int) float64 {
+		cache := NewCloxCache[string, int](Config{NumShards: 1, SlotsPerShard: 1024, Capacity: 256, SweepPercent: sweep})
+		defer cache.Close()
+		if q, _ := cache.shards[0].victimQuality(); q != 0 {
+			t.Errorf("quality = %v before any eviction", q)
+		}
+
+		// Half the entries are read often enough to be protected
+		for i := range 256 {
+			cache.Put(fmt.Sprintf("key-%d", i), i)
+		}
+		for range 8 {
+			for i := 0; i < 256; i += 2 {
+				cache.Get(fmt.Sprintf("key-%d", i))
+			}
+		}
+		for i := range 512 {
+			cache.Put(fmt.Sprintf("new-%d", i), i)
+		}
+		return cache.GetAdaptiveStats()[0].VictimQuality
+	}
+
+	narrow, wide := quality(1), quality(50)
+	if narrow <= 0 || wide > 1 || narrow >= wide {
+		t.Errorf("victim quality = %v with a 1%% scan, %v with 50%%; want narrow < wide", narrow, wide)
+	}
+	t.Logf("victim quality: 1%% scan %.3f, 50%% scan %.3f", narrow, wide)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import "io"
+
+// ByteView is a read-only view of a byte-slice value, backed by a lease (see
+// GetView). The bytes are the ones stored in the cache, not a copy: they must not
+// be modified, and stay valid until Release.
+type ByteView[K Key, B ~[]byte] struct {
+	lease *Lease[K, B]
+}
+
+// GetView returns a view of the bytes stored for key without copying them, or
+// false if key is missing. The value is leased (see GetLease), so a finalizer
+// recycling the buffer waits for Release. Counts as an access like Get.
+func GetView[K Key, B ~[]byte](c *CloxCache[K, B], key K) (ByteView[K, B], bool) {
+	lease, ok := c.GetLease(key)
+	if !ok {
+		return ByteView[K, B]{}, false
+	}
+	return ByteView[K, B]{lease: lease}, true
+}
+
+// Bytes returns the stored bytes. They must not be modified or used after Release.
+func (v ByteView[K, B]) Bytes() B {
+	return v.lease.Value()
+}
+
+// Len returns the number of bytes in the view
+func (v ByteView[K, B]) Len() int {
+	return len(v.lease.Value())
+}
+
+// WriteTo writes the bytes to w, implementing io.WriterTo so io.Copy and
+// friends don't copy them into an intermediate buffer
+func (v ByteView[K, B]) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(v.lease.Value())
+	return int64(n), err
+}
+
+// Release ends the view's lease. Safe to call more than once.
+func (v ByteView[K, B]) Release() {
+	v.lease.Release()
+}
+
+// GetInto copies the value for key into dst and returns the value's length, or
+// false if key is missing. It doesn't allocate, so services that pool their own
+// buffers can serve hits without garbage. If the value is longer than dst, only
+// len(dst) bytes are copied and the returned length tells the caller how large a
+// buffer to retry with. Counts as an access like Get.
+func GetInto[K Key, B ~[]byte | ~string](c *CloxCache[K, B], key K, dst []byte) (int, bool) {
+	if c.finalizes() {
+		// A finalizer may recycle the bytes once they leave: copy them while pinned
+		var n int
+		found := c.GetFunc(key, func(value B) {
+			copy(dst, value)
+			n = len(value)
+		})
+		return n, found
+	}
+	value, ok := c.Get(key)
+	if !ok {
+		return 0, false
+	}
+	copy(dst, value)
+	return len(value), true
+}
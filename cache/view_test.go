@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCloxCacheGetView(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	var recycled [][]byte
+	cache := NewCloxCache(cfg, WithFinalizer(func(_ string, b []byte) {
+		recycled = append(recycled, b)
+	}))
+	defer cache.Close()
+
+	payload := bytes.Repeat([]byte("x"), 16<<10)
+	cache.Put("page", payload)
+	view, ok := GetView(cache, "page")
+	if !ok {
+		t.Fatal("GetView missed a live key")
+	}
+	if &view.Bytes()[0] != &payload[0] {
+		t.Error("GetView copied the stored bytes")
+	}
+
+	cache.Delete("page")
+	if len(recycled) != 0 {
+		t.Error("Buffer recycled while a view was open")
+	}
+	var out bytes.Buffer
+	if n, err := view.WriteTo(&out); err != nil || n != int64(view.Len()) || !bytes.Equal(out.Bytes(), payload) {
+		t.Errorf("WriteTo = %d, %v; want %d bytes of payload", n, err, len(payload))
+	}
+
+	view.Release()
+	if len(recycled) != 1 {
+		t.Errorf("Recycled %d buffers after Release, want 1", len(recycled))
+	}
+	if _, ok := GetView(cache, "page"); ok {
+		t.Error("GetView found a deleted key")
+	}
+}
+
+func TestCloxCacheGetInto(t *testing.T) {
+	cfg := Config{NumShards: 1, SlotsPerShard: 64, Capacity: 16}
+	cache := NewCloxCache[string, []byte](cfg)
+	defer cache.Close()
+
+	cache.Put("k", []byte("hello world"))
+	buf := make([]byte, 64)
+	n, ok := GetInto(cache, "k", buf)
+	if !ok || string(buf[:n]) != "hello world" {
+		t.Errorf("GetInto = %q, %v; want hello world", buf[:n], ok)
+	}
+
+	short := make([]byte, 5)
+	if n, ok := GetInto(cache, "k", short); !ok || n != 11 || string(short) != "hello" {
+		t.Errorf("GetInto into a short buffer = %d, %q; want 11, hello", n, short)
+	}
+	if _, ok := GetInto(cache, "missing", buf); ok {
+		t.Error("GetInto found a missing key")
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { GetInto(cache, "k", buf) }); allocs != 0 {
+		t.Errorf("GetInto allocated %.0f times per call, want 0", allocs)
+	}
+
+	strings := NewCloxCache[string, string](cfg)
+	defer strings.Close()
+	strings.Put("s", "text")
+	if n, ok := GetInto(strings, "s", buf); !ok || string(buf[:n]) != "text" {
+		t.Errorf("GetInto(string value) = %q, %v; want text", buf[:n], ok)
+	}
+}
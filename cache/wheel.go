@@ -0,0 +1,155 @@
+package cache
+
+// Hierarchical timing wheel for TTL expiry.
+//
+// Each shard keeps the nodes that have a TTL in a wheel of expiry buckets (see
+// ttl.go), so the clock can remove expired entries as their bucket passes
+// instead of leaving them to lazy reclamation: memory is returned promptly and
+// nothing has to scan the cache or keep a heap ordered.
+//
+// Level 0 has a slot per bucket for the next wheelSlots buckets; each level
+// above has slots wheelSlots times as wide. An entry sits at the lowest level
+// whose span reaches its bucket. When the wheel reaches the start of a wider
+// slot, that slot is cascaded: its entries move down to the level that now
+// resolves them, until they land in level 0 and fire with their bucket.
+// Entries beyond the top level wait in an overflow list that is cascaded each
+// time the top level wraps around.
+//
+// Fired entries queue for the reaper, which removes at most ttlReapBatch of them
+// per shard and clock tick so a bucket of millions doesn't hold the shard lock
+// for long; the rest wait for the next tick unless a read or write reclaims
+// them first (see ttl.go).
+//
+// Entries are not removed when a node's TTL changes or it leaves the cache;
+// each records the bucket it was filed under and is dropped when it fires or
+// cascades if the node no longer has that bucket. A removed node stays
+// reachable from the wheel until then.
+
+const (
+	wheelBits   = 6
+	wheelSlots  = 1 << wheelBits
+	wheelLevels = 4 // with 1s buckets, level 3 spans 194 days
+)
+
+// wheelEntry is a node filed under the expiry bucket it had when added
+type wheelEntry[K Key, V any] struct {
+	node   *recordNode[K, V]
+	bucket uint32
+}
+
+// timingWheel holds a shard's TTL nodes by expiry bucket (guarded by the shard lock)
+type timingWheel[K Key, V any] struct {
+	now      uint32 // newest bucket that has fired
+	levels   [wheelLevels][wheelSlots][]wheelEntry[K, V]
+	overflow []wheelEntry[K, V]
+	due      []wheelEntry[K, V] // fired entries the reaper hasn't reached
+}
+
+// newTimingWheel returns an empty wheel whose buckets up to now have fired
+func newTimingWheel[K Key, V any](now uint32) *timingWheel[K, V] {
+	return &timingWheel[K, V]{now: now}
+}
+
+// add files node under bucket; buckets that already fired go to the next one
+func (w *timingWheel[K, V]) add(node *recordNode[K, V], bucket uint32) {
+	w.file(wheelEntry[K, V]{node: node, bucket: bucket}, max(bucket, w.now+1))
+}
+
+// file puts e in the slot for bucket at, which must not be before now
+func (w *timingWheel[K, V]) file(e wheelEntry[K, V], bucket uint32) {
+	at, now := uint64(bucket), uint64(w.now)
+	for level := range wheelLevels {
+		shift := uint(level * wheelBits)
+		if at>>shift-now>>shift < wheelSlots {
+			slot := &w.levels[level][at>>shift&(wheelSlots-1)]
+			*slot = append(*slot, e)
+			return
+		}
+	}
+	w.overflow = append(w.overflow, e)
+}
+
+// advance fires the buckets after now up to and including to, queueing their
+// entries for reap
+func (w *timingWheel[K, V]) advance(to uint32) {
+	if to <= w.now {
+		return
+	}
+	if uint64(to-w.now) > wheelSlots*wheelSlots {
+		// Stepping bucket by bucket would cost more than refiling everything
+		w.rebuild(to)
+		return
+	}
+	for w.now < to {
+		w.now++
+		for level := wheelLevels - 1; level > 0; level-- {
+			shift := uint(level * wheelBits)
+			if w.now&(1<<shift-1) != 0 {
+				continue
+			}
+			if level == wheelLevels-1 && w.now&(1<<(shift+wheelBits)-1) == 0 {
+				w.cascade(&w.overflow)
+			}
+			w.cascade(&w.levels[level][w.now>>shift&(wheelSlots-1)])
+		}
+		slot := &w.levels[0][w.now&(wheelSlots-1)]
+		w.due = append(w.due, *slot...)
+		*slot = nil
+	}
+}
+
+// reap calls expire for up to limit fired entries whose node still has the
+// bucket it was filed under
+func (w *timingWheel[K, V]) reap(limit int, expire func(*recordNode[K, V])) {
+	n := min(limit, len(w.due))
+	for _, e := range w.due[:n] {
+		if e.node.expires.Load() == e.bucket {
+			expire(e.node)
+		}
+	}
+	clear(w.due[:n])
+	if w.due = w.due[n:]; len(w.due) == 0 {
+		w.due = nil
+	}
+}
+
+// cascade refiles the entries of a wider slot that are still current
+func (w *timingWheel[K, V]) cascade(slot *[]wheelEntry[K, V]) {
+	entries := *slot
+	*slot = nil
+	for _, e := range entries {
+		if e.node.expires.Load() == e.bucket {
+			w.file(e, e.bucket) // not before now, or it would have fired
+		}
+	}
+}
+
+// rebuild jumps the wheel to now, firing everything due and refiling the rest
+func (w *timingWheel[K, V]) rebuild(now uint32) {
+	var entries []wheelEntry[K, V]
+	for level := range w.levels {
+		for slot := range w.levels[level] {
+			entries = append(entries, w.levels[level][slot]...)
+			w.levels[level][slot] = nil
+		}
+	}
+	entries = append(entries, w.overflow...)
+	w.overflow = nil
+	w.now = now
+	for _, e := range entries {
+		switch {
+		case e.node.expires.Load() != e.bucket:
+		case e.bucket <= now:
+			w.due = append(w.due, e)
+		default:
+			w.file(e, e.bucket)
+		}
+	}
+}
+
+// reapLocked removes a live node the wheel fired. Caller must hold the shard lock.
+func (c *CloxCache[K, V]) reapLocked(shard *shard[K, V], node *recordNode[K, V]) {
+	if c.unlinkLocked(shard, node, EvictReasonExpired) {
+		shard.ttlReaped.Add(1)
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestTimingWheel(t *testing.T) {
+	wheel := newTimingWheel[string, int](0)
+	fired := make(map[*recordNode[string, int]]uint32)
+	var now uint32
+	expire := func(node *recordNode[string, int]) { fired[node] = now }
+
+	// Buckets spread over every level and the overflow list
+	var nodes []*recordNode[string, int]
+	for _, bucket := range []uint32{1, 63, 64, 65, 4095, 4096, 4097, 200_000, 262_144, 300_000, 20_000_000} {
+		node := new(recordNode[string, int])
+		node.expires.Store(bucket)
+		wheel.add(node, bucket)
+		nodes = append(nodes, node)
+	}
+	// A node whose TTL moved fires with its new bucket only
+	moved := new(recordNode[string, int])
+	moved.expires.Store(10)
+	wheel.add(moved, 10)
+	moved.expires.Store(5000)
+	wheel.add(moved, 5000)
+	// A node whose TTL was cleared never fires
+	cleared := new(recordNode[string, int])
+	wheel.add(cleared, 70)
+
+	for now < 20_000_000 {
+		now = min(now+1+rand.Uint32N(3000), 20_000_000)
+		wheel.advance(now)
+		wheel.reap(math.MaxInt, expire)
+	}
+	for _, node := range nodes {
+		bucket := node.expires.Load()
+		at, ok := fired[node]
+		// Jumps of more than wheelSlots² buckets fire whatever they passed at once
+		if !ok || at < bucket || at-bucket >= 3000 {
+			t.Errorf("bucket %d fired at %d, %v", bucket, at, ok)
+		}
+	}
+	if at := fired[moved]; at < 5000 {
+		t.Errorf("Moved node fired at %d, before its new bucket", at)
+	}
+	if _, ok := fired[cleared]; ok {
+		t.Error("Node without a TTL fired")
+	}
+	if len(fired) != len(nodes)+1 {
+		t.Errorf("%d nodes fired, want %d (each once)", len(fired), len(nodes)+1)
+	}
+}
+
+func TestTimingWheelStepwise(t *testing.T) {
+	wheel := newTimingWheel[string, int](0)
+	want := make(map[*recordNode[string, int]]uint32)
+	for range 2000 {
+		bucket := 1 + rand.Uint32N(3*wheelSlots*wheelSlots)
+		node := new(recordNode[string, int])
+		node.expires.Store(bucket)
+		wheel.add(node, bucket)
+		want[node] = bucket
+	}
+	for now := uint32(1); now <= 3*wheelSlots*wheelSlots; now++ {
+		wheel.advance(now)
+		wheel.reap(math.MaxInt, func(node *recordNode[string, int]) {
+			if want[node] != now {
+				t.Fatalf("bucket %d fired at %d", want[node], now)
+			}
+			delete(want, node)
+		})
+	}
+	if len(want) != 0 {
+		t.Errorf("%d nodes never fired", len(want))
+	}
+}
+
+func TestCloxCacheWheelExpiry(t *testing.T) {
+	cache := NewCloxCache[string, int](Config{NumShards: 4, SlotsPerShard: 256, Capacity: 1024})
+	defer cache.Close()
+
+	for i := range 600 {
+		cache.PutWithTTL(fmt.Sprintf("key-%d", i), i, time.Duration(1+i%6)*time.Minute)
+	}
+	cache.Put("forever", 1)
+
+	// Each minute removes its entries without any reads or writes
+	start := time.Now()
+	for minute := 1; minute <= 6; minute++ {
+		cache.advanceTTL(start.Add(time.Duration(minute)*time.Minute + 2*time.Second))
+		if n, want := cache.countEntries(), 600-100*minute+1; n != want {
+			t.Errorf("%d entries after %d minutes, want %d", n, minute, want)
+		}
+	}
+	if report := cache.VerifyIntegrity(); !report.OK() {
+		t.Errorf("Integrity issues: %v", report.Issues)
+	}
+
+	// Frozen caches keep expired entries until Thaw
+	cache.PutWithTTL("frozen", 1, time.Minute)
+	cache.Freeze()
+	cache.advanceTTL(start.Add(10 * time.Minute))
+	if n := cache.countEntries(); n != 2 {
+		t.Errorf("%d entries while frozen, want 2", n)
+	}
+	cache.Thaw()
+	cache.advanceTTL(start.Add(11 * time.Minute))
+	if n := cache.countEntries(); n != 1 {
+		t.Errorf("%d entries after Thaw, want 1", n)
+	}
+}
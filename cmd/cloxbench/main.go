@@ -0,0 +1,320 @@
+// Command cloxbench runs a configurable workload against a CloxCache and prints
+// hit rate, throughput, allocation and adaptive-stat timelines in a
+// machine-readable format, so a Config can be tuned without writing Go
+// benchmarks.
+//
+// Usage:
+//
+//	cloxbench [flags]
+//
+// Workload flags:
+//
+//	-keys N          distinct keys (default 100000)
+//	-theta F         Zipf skew of key popularity in [0, 1), 0 = uniform (default 0.99)
+//	-read-ratio F    fraction of operations that are Gets (default 0.9)
+//	-value-size N    bytes per value (default 128)
+//	-duration D      how long to run (default 10s, or to the end of a -trace)
+//	-goroutines N    concurrent workers (default GOMAXPROCS)
+//	-interval D      time between timeline samples (default 1s)
+//	-seed N          random seed; workers use seed, seed+1, ...
+//	-format F        json (one object per line) or csv
+//	-config FILE     cache configuration (.json, .yaml); -cache-* flags override it
+//	-trace FILE      replay a trace instead of generating operations (.gz is decompressed)
+//	-trace-format F  format of -trace (see below)
+//
+// plus every -cache-* flag of Config.RegisterFlags. Reads that miss store the
+// value (cache-aside), so the hit rate is what an application would see. Each
+// line is a timeline sample with the counters of its interval, and the last one
+// is the summary of the whole run.
+//
+// Traces are replayed in order by a single worker, so -keys, -theta,
+// -read-ratio, -goroutines and -seed don't apply. Published traces are read in
+// their own formats, without conversion:
+//
+//	arc      ARC/UMass block traces: "start count ignored request" per line, each
+//	         line reading count consecutive blocks of -value-size bytes
+//	twitter  Twitter cache cluster traces: CSV of timestamp, key, key size,
+//	         value size, client, operation, TTL; get/gets read, delete deletes,
+//	         every other operation writes
+//	wiki     Wikipedia CDN traces: "timestamp id size" (wiki2018) or
+//	         "timestamp id type size latency" (wiki2019), whitespace-separated
+//
+// and so are plain op logs, as proxies emit them:
+//
+//	csv             "timestamp,op,key,size" per line, op being get, set or delete
+//	                (read, put, write and del also work); a header line is skipped
+//	oracle-general  libCacheSim's binary oracleGeneral records (all reads)
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bottledcode/cloxcache/cache"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, "cloxbench:", err)
+		os.Exit(1)
+	}
+}
+
+// options holds the parsed command line
+type options struct {
+	cfg        cache.Config
+	w          workload
+	duration   time.Duration
+	goroutines int
+	interval   time.Duration
+	seed       int64
+	format     string
+
+	trace, traceFormat string
+}
+
+// parseArgs parses args. A -config file is loaded first and the command line
+// parsed again on top of it, so -cache-* flags override the file.
+func parseArgs(args []string) (options, error) {
+	var configPath string
+	opts, err := parseFlags(args, nil, &configPath)
+	if err != nil || configPath == "" {
+		return opts, err
+	}
+	cfg, err := cache.ConfigFromFile(configPath)
+	if err != nil {
+		return opts, err
+	}
+	return parseFlags(args, &cfg, &configPath)
+}
+
+// parseFlags parses args over base (nil picks a 10,000 entry cache with stats)
+func parseFlags(args []string, base *cache.Config, configPath *string) (options, error) {
+	var opts options
+	if base != nil {
+		opts.cfg = *base
+	} else {
+		opts.cfg = cache.ConfigFromCapacity(10000)
+		opts.cfg.CollectStats = true
+	}
+
+	fs := flag.NewFlagSet("cloxbench", flag.ContinueOnError)
+	opts.cfg.RegisterFlags(fs)
+	fs.IntVar(&opts.w.keys, "keys", 100000, "distinct keys")
+	fs.Float64Var(&opts.w.theta, "theta", 0.99, "Zipf skew of key popularity in [0, 1), 0 = uniform")
+	fs.Float64Var(&opts.w.readRatio, "read-ratio", 0.9, "fraction of operations that are Gets")
+	fs.IntVar(&opts.w.valueSize, "value-size", 128, "bytes per value")
+	fs.DurationVar(&opts.duration, "duration", 0, "how long to run (default 10s, or to the end of a -trace)")
+	fs.IntVar(&opts.goroutines, "goroutines", runtime.GOMAXPROCS(0), "concurrent workers")
+	fs.DurationVar(&opts.interval, "interval", time.Second, "time between timeline samples")
+	fs.Int64Var(&opts.seed, "seed", 1, "random seed")
+	fs.StringVar(&opts.format, "format", "json", "output format: json or csv")
+	fs.StringVar(configPath, "config", *configPath, "cache configuration file (.json, .yaml)")
+	fs.StringVar(&opts.trace, "trace", "", "replay this trace file instead of generating operations")
+	fs.StringVar(&opts.traceFormat, "trace-format", "", "format of -trace: "+strings.Join(traceFormatNames(), ", "))
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+
+	switch {
+	case opts.w.keys < 1:
+		return opts, errors.New("-keys must be at least 1")
+	case opts.w.theta < 0 || opts.w.theta >= 1:
+		return opts, errors.New("-theta must be in [0, 1)")
+	case opts.w.readRatio < 0 || opts.w.readRatio > 1:
+		return opts, errors.New("-read-ratio must be in [0, 1]")
+	case opts.w.valueSize < 0:
+		return opts, errors.New("-value-size must not be negative")
+	case opts.duration < 0 || opts.interval <= 0:
+		return opts, errors.New("-duration must not be negative and -interval must be positive")
+	case (opts.trace == "") != (opts.traceFormat == ""):
+		return opts, errors.New("-trace and -trace-format go together")
+	case opts.goroutines < 1:
+		return opts, errors.New("-goroutines must be at least 1")
+	}
+	return opts, opts.cfg.Validate()
+}
+
+// run parses args, runs the benchmark and writes its report to out. It stops
+// early, still reporting, when ctx ends.
+func run(ctx context.Context, args []string, out io.Writer) error {
+	opts, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	rep, err := newReporter(opts.format, out)
+	if err != nil {
+		return err
+	}
+
+	if opts.trace != "" {
+		src, err := openTrace(opts.trace, opts.traceFormat, opts.w.valueSize)
+		if err != nil {
+			return err
+		}
+		defer src.close()
+		return bench(ctx, opts, []opSource{src}, rep)
+	}
+
+	if opts.duration == 0 {
+		opts.duration = 10 * time.Second
+	}
+	keys := make([]string, opts.w.keys)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+	sources := make([]opSource, opts.goroutines)
+	for i := range sources {
+		sources[i] = newGenerator(opts.w, keys, opts.seed+int64(i))
+	}
+	return bench(ctx, opts, sources, rep)
+}
+
+// bench runs one worker per source until the duration passes (0 = no limit), the
+// sources run dry or ctx ends, sampling the cache every interval
+func bench(ctx context.Context, opts options, sources []opSource, rep reporter) error {
+	c := cache.NewCloxCache[string, []byte](opts.cfg)
+	defer c.Close()
+
+	var cancel context.CancelFunc
+	if opts.duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.duration)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+	var ops atomic.Uint64
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local uint64 // ops not yet added to the shared counter
+			defer func() { ops.Add(local) }()
+			var buf []byte // stored values are slices of it: their contents don't matter
+			for {
+				if local == 256 {
+					ops.Add(local)
+					local = 0
+					if ctx.Err() != nil {
+						return
+					}
+				}
+				o, ok := src.next()
+				if !ok {
+					return
+				}
+				local++
+				if o.kind == opDelete {
+					c.Delete(o.key)
+					continue
+				}
+				if o.kind == opGet {
+					if _, hit := c.Get(o.key); hit {
+						continue
+					}
+				}
+				if cap(buf) < o.size {
+					buf = make([]byte, o.size)
+				}
+				c.Put(o.key, buf[:o.size])
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	start := newSample(c, 0)
+	prev := start
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-ticker.C:
+		case <-done:
+			running = false
+		}
+		cur := newSample(c, ops.Load())
+		if err := rep.write(cur.since(prev, c, "sample")); err != nil {
+			cancel()
+			<-done
+			return err
+		}
+		prev = cur
+	}
+	for _, src := range sources {
+		if err := src.err(); err != nil {
+			return err
+		}
+	}
+	if err := rep.write(prev.since(start, c, "summary")); err != nil {
+		return err
+	}
+	return rep.flush()
+}
+
+// sample is the state of the cache and the process at one point of the run
+type sample struct {
+	stats cache.StatsSnapshot
+	ops   uint64
+	mem   runtime.MemStats
+}
+
+func newSample(c *cache.CloxCache[string, []byte], ops uint64) sample {
+	s := sample{stats: c.StatsSnapshot(), ops: ops}
+	runtime.ReadMemStats(&s.mem)
+	return s
+}
+
+// since returns the record for the interval from prev to s
+func (s sample) since(prev sample, c *cache.CloxCache[string, []byte], typ string) record {
+	delta := s.stats.Delta(prev.stats)
+	ops := s.ops - prev.ops
+	r := record{
+		Type:      typ,
+		ElapsedMS: delta.Interval.Milliseconds(),
+		Ops:       ops,
+		HitRate:   delta.HitRatio(),
+		Entries:   s.stats.Entries,
+		Evictions: delta.Evictions,
+		Rejected:  delta.Rejected,
+		HeapBytes: s.mem.HeapAlloc,
+		AvgK:      c.AverageK(),
+	}
+	if secs := delta.Interval.Seconds(); secs > 0 {
+		r.OpsPerSec = float64(ops) / secs
+	}
+	if ops > 0 {
+		r.AllocsOp = float64(s.mem.Mallocs-prev.mem.Mallocs) / float64(ops)
+		r.BytesOp = float64(s.mem.TotalAlloc-prev.mem.TotalAlloc) / float64(ops)
+	}
+	r.RateLow, r.RateHigh = c.AverageLearnedThresholds()
+	shards := c.GetAdaptiveStats()
+	for _, st := range shards {
+		r.VictimQual += st.VictimQuality
+	}
+	r.VictimQual /= float64(len(shards))
+	return r
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRunJSON(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"-duration", "200ms", "-interval", "50ms", "-keys", "1000", "-cache-capacity", "100", "-goroutines", "2"}
+	if err := run(context.Background(), args, &out); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("Got %d lines, want samples and a summary:\n%s", len(lines), out.String())
+	}
+	var samples []record
+	for _, line := range lines {
+		var r record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("Line %q: %v", line, err)
+		}
+		samples = append(samples, r)
+	}
+	summary := samples[len(samples)-1]
+	if summary.Type != "summary" || summary.Ops == 0 || summary.OpsPerSec <= 0 {
+		t.Errorf("Summary = %+v, want ops counted", summary)
+	}
+	// 100 of 1000 Zipf-distributed keys fit, so some reads hit and some miss
+	if summary.HitRate <= 0 || summary.HitRate >= 1 || summary.Evictions == 0 {
+		t.Errorf("Summary hit rate %v with %d evictions, want both nonzero", summary.HitRate, summary.Evictions)
+	}
+	var ops uint64
+	for _, r := range samples[:len(samples)-1] {
+		if r.Type != "sample" {
+			t.Errorf("Record type %q before the summary", r.Type)
+		}
+		ops += r.Ops
+	}
+	if ops != summary.Ops {
+		t.Errorf("Samples add up to %d ops, summary has %d", ops, summary.Ops)
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"-duration", "100ms", "-interval", "50ms", "-format", "csv", "-read-ratio", "1", "-theta", "0"}
+	if err := run(context.Background(), args, &out); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Errorf("Header = %v", rows[0])
+	}
+	last := rows[len(rows)-1]
+	if last[0] != "summary" {
+		t.Errorf("Last row = %v, want the summary", last)
+	}
+	if ops, _ := strconv.ParseUint(last[2], 10, 64); ops == 0 {
+		t.Errorf("Summary counted no ops: %v", last)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := os.WriteFile(path, []byte(`{"numShards": 8, "slotsPerShard": 256, "capacity": 512}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flags override the file wherever they appear
+	opts, err := parseArgs([]string{"-cache-shards", "4", "-config", path, "-keys", "50"})
+	if err != nil {
+		t.Fatalf("parseArgs: %v", err)
+	}
+	if opts.cfg.NumShards != 4 || opts.cfg.SlotsPerShard != 256 || opts.w.keys != 50 {
+		t.Errorf("Config = %+v, keys %d; want the file with 4 shards", opts.cfg, opts.w.keys)
+	}
+
+	for _, args := range [][]string{
+		{"-theta", "1"},
+		{"-read-ratio", "2"},
+		{"-keys", "0"},
+		{"-format", "xml"},
+		{"-cache-shards", "3"},
+		{"extra"},
+	} {
+		if err := run(context.Background(), args, new(bytes.Buffer)); err == nil {
+			t.Errorf("run(%q) succeeded", args)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// record is one line of output: a timeline sample, or the summary of the run
+type record struct {
+	Type       string  `json:"type"` // "sample" or "summary"
+	ElapsedMS  int64   `json:"elapsed_ms"`
+	Ops        uint64  `json:"ops"`
+	OpsPerSec  float64 `json:"ops_per_sec"`
+	HitRate    float64 `json:"hit_rate"`
+	Entries    int64   `json:"entries"`
+	Evictions  uint64  `json:"evictions"`
+	Rejected   uint64  `json:"rejected"`
+	AllocsOp   float64 `json:"allocs_per_op"`
+	BytesOp    float64 `json:"bytes_per_op"`
+	HeapBytes  uint64  `json:"heap_bytes"`
+	AvgK       float64 `json:"avg_k"`
+	RateLow    float64 `json:"rate_low"`
+	RateHigh   float64 `json:"rate_high"`
+	VictimQual float64 `json:"victim_quality"`
+}
+
+// csvHeader names the columns written by record.csv, matching the JSON names
+var csvHeader = []string{
+	"type", "elapsed_ms", "ops", "ops_per_sec", "hit_rate", "entries", "evictions", "rejected",
+	"allocs_per_op", "bytes_per_op", "heap_bytes", "avg_k", "rate_low", "rate_high", "victim_quality",
+}
+
+func (r record) csv() []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', 6, 64) }
+	u := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	return []string{
+		r.Type, strconv.FormatInt(r.ElapsedMS, 10), u(r.Ops), f(r.OpsPerSec), f(r.HitRate),
+		strconv.FormatInt(r.Entries, 10), u(r.Evictions), u(r.Rejected), f(r.AllocsOp), f(r.BytesOp),
+		u(r.HeapBytes), f(r.AvgK), f(r.RateLow), f(r.RateHigh), f(r.VictimQual),
+	}
+}
+
+// reporter writes records as JSON lines or CSV
+type reporter interface {
+	write(r record) error
+	flush() error
+}
+
+func newReporter(format string, w io.Writer) (reporter, error) {
+	switch format {
+	case "json":
+		return jsonReporter{json.NewEncoder(w)}, nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return csvReporter{cw}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json or csv)", format)
+	}
+}
+
+type jsonReporter struct{ enc *json.Encoder }
+
+func (j jsonReporter) write(r record) error { return j.enc.Encode(r) }
+func (j jsonReporter) flush() error         { return nil }
+
+type csvReporter struct{ w *csv.Writer }
+
+func (c csvReporter) write(r record) error { return c.w.Write(r.csv()) }
+
+func (c csvReporter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
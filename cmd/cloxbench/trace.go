@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// traceParser appends the operations of one trace line to ops. valueSize is the
+// size of values the format doesn't give one for.
+type traceParser func(line string, valueSize int, ops []op) ([]op, error)
+
+// traceFormat reads a trace: text formats are parsed line by line, binary ones
+// as fixed-size records
+type traceFormat struct {
+	line       traceParser
+	recordSize int
+	record     func(rec []byte, ops []op) []op
+}
+
+// traceFormats maps -trace-format names to their readers
+var traceFormats = map[string]traceFormat{
+	"arc":            {line: parseARC},
+	"twitter":        {line: parseTwitter},
+	"wiki":           {line: parseWiki},
+	"csv":            {line: parseCSV},
+	"oracle-general": {recordSize: oracleGeneralSize, record: parseOracleGeneral},
+}
+
+// traceFormatNames returns the supported formats, sorted
+func traceFormatNames() []string {
+	names := make([]string, 0, len(traceFormats))
+	for name := range traceFormats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// maxARCRun bounds the blocks one ARC line reads, so a corrupt line can't expand
+// into an endless run
+const maxARCRun = 1 << 20
+
+// traceSource replays a trace file
+type traceSource struct {
+	file      *os.File
+	format    traceFormat
+	sc        *bufio.Scanner // text formats
+	br        *bufio.Reader  // binary formats
+	rec       []byte
+	valueSize int
+	pending   []op // operations of the current line or record
+	pos       int  // next of pending to return
+	n         int  // lines or records read
+	failure   error
+}
+
+// openTrace opens the trace at path, decompressing it if it ends in .gz
+func openTrace(path, format string, valueSize int) (*traceSource, error) {
+	tf, ok := traceFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown trace format %q (want %s)", format, strings.Join(traceFormatNames(), ", "))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		if r, err = gzip.NewReader(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	t := &traceSource{file: f, format: tf, valueSize: valueSize}
+	if tf.line != nil {
+		t.sc = bufio.NewScanner(r)
+		t.sc.Buffer(nil, 1<<20)
+	} else {
+		t.br = bufio.NewReaderSize(r, 1<<16)
+		t.rec = make([]byte, tf.recordSize)
+	}
+	return t, nil
+}
+
+func (t *traceSource) next() (op, bool) {
+	for t.pos == len(t.pending) {
+		if t.failure != nil {
+			return op{}, false
+		}
+		t.pos = 0
+		var more bool
+		if t.sc != nil {
+			more = t.readLine()
+		} else {
+			more = t.readRecord()
+		}
+		if !more {
+			return op{}, false
+		}
+	}
+	t.pos++
+	return t.pending[t.pos-1], true
+}
+
+// readLine parses the next line of a text trace into pending
+func (t *traceSource) readLine() bool {
+	for {
+		if !t.sc.Scan() {
+			if err := t.sc.Err(); err != nil {
+				t.failure = fmt.Errorf("%s: %w", t.file.Name(), err)
+			}
+			return false
+		}
+		t.n++
+		line := strings.TrimSpace(t.sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var err error
+		if t.pending, err = t.format.line(line, t.valueSize, t.pending[:0]); err != nil {
+			t.failure = fmt.Errorf("%s:%d: %w", t.file.Name(), t.n, err)
+			return false
+		}
+		return true
+	}
+}
+
+// readRecord decodes the next record of a binary trace into pending
+func (t *traceSource) readRecord() bool {
+	if _, err := io.ReadFull(t.br, t.rec); err != nil {
+		if err != io.EOF {
+			t.failure = fmt.Errorf("%s: record %d: %w", t.file.Name(), t.n+1, err)
+		}
+		return false
+	}
+	t.n++
+	t.pending = t.format.record(t.rec, t.pending[:0])
+	return true
+}
+
+func (t *traceSource) err() error { return t.failure }
+
+func (t *traceSource) close() error { return t.file.Close() }
+
+// parseARC parses a line of the traces published with ARC (Megiddo and Modha):
+// "start count ignored request", reading count blocks from start
+func parseARC(line string, valueSize int, ops []op) ([]op, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ops, fmt.Errorf("want start and count, got %q", line)
+	}
+	start, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return ops, fmt.Errorf("bad start block: %w", err)
+	}
+	count, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil || count > maxARCRun {
+		return ops, fmt.Errorf("bad block count %q", fields[1])
+	}
+	for block := range count {
+		ops = append(ops, op{key: strconv.FormatUint(start+block, 10), size: valueSize, kind: opGet})
+	}
+	return ops, nil
+}
+
+// parseTwitter parses a line of the Twitter cache cluster traces (Yang et al.):
+// "timestamp,key,key size,value size,client,operation,TTL"
+func parseTwitter(line string, _ int, ops []op) ([]op, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) != 7 {
+		return ops, fmt.Errorf("want 7 fields, got %d", len(fields))
+	}
+	size, err := strconv.Atoi(fields[3])
+	if err != nil || size < 0 {
+		return ops, fmt.Errorf("bad value size %q", fields[3])
+	}
+	o := op{key: fields[1], size: size}
+	switch fields[5] {
+	case "get", "gets":
+		o.kind = opGet
+	case "delete":
+		o.kind = opDelete
+	default: // set, add, replace, cas, append, prepend, incr, decr
+		o.kind = opSet
+	}
+	return append(ops, o), nil
+}
+
+// parseWiki parses a line of the Wikipedia CDN traces: "timestamp id size"
+// (wiki2018) or "timestamp id type size latency" (wiki2019)
+func parseWiki(line string, _ int, ops []op) ([]op, error) {
+	fields := strings.Fields(line)
+	var size string
+	switch len(fields) {
+	case 3:
+		size = fields[2]
+	case 5:
+		size = fields[3]
+	default:
+		return ops, fmt.Errorf("want 3 or 5 fields, got %d", len(fields))
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n < 0 {
+		return ops, fmt.Errorf("bad size %q", size)
+	}
+	return append(ops, op{key: fields[1], size: n, kind: opGet}), nil
+}
+
+// parseCSV parses a line of a plain op log: "timestamp,op,key,size", where op is
+// get (or read), set (or put, write) or delete (or del). A header line starting
+// with "timestamp" is skipped.
+func parseCSV(line string, _ int, ops []op) ([]op, error) {
+	fields := strings.Split(line, ",")
+	if len(fields) != 4 {
+		return ops, fmt.Errorf("want 4 fields, got %d", len(fields))
+	}
+	if fields[0] == "timestamp" {
+		return ops, nil
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(fields[3]))
+	if err != nil || size < 0 {
+		return ops, fmt.Errorf("bad size %q", fields[3])
+	}
+	o := op{key: strings.TrimSpace(fields[2]), size: size}
+	switch strings.ToLower(strings.TrimSpace(fields[1])) {
+	case "get", "read":
+		o.kind = opGet
+	case "set", "put", "write":
+		o.kind = opSet
+	case "delete", "del":
+		o.kind = opDelete
+	default:
+		return ops, fmt.Errorf("unknown op %q", fields[1])
+	}
+	return append(ops, o), nil
+}
+
+// oracleGeneralSize is the size of a libCacheSim oracleGeneral record:
+// timestamp(uint32) id(uint64) size(uint32) nextAccess(int64), little-endian
+const oracleGeneralSize = 24
+
+// parseOracleGeneral decodes a libCacheSim oracleGeneral record as a read of the
+// object
+func parseOracleGeneral(rec []byte, ops []op) []op {
+	id := binary.LittleEndian.Uint64(rec[4:])
+	size := binary.LittleEndian.Uint32(rec[12:])
+	return append(ops, op{key: strconv.FormatUint(id, 10), size: int(min(size, math.MaxInt32)), kind: opGet})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readTrace returns every operation of a trace and the error it ended with
+func readTrace(t *testing.T, path, format string) ([]op, error) {
+	t.Helper()
+	src, err := openTrace(path, format, 512)
+	if err != nil {
+		t.Fatalf("openTrace: %v", err)
+	}
+	defer src.close()
+	var ops []op
+	for {
+		o, ok := src.next()
+		if !ok {
+			return ops, src.err()
+		}
+		ops = append(ops, o)
+	}
+}
+
+// oracleGeneral encodes a libCacheSim oracleGeneral record
+func oracleGeneral(id uint64, size uint32) []byte {
+	rec := binary.LittleEndian.AppendUint32(nil, 1)
+	rec = binary.LittleEndian.AppendUint64(rec, id)
+	rec = binary.LittleEndian.AppendUint32(rec, size)
+	return binary.LittleEndian.AppendUint64(rec, math.MaxUint64)
+}
+
+func writeTrace(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTraceFormats(t *testing.T) {
+	tests := []struct {
+		format, data string
+		want         []op
+	}{
+		{"arc", "100 3 0 0\n\n7 1 0 1\n", []op{
+			{key: "100", size: 512}, {key: "101", size: 512}, {key: "102", size: 512}, {key: "7", size: 512},
+		}},
+		{"twitter", "0,k1,2,300,1,get,0\n1,k1,2,300,1,set,3600\n2,k2,2,0,1,delete,0\n", []op{
+			{key: "k1", size: 300, kind: opGet}, {key: "k1", size: 300, kind: opSet}, {key: "k2", kind: opDelete},
+		}},
+		{"wiki", "1 a 1000\n2 b 2000\n# a comment\n", []op{{key: "a", size: 1000}, {key: "b", size: 2000}}},
+		{"wiki", "1 a image 1000 5\n", []op{{key: "a", size: 1000}}},
+		{"csv", "timestamp,op,key,size\n1,get,a,10\n2,SET,a,20\n3,del,a,0\n", []op{
+			{key: "a", size: 10, kind: opGet}, {key: "a", size: 20, kind: opSet}, {key: "a", kind: opDelete},
+		}},
+		{"oracle-general", string(oracleGeneral(7, 4096)) + string(oracleGeneral(9, 1)), []op{
+			{key: "7", size: 4096}, {key: "9", size: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			ops, err := readTrace(t, writeTrace(t, "trace", tt.data), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ops) != len(tt.want) {
+				t.Fatalf("Got %v, want %v", ops, tt.want)
+			}
+			for i := range ops {
+				if ops[i] != tt.want[i] {
+					t.Errorf("Op %d = %+v, want %+v", i, ops[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTraceErrors(t *testing.T) {
+	for format, data := range map[string]string{
+		"arc":     "100 3 0 0\nnope\n",
+		"twitter": "0,k1,2,300,1,get\n",
+		"wiki":    "1 a -5\n",
+		"csv":     "1,get,a,10\n2,touch,a,10\n",
+		// Truncated mid-record
+		"oracle-general": string(oracleGeneral(1, 1)[:10]),
+	} {
+		ops, err := readTrace(t, writeTrace(t, "trace", data), format)
+		if err == nil || !strings.Contains(err.Error(), ":") {
+			t.Errorf("%s: got %v, want an error naming the line", format, err)
+		}
+		if format == "arc" && len(ops) != 3 {
+			t.Errorf("arc: %d ops before the bad line, want 3", len(ops))
+		}
+	}
+	if _, err := openTrace("trace", "lirs", 0); err == nil {
+		t.Error("Unknown format was accepted")
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	// A compressed wiki trace cycling over 10 objects
+	var data bytes.Buffer
+	zw := gzip.NewWriter(&data)
+	for i := range 1000 {
+		fmt.Fprintf(zw, "%d obj-%d 100\n", i, i%10)
+	}
+	zw.Close()
+	path := writeTrace(t, "trace.gz", data.String())
+
+	var out bytes.Buffer
+	args := []string{"-trace", path, "-trace-format", "wiki", "-cache-shards", "1", "-cache-slots", "16", "-cache-capacity", "3"}
+	if err := run(context.Background(), args, &out); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var summary record
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Type != "summary" || summary.Ops != 1000 {
+		t.Errorf("Summary = %+v, want the whole trace replayed", summary)
+	}
+
+	if err := run(context.Background(), []string{"-trace", path}, new(bytes.Buffer)); err == nil {
+		t.Error("-trace without -trace-format was accepted")
+	}
+}
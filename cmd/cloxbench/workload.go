@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// workload describes the operations each benchmark goroutine issues
+type workload struct {
+	keys      int     // distinct keys
+	theta     float64 // Zipf skew of key popularity (0 = uniform)
+	readRatio float64 // fraction of operations that are Gets
+	valueSize int     // bytes per value
+}
+
+// opKind is what an operation does
+type opKind uint8
+
+const (
+	opGet    opKind = iota // Get, storing the value on a miss
+	opSet                  // Put
+	opDelete               // Delete
+)
+
+// op is one operation of a workload
+type op struct {
+	key  string
+	size int // value bytes stored by the operation
+	kind opKind
+}
+
+// opSource generates a workload's operations; ok is false once it runs dry, and
+// err tells why if that was a failure
+type opSource interface {
+	next() (o op, ok bool)
+	err() error
+}
+
+// generator draws operations at random: keys by Zipf popularity, reads with the
+// configured ratio
+type generator struct {
+	w    workload
+	keys []string
+	rng  *rand.Rand
+	zipf *zipfGenerator // nil = uniform
+}
+
+// newGenerator returns a generator of w's operations on keys, which holds
+// w.keys names
+func newGenerator(w workload, keys []string, seed int64) *generator {
+	g := &generator{w: w, keys: keys, rng: rand.New(rand.NewSource(seed))}
+	if w.theta > 0 {
+		g.zipf = newZipfGenerator(uint64(w.keys), w.theta, g.rng)
+	}
+	return g
+}
+
+func (g *generator) next() (op, bool) {
+	var key int
+	if g.zipf != nil {
+		key = int(min(g.zipf.next(), uint64(g.w.keys-1)))
+	} else {
+		key = g.rng.Intn(g.w.keys)
+	}
+	o := op{key: g.keys[key], size: g.w.valueSize, kind: opSet}
+	if g.rng.Float64() < g.w.readRatio {
+		o.kind = opGet
+	}
+	return o, true
+}
+
+func (g *generator) err() error { return nil }
+
+// zipfGenerator draws ranks from a Zipf distribution with skew theta in (0, 1),
+// using the YCSB method (Gray et al., "Quickly Generating Billion-Record
+// Synthetic Databases"): rand.Zipf only accepts skews above 1
+type zipfGenerator struct {
+	rng   *rand.Rand
+	n     uint64
+	theta float64
+	alpha float64
+	zetan float64
+	eta   float64
+}
+
+func newZipfGenerator(n uint64, theta float64, rng *rand.Rand) *zipfGenerator {
+	z := &zipfGenerator{rng: rng, n: n, theta: theta, alpha: 1 / (1 - theta)}
+	zeta2 := z.zeta(2)
+	z.zetan = z.zeta(n)
+	z.eta = (1 - math.Pow(2/float64(n), 1-theta)) / (1 - zeta2/z.zetan)
+	return z
+}
+
+func (z *zipfGenerator) zeta(n uint64) float64 {
+	sum := 0.0
+	for i := range n {
+		sum += 1 / math.Pow(float64(i+1), z.theta)
+	}
+	return sum
+}
+
+func (z *zipfGenerator) next() uint64 {
+	u := z.rng.Float64()
+	uz := u * z.zetan
+	if uz < 1 {
+		return 0
+	}
+	if uz < 1+math.Pow(0.5, z.theta) {
+		return 1
+	}
+	return uint64(float64(z.n) * math.Pow(z.eta*u-z.eta+1, z.alpha))
+}